@@ -17,6 +17,11 @@ import (
 
 //	@query.collection.format	multi
 
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				"Bearer " followed by a static token or a JWT, required if authentication is enabled
+
 func main() {
 	ctx := signals.SetupSignalHandler()
 	command, err := apiserver.NewServerCommand(ctx)
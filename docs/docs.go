@@ -35,6 +35,11 @@ const docTemplate = `{
         },
         "/api/ga4gh/tes/v1/tasks": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "list tasks",
                 "produces": [
                     "application/json"
@@ -76,6 +81,17 @@ const docTemplate = `{
                         "name": "view",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "ID",
+                            "PRIORITY"
+                        ],
+                        "type": "string",
+                        "default": "ID",
+                        "description": "query sort by, PRIORITY sorts by effective priority descending then creation time",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
                     {
                         "type": "array",
                         "items": {
@@ -97,6 +113,38 @@ const docTemplate = `{
                         "description": "query without cluster",
                         "name": "without_cluster",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "query quota held or not",
+                        "name": "quota_held",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query resubmissions of the task",
+                        "name": "retry_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "query tag key array, all tags must be matched",
+                        "name": "tag_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "query tag value array, paired with tag_key by index, empty matches any value",
+                        "name": "tag_value",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -112,6 +160,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -121,6 +175,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "create task",
                 "consumes": [
                     "application/json"
@@ -141,6 +200,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/context_task_interface_hertz_handlers.CreateTaskRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "idempotency key, the task created first with the key is returned for repeated requests",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -156,6 +221,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "401": {
+                        "description": "unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "bioos_info is different from the caller",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -167,6 +250,11 @@ const docTemplate = `{
         },
         "/api/ga4gh/tes/v1/tasks/{id}": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "get task by id",
                 "produces": [
                     "application/json"
@@ -209,6 +297,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
@@ -226,6 +320,11 @@ const docTemplate = `{
         },
         "/api/ga4gh/tes/v1/tasks/{id}:cancel": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "cancel task by id",
                 "produces": [
                     "application/json"
@@ -256,6 +355,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
@@ -273,6 +378,11 @@ const docTemplate = `{
         },
         "/api/v1/clusters": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "list clusters",
                 "produces": [
                     "application/json"
@@ -297,6 +407,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -308,6 +424,11 @@ const docTemplate = `{
         },
         "/api/v1/clusters/{id}": {
             "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "put cluster",
                 "consumes": [
                     "application/json"
@@ -350,6 +471,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -359,6 +486,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "delete cluster",
                 "produces": [
                     "application/json"
@@ -389,6 +521,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
@@ -406,6 +544,11 @@ const docTemplate = `{
         },
         "/api/v1/extra_priority": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "list extra priority on tasks",
                 "produces": [
                     "application/json"
@@ -450,6 +593,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -459,6 +608,11 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "create or update extra priority on tasks",
                 "consumes": [
                     "application/json"
@@ -518,6 +672,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -527,6 +687,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "delete extra priority on tasks",
                 "produces": [
                     "application/json"
@@ -574,6 +739,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
@@ -591,6 +762,11 @@ const docTemplate = `{
         },
         "/api/v1/quota": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "get quota",
                 "produces": [
                     "application/json"
@@ -632,6 +808,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
@@ -647,6 +829,11 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "put quota",
                 "consumes": [
                     "application/json"
@@ -682,6 +869,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -691,6 +884,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "delete quota",
                 "produces": [
                     "application/json"
@@ -732,6 +930,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
@@ -749,6 +953,11 @@ const docTemplate = `{
         },
         "/api/v1/tasks/accounts": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "list tasks accounts",
                 "produces": [
                     "application/json"
@@ -764,6 +973,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/context_task_interface_hertz_handlers.ListTasksAccountsResponse"
                         }
                     },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -773,57 +988,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/resources": {
-            "get": {
-                "description": "gather tasks resources",
+        "/api/v1/tasks/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "create tasks in one transaction, results are returned by index, in ALL_OR_NOTHING mode none is created if any task fails",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "task"
                 ],
-                "summary": "gather tasks resources",
+                "summary": "batch create tasks",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "query state array",
-                        "name": "state",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "query cluster id",
-                        "name": "cluster_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "query with cluster",
-                        "name": "with_cluster",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "query account id",
-                        "name": "account_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "query user id",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "batch create tasks request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.BatchCreateTasksRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/context_task_interface_hertz_handlers.GatherTasksResourcesResponse"
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.BatchCreateTasksResponse"
                         }
                     },
                     "400": {
@@ -832,6 +1030,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "401": {
+                        "description": "unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "bioos_info is different from the caller",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "internal system error",
                         "schema": {
@@ -841,9 +1051,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/{id}": {
-            "patch": {
-                "description": "update task by id",
+        "/api/v1/tasks/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "move every non-finished task matching the filter to CANCELING, at least one of bioos_info and tags is required",
                 "consumes": [
                     "application/json"
                 ],
@@ -853,22 +1068,15 @@ const docTemplate = `{
                 "tags": [
                     "task"
                 ],
-                "summary": "update task",
+                "summary": "bulk cancel tasks",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "update task id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "update task request",
+                        "description": "bulk cancel tasks request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/context_task_interface_hertz_handlers.UpdateTaskRequest"
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.BulkCancelTasksRequest"
                         }
                     }
                 ],
@@ -876,17 +1084,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/context_task_interface_hertz_handlers.UpdateTaskResponse"
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.BulkCancelTasksResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid param or cannot execute",
+                        "description": "invalid param",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
-                    "404": {
-                        "description": "not found",
+                    "403": {
+                        "description": "permission denied",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
@@ -900,37 +1108,707 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
-            "get": {
-                "description": "ping",
-                "produces": [
+        "/api/v1/tasks/claim": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "assign up to limit QUEUED tasks without cluster to the cluster, ordered by effective priority then creation time",
+                "consumes": [
                     "application/json"
                 ],
-                "summary": "ping",
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    }
-                }
-            }
-        },
-        "/version": {
-            "get": {
-                "description": "version",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "version",
+                "tags": [
+                    "task"
+                ],
+                "summary": "claim tasks",
+                "parameters": [
+                    {
+                        "description": "claim tasks request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.ClaimTasksRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/version.Info"
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.ClaimTasksResponse"
                         }
-                    }
-                }
-            }
-        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "cluster not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/resources": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "gather tasks resources",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "gather tasks resources",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "query state array",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query cluster id",
+                        "name": "cluster_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "query with cluster",
+                        "name": "with_cluster",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query account id",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query user id",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.GatherTasksResourcesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/watch": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "stream task state changes as Server-Sent Events, resumable by resource_version or Last-Event-ID",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "watch tasks",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "query task ids",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query account id",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query user id",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query run id",
+                        "name": "run_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resume after the resource version",
+                        "name": "resource_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "resume after the event id",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.TaskWatchEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update task by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "update task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "update task id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update task request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.UpdateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.UpdateTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param or cannot execute",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list state transitions of task in order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "list task events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.ListTaskEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/resubmit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "clone the finished task into a new QUEUED task with retry_of and attempt, resources are overridden if set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "resubmit task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "resubmitted task id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "resubmit task request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.ResubmitTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.ResubmitTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param or cannot execute",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list webhooks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "list webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter by account_id",
+                        "name": "account_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/context_webhook_interface_hertz_handlers.Webhook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/dead_letters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list webhook dead letters, latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "list webhook dead letters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "max count of dead letters, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by account_id",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by webhook_id",
+                        "name": "webhook_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/context_webhook_interface_hertz_handlers.Delivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "put webhook",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "put webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "put webhook id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "put webhook request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/context_webhook_interface_hertz_handlers.PutWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/context_webhook_interface_hertz_handlers.PutWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete webhook",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "delete webhook id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/context_webhook_interface_hertz_handlers.DeleteWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid param",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "internal system error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "ping",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "ping",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "version",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "version",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/version.Info"
+                        }
+                    }
+                }
+            }
+        }
     },
     "definitions": {
         "context_cluster_interface_hertz_handlers.Capacity": {
@@ -960,6 +1838,9 @@ const docTemplate = `{
                 "capacity": {
                     "$ref": "#/definitions/context_cluster_interface_hertz_handlers.Capacity"
                 },
+                "healthy": {
+                    "type": "boolean"
+                },
                 "heartbeat_timestamp": {
                     "type": "string"
                 },
@@ -1148,6 +2029,55 @@ const docTemplate = `{
                 }
             }
         },
+        "context_task_interface_hertz_handlers.BatchCreateTaskError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.BatchCreateTaskResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/context_task_interface_hertz_handlers.BatchCreateTaskError"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.BatchCreateTasksRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "Mode is ALL_OR_NOTHING or BEST_EFFORT, none of the tasks is created in ALL_OR_NOTHING if any fails",
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/context_task_interface_hertz_handlers.CreateTaskRequest"
+                    }
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.BatchCreateTasksResponse": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "description": "Tasks are results of tasks in the request by index",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/context_task_interface_hertz_handlers.BatchCreateTaskResult"
+                    }
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.BioosInfo": {
             "type": "object",
             "properties": {
@@ -1205,15 +2135,98 @@ const docTemplate = `{
                 }
             }
         },
+        "context_task_interface_hertz_handlers.BulkCancelBioosInfo": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "submission_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.BulkCancelTasksRequest": {
+            "type": "object",
+            "properties": {
+                "bioos_info": {
+                    "$ref": "#/definitions/context_task_interface_hertz_handlers.BulkCancelBioosInfo"
+                },
+                "state": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.BulkCancelTasksResponse": {
+            "type": "object",
+            "properties": {
+                "already_finished": {
+                    "type": "integer"
+                },
+                "canceled": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "failed_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.CancelTaskResponse": {
             "type": "object"
         },
+        "context_task_interface_hertz_handlers.ClaimTasksRequest": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.ClaimTasksResponse": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/context_task_interface_hertz_handlers.Task"
+                    }
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.CreateTaskRequest": {
             "type": "object",
             "properties": {
                 "bioos_info": {
                     "$ref": "#/definitions/context_task_interface_hertz_handlers.BioosInfo"
                 },
+                "callback_url": {
+                    "description": "CallbackURL is notified when the task is finished",
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -1223,12 +2236,20 @@ const docTemplate = `{
                         "$ref": "#/definitions/context_task_interface_hertz_handlers.Executor"
                     }
                 },
+                "idempotency_key": {
+                    "description": "IdempotencyKey makes repeated creations of the same owner return the task created first",
+                    "type": "string"
+                },
                 "inputs": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/context_task_interface_hertz_handlers.Input"
                     }
                 },
+                "max_retries": {
+                    "description": "MaxRetries is how many times SYSTEM_ERROR is retried by requeueing the task,\nthe policy of the account or server is applied if it is not set",
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1281,6 +2302,9 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "ignore_error": {
+                    "type": "boolean"
+                },
                 "image": {
                     "type": "string"
                 },
@@ -1307,7 +2331,16 @@ const docTemplate = `{
                 "executor_id": {
                     "type": "string"
                 },
-                "start_time": {
+                "exit_code": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "stderr": {
+                    "type": "string"
+                },
+                "stdout": {
                     "type": "string"
                 }
             }
@@ -1364,9 +2397,16 @@ const docTemplate = `{
         "context_task_interface_hertz_handlers.GetTaskResponse": {
             "type": "object",
             "properties": {
+                "attempt": {
+                    "description": "Attempt is 1 for the first submission, and increased by each resubmission",
+                    "type": "integer"
+                },
                 "bioos_info": {
                     "$ref": "#/definitions/context_task_interface_hertz_handlers.BioosInfo"
                 },
+                "callback_url": {
+                    "type": "string"
+                },
                 "cluster_id": {
                     "type": "string"
                 },
@@ -1376,6 +2416,10 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "effective_priority": {
+                    "description": "EffectivePriority is priority_value plus extra priorities applied to the task",
+                    "type": "integer"
+                },
                 "executors": {
                     "type": "array",
                     "items": {
@@ -1397,6 +2441,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/context_task_interface_hertz_handlers.TaskLog"
                     }
                 },
+                "max_retries": {
+                    "description": "MaxRetries is how many times SYSTEM_ERROR is retried by requeueing the task",
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1409,9 +2457,20 @@ const docTemplate = `{
                 "priority_value": {
                     "type": "integer"
                 },
+                "quota_held": {
+                    "type": "boolean"
+                },
                 "resources": {
                     "$ref": "#/definitions/context_task_interface_hertz_handlers.Resources"
                 },
+                "retries": {
+                    "description": "Retries is how many times the task is requeued after SYSTEM_ERROR",
+                    "type": "integer"
+                },
+                "retry_of": {
+                    "description": "RetryOf is id of the task resubmitted as this one",
+                    "type": "string"
+                },
                 "state": {
                     "type": "string"
                 },
@@ -1452,6 +2511,17 @@ const docTemplate = `{
                 }
             }
         },
+        "context_task_interface_hertz_handlers.ListTaskEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/context_task_interface_hertz_handlers.TaskEvent"
+                    }
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.ListTasksAccountsResponse": {
             "type": "object",
             "properties": {
@@ -1497,9 +2567,32 @@ const docTemplate = `{
                 }
             }
         },
+        "context_task_interface_hertz_handlers.OutputFileLog": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.Resources": {
             "type": "object",
             "properties": {
+                "backend_parameters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "backend_parameters_strict": {
+                    "type": "boolean"
+                },
                 "boot_disk_gb": {
                     "type": "integer"
                 },
@@ -1512,18 +2605,76 @@ const docTemplate = `{
                 "gpu": {
                     "$ref": "#/definitions/context_task_interface_hertz_handlers.GPUResource"
                 },
+                "preemptible": {
+                    "type": "boolean"
+                },
+                "ram_gb": {
+                    "description": "nolint",
+                    "type": "number"
+                },
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.ResubmitResources": {
+            "type": "object",
+            "properties": {
+                "boot_disk_gb": {
+                    "type": "integer"
+                },
+                "cpu_cores": {
+                    "type": "integer"
+                },
+                "disk_gb": {
+                    "type": "number"
+                },
+                "preemptible": {
+                    "type": "boolean"
+                },
                 "ram_gb": {
                     "description": "nolint",
                     "type": "number"
                 }
             }
         },
+        "context_task_interface_hertz_handlers.ResubmitTaskRequest": {
+            "type": "object",
+            "properties": {
+                "resources": {
+                    "description": "Resources overrides resources of the finished task if set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/context_task_interface_hertz_handlers.ResubmitResources"
+                        }
+                    ]
+                }
+            }
+        },
+        "context_task_interface_hertz_handlers.ResubmitTaskResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.Task": {
             "type": "object",
             "properties": {
+                "attempt": {
+                    "description": "Attempt is 1 for the first submission, and increased by each resubmission",
+                    "type": "integer"
+                },
                 "bioos_info": {
                     "$ref": "#/definitions/context_task_interface_hertz_handlers.BioosInfo"
                 },
+                "callback_url": {
+                    "type": "string"
+                },
                 "cluster_id": {
                     "type": "string"
                 },
@@ -1533,6 +2684,10 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "effective_priority": {
+                    "description": "EffectivePriority is priority_value plus extra priorities applied to the task",
+                    "type": "integer"
+                },
                 "executors": {
                     "type": "array",
                     "items": {
@@ -1554,6 +2709,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/context_task_interface_hertz_handlers.TaskLog"
                     }
                 },
+                "max_retries": {
+                    "description": "MaxRetries is how many times SYSTEM_ERROR is retried by requeueing the task",
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1566,9 +2725,20 @@ const docTemplate = `{
                 "priority_value": {
                     "type": "integer"
                 },
+                "quota_held": {
+                    "type": "boolean"
+                },
                 "resources": {
                     "$ref": "#/definitions/context_task_interface_hertz_handlers.Resources"
                 },
+                "retries": {
+                    "description": "Retries is how many times the task is requeued after SYSTEM_ERROR",
+                    "type": "integer"
+                },
+                "retry_of": {
+                    "description": "RetryOf is id of the task resubmitted as this one",
+                    "type": "string"
+                },
                 "state": {
                     "type": "string"
                 },
@@ -1586,6 +2756,26 @@ const docTemplate = `{
                 }
             }
         },
+        "context_task_interface_hertz_handlers.TaskEvent": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "type": "string"
+                },
+                "previous_state": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.TaskLog": {
             "type": "object",
             "properties": {
@@ -1604,6 +2794,12 @@ const docTemplate = `{
                         }
                     }
                 },
+                "outputs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/context_task_interface_hertz_handlers.OutputFileLog"
+                    }
+                },
                 "start_time": {
                     "type": "string"
                 },
@@ -1615,6 +2811,32 @@ const docTemplate = `{
                 }
             }
         },
+        "context_task_interface_hertz_handlers.TaskWatchEvent": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "type": "string"
+                },
+                "previous_state": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "resource_version": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
         "context_task_interface_hertz_handlers.UpdateTaskRequest": {
             "type": "object",
             "properties": {
@@ -1635,6 +2857,102 @@ const docTemplate = `{
         "context_task_interface_hertz_handlers.UpdateTaskResponse": {
             "type": "object"
         },
+        "context_webhook_interface_hertz_handlers.DeleteWebhookResponse": {
+            "type": "object"
+        },
+        "context_webhook_interface_hertz_handlers.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "creation_time": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/context_webhook_interface_hertz_handlers.Event"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_time": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "context_webhook_interface_hertz_handlers.Event": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "submission_id": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "context_webhook_interface_hertz_handlers.PutWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret signs the payload, the default secret of the server is used if empty",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "context_webhook_interface_hertz_handlers.PutWebhookResponse": {
+            "type": "object"
+        },
+        "context_webhook_interface_hertz_handlers.Webhook": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "errors.AppError": {
             "type": "object",
             "properties": {
@@ -1646,6 +2964,20 @@ const docTemplate = `{
                 }
             }
         },
+        "serviceinfo.GPUInfo": {
+            "type": "object",
+            "properties": {
+                "isInteger": {
+                    "type": "boolean"
+                },
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "serviceinfo.OrganizationInfo": {
             "type": "object",
             "properties": {
@@ -1657,6 +2989,34 @@ const docTemplate = `{
                 }
             }
         },
+        "serviceinfo.RangeInfo": {
+            "type": "object",
+            "properties": {
+                "isInteger": {
+                    "type": "boolean"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                }
+            }
+        },
+        "serviceinfo.ResourcesInfo": {
+            "type": "object",
+            "properties": {
+                "bootDiskGB": {
+                    "$ref": "#/definitions/serviceinfo.RangeInfo"
+                },
+                "diskGB": {
+                    "$ref": "#/definitions/serviceinfo.RangeInfo"
+                },
+                "gpu": {
+                    "$ref": "#/definitions/serviceinfo.GPUInfo"
+                }
+            }
+        },
         "serviceinfo.ServiceInfo": {
             "type": "object",
             "properties": {
@@ -1684,12 +3044,21 @@ const docTemplate = `{
                 "organization": {
                     "$ref": "#/definitions/serviceinfo.OrganizationInfo"
                 },
+                "resources": {
+                    "$ref": "#/definitions/serviceinfo.ResourcesInfo"
+                },
                 "storage": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "tesResources_backend_parameters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "type": {
                     "$ref": "#/definitions/serviceinfo.TypeInfo"
                 },
@@ -1747,6 +3116,14 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "\"Bearer \" followed by a static token or a JWT, required if authentication is enabled",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -15,15 +15,15 @@ import (
 	swaggerfiles "github.com/swaggo/files"
 
 	"github.com/GBA-BI/tes-api/internal/apiserver/middlewares/hertz"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	"github.com/GBA-BI/tes-api/pkg/consts"
 	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	appserver "github.com/GBA-BI/tes-api/pkg/server"
-	"github.com/GBA-BI/tes-api/pkg/serviceinfo"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 	"github.com/GBA-BI/tes-api/pkg/version"
 )
 
-func setupHTTPServer(opts *appserver.HTTPOptions, registers ...appserver.RouteRegister) *server.Hertz {
+func setupHTTPServer(opts *appserver.HTTPOptions, authenticator auth.Authenticator, serviceInfo app.HandlerFunc, registers ...appserver.RouteRegister) *server.Hertz {
 	httpOptions := []config.Option{
 		server.WithHostPorts(fmt.Sprintf(":%d", opts.Port)),
 		server.WithMaxRequestBodySize(opts.MaxRequestBodySize),
@@ -31,15 +31,15 @@ func setupHTTPServer(opts *appserver.HTTPOptions, registers ...appserver.RouteRe
 	}
 
 	httpServer := server.Default(httpOptions...)
-	setupMiddlewares(httpServer)
-	setupRouter(httpServer)
+	setupMiddlewares(httpServer, authenticator)
+	setupRouter(httpServer, serviceInfo)
 	for _, r := range registers {
 		r.AddRoute(httpServer)
 	}
 	return httpServer
 }
 
-func setupMiddlewares(h *server.Hertz) {
+func setupMiddlewares(h *server.Hertz, authenticator auth.Authenticator) {
 	h.Use(
 		requestid.New(
 			requestid.WithGenerator(func(_ context.Context, _ *app.RequestContext) string {
@@ -48,14 +48,19 @@ func setupMiddlewares(h *server.Hertz) {
 			// set custom header for request id
 			requestid.WithCustomHeaderStrKey(consts.XRequestIDKey),
 		),
+		hertz.RequestIDContext(),
 		hertz.Logger(),
 	)
+	// requests are not authenticated if authentication is disabled
+	if authenticator != nil {
+		h.Use(hertz.Authentication(authenticator))
+	}
 }
 
-func setupRouter(h *server.Hertz) {
+func setupRouter(h *server.Hertz, serviceInfo app.HandlerFunc) {
 	h.GET("/ping", PingHandler)
 	h.GET("/version", VersionHandler)
-	h.GET(consts.Ga4ghAPIPrefix+"/service-info", ServiceInfoHandler)
+	h.GET(consts.Ga4ghAPIPrefix+"/service-info", serviceInfo)
 	url := swagger.URL("/swagger/doc.json") // The url pointing to API definition
 	h.GET("/swagger/*any", swagger.WrapHandler(swaggerfiles.Handler, url))
 
@@ -87,14 +92,3 @@ func PingHandler(_ context.Context, ctx *app.RequestContext) {
 func VersionHandler(_ context.Context, ctx *app.RequestContext) {
 	ctx.JSON(http.StatusOK, version.Get())
 }
-
-// ServiceInfoHandler ga4gh serviceInfo handler
-//
-//	@Summary		service-info
-//	@Description	ga4gh service-info
-//	@Produce		application/json
-//	@Router			/api/ga4gh/tes/v1/service-info [get]
-//	@Success		200	{object}	serviceinfo.ServiceInfo
-func ServiceInfoHandler(_ context.Context, ctx *app.RequestContext) {
-	ctx.JSON(http.StatusOK, serviceinfo.DefaultServiceInfo)
-}
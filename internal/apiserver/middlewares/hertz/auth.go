@@ -0,0 +1,56 @@
+package hertz
+
+import (
+	"bytes"
+	"context"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"github.com/GBA-BI/tes-api/pkg/auth"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+const identityKey = "identity"
+
+var bearerPrefix = []byte("Bearer ")
+
+// Authentication authenticates bearer tokens of api requests except service-info, and passes
+// the identity of the caller to context.Context of subsequent handlers.
+func Authentication(authenticator auth.Authenticator) app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		path := ctx.Request.Path()
+		if !bytes.HasPrefix(path, []byte(consts.Ga4ghAPIPrefix)) && !bytes.HasPrefix(path, []byte(consts.OtherAPIPrefix)) ||
+			string(path) == consts.Ga4ghAPIPrefix+"/service-info" {
+			return
+		}
+
+		header := ctx.Request.Header.Peek(consts.AuthorizationKey)
+		if !bytes.HasPrefix(header, bearerPrefix) {
+			utils.WriteHertzErrorResponse(ctx, apperrors.NewUnauthenticatedError("bearer token is required"))
+			ctx.Abort()
+			return
+		}
+		identity, err := authenticator.Authenticate(c, string(bytes.TrimSpace(header[len(bearerPrefix):])))
+		if err != nil {
+			applog.CtxInfow(c, "failed to authenticate", "err", err)
+			utils.WriteHertzErrorResponse(ctx, apperrors.NewUnauthenticatedError("invalid bearer token"))
+			ctx.Abort()
+			return
+		}
+		ctx.Set(identityKey, identity)
+		ctx.Next(auth.WithIdentity(c, identity))
+	}
+}
+
+// getIdentity returns the identity set by Authentication, or nil if the request is not authenticated
+func getIdentity(ctx *app.RequestContext) *auth.Identity {
+	value, ok := ctx.Get(identityKey)
+	if !ok {
+		return nil
+	}
+	identity, _ := value.(*auth.Identity)
+	return identity
+}
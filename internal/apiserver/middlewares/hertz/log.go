@@ -35,14 +35,22 @@ func Logger() app.HandlerFunc {
 		statusCode := ctx.Response.StatusCode()
 		clientIP := ctx.ClientIP()
 
-		applog.CtxInfow(c, "process request",
+		keysAndValues := []interface{}{
 			"status_code", statusCode,
 			"req_action", extractAction(reqMethod, reqURI),
 			"latency_time", latencyTime,
 			"client_ip", clientIP,
 			"req_method", reqMethod,
 			"req_uri", reqURI,
-			"request_id", requestID)
+			"request_id", requestID,
+		}
+		if identity := getIdentity(ctx); identity != nil {
+			keysAndValues = append(keysAndValues,
+				"subject", identity.Subject,
+				"account_id", identity.AccountID,
+				"user_id", identity.UserID)
+		}
+		applog.CtxInfow(c, "process request", keysAndValues...)
 	}
 }
 
@@ -69,10 +77,22 @@ func extractAction(reqMethod, reqURI string) string {
 		return "CancelTask"
 	case updateTaskRegexp.MatchString(path) && reqMethod == http.MethodPatch:
 		return "UpdateTask"
+	case claimTasksRegexp.MatchString(path) && reqMethod == http.MethodPost:
+		return "ClaimTasks"
+	case resubmitTaskRegexp.MatchString(path) && reqMethod == http.MethodPost:
+		return "ResubmitTask"
+	case batchCreateTasksRegexp.MatchString(path) && reqMethod == http.MethodPost:
+		return "BatchCreateTasks"
+	case bulkCancelTasksRegexp.MatchString(path) && reqMethod == http.MethodPost:
+		return "BulkCancelTasks"
 	case gatherTasksResourcesRegexp.MatchString(path) && reqMethod == http.MethodGet:
 		return "GatherTasksResources"
 	case listTasksAccountsRegexp.MatchString(path) && reqMethod == http.MethodGet:
 		return "ListTasksAccounts"
+	case watchTasksRegexp.MatchString(path) && reqMethod == http.MethodGet:
+		return "WatchTasks"
+	case listTaskEventsRegexp.MatchString(path) && reqMethod == http.MethodGet:
+		return "ListTaskEvents"
 	case putDeleteClusterRegexp.MatchString(path):
 		switch reqMethod {
 		case http.MethodPut:
@@ -100,20 +120,40 @@ func extractAction(reqMethod, reqURI string) string {
 		case http.MethodDelete:
 			return "DeleteExtraPriority"
 		}
+	case listWebhookDeadLettersRegexp.MatchString(path) && reqMethod == http.MethodGet:
+		return "ListWebhookDeadLetters"
+	case putDeleteWebhookRegexp.MatchString(path):
+		switch reqMethod {
+		case http.MethodPut:
+			return "PutWebhook"
+		case http.MethodDelete:
+			return "DeleteWebhook"
+		}
+	case listWebhooksRegexp.MatchString(path) && reqMethod == http.MethodGet:
+		return "ListWebhooks"
 	}
 	return "<invalid action>"
 }
 
 var (
-	serviceInfoRegexp          = regexp.MustCompile(fmt.Sprintf("^%s/service-info$", consts.Ga4ghAPIPrefix))
-	listTasksCreateTaskRegexp  = regexp.MustCompile(fmt.Sprintf("^%s/tasks$", consts.Ga4ghAPIPrefix))
-	getTaskRegexp              = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+$", consts.Ga4ghAPIPrefix))
-	cancelTaskRegexp           = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+:cancel$", consts.Ga4ghAPIPrefix))
-	updateTaskRegexp           = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+$", consts.OtherAPIPrefix))
-	gatherTasksResourcesRegexp = regexp.MustCompile(fmt.Sprintf("^%s/tasks/resources$", consts.OtherAPIPrefix))
-	listTasksAccountsRegexp    = regexp.MustCompile(fmt.Sprintf("^%s/tasks/accounts$", consts.OtherAPIPrefix))
-	putDeleteClusterRegexp     = regexp.MustCompile(fmt.Sprintf("^%s/clusters/.+", consts.OtherAPIPrefix))
-	listClustersRegexp         = regexp.MustCompile(fmt.Sprintf("^%s/clusters$", consts.OtherAPIPrefix))
-	quotaRegexp                = regexp.MustCompile(fmt.Sprintf("^%s/quota$", consts.OtherAPIPrefix))
-	extraPriorityRegexp        = regexp.MustCompile(fmt.Sprintf("^%s/extra_priority$", consts.OtherAPIPrefix))
+	serviceInfoRegexp            = regexp.MustCompile(fmt.Sprintf("^%s/service-info$", consts.Ga4ghAPIPrefix))
+	listTasksCreateTaskRegexp    = regexp.MustCompile(fmt.Sprintf("^%s/tasks$", consts.Ga4ghAPIPrefix))
+	getTaskRegexp                = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+$", consts.Ga4ghAPIPrefix))
+	cancelTaskRegexp             = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+:cancel$", consts.Ga4ghAPIPrefix))
+	updateTaskRegexp             = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+$", consts.OtherAPIPrefix))
+	claimTasksRegexp             = regexp.MustCompile(fmt.Sprintf("^%s/tasks/claim$", consts.OtherAPIPrefix))
+	resubmitTaskRegexp           = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+/resubmit$", consts.OtherAPIPrefix))
+	batchCreateTasksRegexp       = regexp.MustCompile(fmt.Sprintf("^%s/tasks/batch$", consts.OtherAPIPrefix))
+	bulkCancelTasksRegexp        = regexp.MustCompile(fmt.Sprintf("^%s/tasks/cancel$", consts.OtherAPIPrefix))
+	gatherTasksResourcesRegexp   = regexp.MustCompile(fmt.Sprintf("^%s/tasks/resources$", consts.OtherAPIPrefix))
+	listTasksAccountsRegexp      = regexp.MustCompile(fmt.Sprintf("^%s/tasks/accounts$", consts.OtherAPIPrefix))
+	watchTasksRegexp             = regexp.MustCompile(fmt.Sprintf("^%s/tasks/watch$", consts.OtherAPIPrefix))
+	listTaskEventsRegexp         = regexp.MustCompile(fmt.Sprintf("^%s/tasks/task-[a-z0-9]+/events$", consts.OtherAPIPrefix))
+	putDeleteClusterRegexp       = regexp.MustCompile(fmt.Sprintf("^%s/clusters/.+", consts.OtherAPIPrefix))
+	listClustersRegexp           = regexp.MustCompile(fmt.Sprintf("^%s/clusters$", consts.OtherAPIPrefix))
+	quotaRegexp                  = regexp.MustCompile(fmt.Sprintf("^%s/quota$", consts.OtherAPIPrefix))
+	extraPriorityRegexp          = regexp.MustCompile(fmt.Sprintf("^%s/extra_priority$", consts.OtherAPIPrefix))
+	listWebhookDeadLettersRegexp = regexp.MustCompile(fmt.Sprintf("^%s/webhooks/dead_letters$", consts.OtherAPIPrefix))
+	putDeleteWebhookRegexp       = regexp.MustCompile(fmt.Sprintf("^%s/webhooks/.+", consts.OtherAPIPrefix))
+	listWebhooksRegexp           = regexp.MustCompile(fmt.Sprintf("^%s/webhooks$", consts.OtherAPIPrefix))
 )
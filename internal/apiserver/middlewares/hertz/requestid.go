@@ -0,0 +1,18 @@
+package hertz
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/hertz-contrib/requestid"
+
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+// RequestIDContext passes the request id to context.Context of subsequent handlers,
+// so that it can be recorded out of the interface layer.
+func RequestIDContext() app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		ctx.Next(utils.WithRequestID(c, requestid.Get(ctx)))
+	}
+}
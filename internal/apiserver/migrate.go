@@ -0,0 +1,129 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/GBA-BI/tes-api/internal/apiserver/options"
+	"github.com/GBA-BI/tes-api/internal/migrations"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+	"github.com/GBA-BI/tes-api/pkg/viper"
+)
+
+func newMigrateCommand(ctx context.Context, opts *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "migrate",
+		Short:        "Migrate the db schema",
+		Long:         "Migrate the db schema, the api server refuses to serve if the schema is behind",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Log.Validate(); err != nil {
+				return err
+			}
+			if err := opts.DB.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Migrate.Validate(); err != nil {
+				return err
+			}
+			applog.RegisterLogger(opts.Log)
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			applog.Sync()
+		},
+	}
+	opts.Log.AddFlags(cmd.PersistentFlags())
+	opts.DB.AddFlags(cmd.PersistentFlags())
+	opts.Migrate.AddFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().AddFlag(pflag.Lookup(viper.ConfigFlagName))
+
+	var upTo int64
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			migrator, err := newMigrator(opts)
+			if err != nil {
+				return err
+			}
+			return migrator.Up(ctx, upTo)
+		},
+	}
+	upCmd.Flags().Int64Var(&upTo, "to", upTo, "version to migrate up to, 0 means the latest")
+
+	var downTo int64
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert applied migrations after a version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			migrator, err := newMigrator(opts)
+			if err != nil {
+				return err
+			}
+			return migrator.Down(ctx, downTo)
+		},
+	}
+	downCmd.Flags().Int64Var(&downTo, "to", downTo, "version to migrate down to, 0 reverts all migrations")
+	_ = downCmd.MarkFlagRequired("to")
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			migrator, err := newMigrator(opts)
+			if err != nil {
+				return err
+			}
+			statuses, err := migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+			for _, status := range statuses {
+				applied := "pending"
+				if status.Record != nil {
+					applied = status.Record.AppliedTime.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, applied)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.AddCommand(upCmd, downCmd, statusCmd)
+	return cmd
+}
+
+func newMigrator(opts *options.Options) (*migrate.Migrator, error) {
+	gormDB, err := opts.DB.GetGORMInstance()
+	if err != nil {
+		return nil, err
+	}
+	return migrations.NewMigrator(gormDB, opts.Migrate)
+}
+
+// checkSchema refuses to serve with a schema behind migrations, unless it is migrated automatically
+func checkSchema(ctx context.Context, opts *options.Options) error {
+	migrator, err := newMigrator(opts)
+	if err != nil {
+		return err
+	}
+	if opts.Migrate.Auto {
+		return migrator.Up(ctx, 0)
+	}
+	if err = migrator.Check(ctx); err != nil {
+		return fmt.Errorf("%w, run migrate up before serving", err)
+	}
+	return nil
+}
@@ -5,26 +5,53 @@ import (
 
 	"github.com/GBA-BI/tes-api/pkg/log"
 
+	"github.com/GBA-BI/tes-api/internal/context/cluster/infra/reconcile"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/admission"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/idempotency"
 	"github.com/GBA-BI/tes-api/internal/context/task/infra/normalize"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/priority"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/retention"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/infra/dispatch"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	"github.com/GBA-BI/tes-api/pkg/db"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
 	"github.com/GBA-BI/tes-api/pkg/server"
+	"github.com/GBA-BI/tes-api/pkg/serviceinfo"
 )
 
 // Options ...
 type Options struct {
-	Log       *log.Options       `mapstructure:"log"`
-	Server    *server.Options    `mapstructure:"server"`
-	DB        *db.Options        `mapstructure:"db"`
-	Normalize *normalize.Options `mapstructure:"normalize"`
+	Log         *log.Options         `mapstructure:"log"`
+	Server      *server.Options      `mapstructure:"server"`
+	Auth        *auth.Options        `mapstructure:"auth"`
+	DB          *db.Options          `mapstructure:"db"`
+	Migrate     *migrate.Options     `mapstructure:"migrate"`
+	Normalize   *normalize.Options   `mapstructure:"normalize"`
+	ServiceInfo *serviceinfo.Options `mapstructure:"serviceInfo"`
+	Admission   *admission.Options   `mapstructure:"admission"`
+	Reconcile   *reconcile.Options   `mapstructure:"reconcile"`
+	Priority    *priority.Options    `mapstructure:"priority"`
+	Webhook     *dispatch.Options    `mapstructure:"webhook"`
+	Idempotency *idempotency.Options `mapstructure:"idempotency"`
+	Retention   *retention.Options   `mapstructure:"retention"`
 }
 
 // NewOptions ...
 func NewOptions() *Options {
 	return &Options{
-		Log:       log.NewOptions(),
-		Server:    server.NewOptions(),
-		DB:        db.NewOptions(),
-		Normalize: normalize.NewOptions(),
+		Log:         log.NewOptions(),
+		Server:      server.NewOptions(),
+		Auth:        auth.NewOptions(),
+		DB:          db.NewOptions(),
+		Migrate:     migrate.NewOptions(),
+		Normalize:   normalize.NewOptions(),
+		ServiceInfo: serviceinfo.NewOptions(),
+		Admission:   admission.NewOptions(),
+		Reconcile:   reconcile.NewOptions(),
+		Priority:    priority.NewOptions(),
+		Webhook:     dispatch.NewOptions(),
+		Idempotency: idempotency.NewOptions(),
+		Retention:   retention.NewOptions(),
 	}
 }
 
@@ -36,12 +63,39 @@ func (o *Options) Validate() error {
 	if err := o.Server.Validate(); err != nil {
 		return err
 	}
+	if err := o.Auth.Validate(); err != nil {
+		return err
+	}
 	if err := o.DB.Validate(); err != nil {
 		return err
 	}
+	if err := o.Migrate.Validate(); err != nil {
+		return err
+	}
 	if err := o.Normalize.Validate(); err != nil {
 		return err
 	}
+	if err := o.ServiceInfo.Validate(); err != nil {
+		return err
+	}
+	if err := o.Admission.Validate(); err != nil {
+		return err
+	}
+	if err := o.Reconcile.Validate(); err != nil {
+		return err
+	}
+	if err := o.Priority.Validate(); err != nil {
+		return err
+	}
+	if err := o.Webhook.Validate(); err != nil {
+		return err
+	}
+	if err := o.Idempotency.Validate(); err != nil {
+		return err
+	}
+	if err := o.Retention.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -49,6 +103,15 @@ func (o *Options) Validate() error {
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	o.Log.AddFlags(fs)
 	o.Server.AddFlags(fs)
+	o.Auth.AddFlags(fs)
 	o.DB.AddFlags(fs)
+	o.Migrate.AddFlags(fs)
 	o.Normalize.AddFlags(fs)
+	o.ServiceInfo.AddFlags(fs)
+	o.Admission.AddFlags(fs)
+	o.Reconcile.AddFlags(fs)
+	o.Priority.AddFlags(fs)
+	o.Webhook.AddFlags(fs)
+	o.Idempotency.AddFlags(fs)
+	o.Retention.AddFlags(fs)
 }
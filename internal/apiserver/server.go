@@ -4,6 +4,7 @@ import (
 	"context"
 
 	applog "github.com/GBA-BI/tes-api/pkg/log"
+	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
@@ -17,6 +18,9 @@ import (
 	quotahertz "github.com/GBA-BI/tes-api/internal/context/quota/interface/hertz"
 	taskapp "github.com/GBA-BI/tes-api/internal/context/task/application"
 	taskhertz "github.com/GBA-BI/tes-api/internal/context/task/interface/hertz"
+	webhookapp "github.com/GBA-BI/tes-api/internal/context/webhook/application"
+	webhookhertz "github.com/GBA-BI/tes-api/internal/context/webhook/interface/hertz"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	"github.com/GBA-BI/tes-api/pkg/version"
 	"github.com/GBA-BI/tes-api/pkg/viper"
 )
@@ -53,11 +57,14 @@ func newServerCommand(ctx context.Context, opts *options.Options) *cobra.Command
 func run(ctx context.Context, opts *options.Options) (err error) {
 	applog.Infow("run veTES api server")
 
+	if err = checkSchema(ctx, opts); err != nil {
+		return err
+	}
 	taskService, err := taskapp.NewTaskService(ctx, opts)
 	if err != nil {
 		return err
 	}
-	clusterService, err := clusterapp.NewClusterService(ctx, opts)
+	clusterService, err := clusterapp.NewClusterService(ctx, opts, taskService.TaskReclaimer)
 	if err != nil {
 		return err
 	}
@@ -65,18 +72,38 @@ func run(ctx context.Context, opts *options.Options) (err error) {
 	if err != nil {
 		return err
 	}
-	extraPriorityService, err := extrapriorityapp.NewExtraPriorityService(ctx, opts)
+	extraPriorityService, err := extrapriorityapp.NewExtraPriorityService(ctx, opts, taskService.PriorityRefresher)
+	if err != nil {
+		return err
+	}
+	webhookService, err := webhookapp.NewWebhookService(ctx, opts, taskService.NotificationSource)
 	if err != nil {
 		return err
 	}
 
-	httpServer := setupHTTPServer(opts.Server.HTTP,
+	authenticator, err := auth.NewAuthenticator(opts.Auth)
+	if err != nil {
+		return err
+	}
+
+	serviceInfo := newServiceInfo(opts)
+	httpServer := setupHTTPServer(opts.Server.HTTP, authenticator,
+		func(c context.Context, ctx *app.RequestContext) {
+			ServiceInfoHandler(c, ctx, serviceInfo, clusterService.ClusterQueries.List)
+		},
 		taskhertz.NewRouterRegister(taskService),
 		clusterhertz.NewRouterRegister(clusterService),
 		quotahertz.NewRouterRegister(quotaService),
 		extrapriorityhertz.NewRouterRegister(extraPriorityService),
+		webhookhertz.NewRouterRegister(webhookService),
 	)
 
+	go clusterService.Reconciler.Run(ctx)
+	go webhookService.Dispatcher.Run(ctx)
+	go webhookService.Retainer.Run(ctx)
+	go taskService.Retainer.Run(ctx)
+	go taskService.PriorityReconciler.Run(ctx)
+
 	httpServer.Spin()
 	return nil
 }
@@ -89,6 +116,7 @@ func NewServerCommand(ctx context.Context) (*cobra.Command, error) {
 	opts.AddFlags(cmd.Flags())
 	version.AddFlags(cmd.Flags())
 	cmd.Flags().AddFlag(pflag.Lookup(viper.ConfigFlagName))
+	cmd.AddCommand(newMigrateCommand(ctx, opts))
 	if err := viper.LoadConfig(opts); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,84 @@
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"github.com/GBA-BI/tes-api/internal/apiserver/options"
+	clusterquery "github.com/GBA-BI/tes-api/internal/context/cluster/application/query"
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+	"github.com/GBA-BI/tes-api/pkg/serviceinfo"
+)
+
+// newServiceInfo builds the static part of service-info from options
+func newServiceInfo(opts *options.Options) *serviceinfo.ServiceInfo {
+	res := serviceinfo.DefaultServiceInfo
+	res.Storage = opts.ServiceInfo.Storage
+	res.TESResourcesBackendParameters = opts.Normalize.BackendParameters
+	res.Resources = &serviceinfo.ResourcesInfo{
+		GPU: &serviceinfo.GPUInfo{},
+	}
+	if opts.Normalize.DiskGB.Enable {
+		res.Resources.DiskGB = &serviceinfo.RangeInfo{
+			Min:       opts.Normalize.DiskGB.Min,
+			Max:       opts.Normalize.DiskGB.Max,
+			IsInteger: opts.Normalize.DiskGB.IsInteger,
+		}
+	}
+	if opts.Normalize.BootDiskGB.Enable {
+		res.Resources.BootDiskGB = &serviceinfo.RangeInfo{
+			Min:       float64(opts.Normalize.BootDiskGB.Min),
+			Max:       float64(opts.Normalize.BootDiskGB.Max),
+			IsInteger: true,
+		}
+	}
+	if opts.Normalize.GPU.Enable {
+		res.Resources.GPU.IsInteger = opts.Normalize.GPU.IsInteger
+	}
+	return &res
+}
+
+// ServiceInfoHandler ga4gh serviceInfo handler
+//
+//	@Summary		service-info
+//	@Description	ga4gh service-info
+//	@Produce		application/json
+//	@Router			/api/ga4gh/tes/v1/service-info [get]
+//	@Success		200	{object}	serviceinfo.ServiceInfo
+func ServiceInfoHandler(c context.Context, ctx *app.RequestContext, baseInfo *serviceinfo.ServiceInfo, listClusters clusterquery.ListHandler) {
+	clusters, err := listClusters.Handle(c, &clusterquery.ListQuery{})
+	if err != nil {
+		// gpu types are left empty, the static part of service-info is still served
+		applog.CtxErrorw(c, "failed to list clusters for gpu types", "err", err)
+	}
+
+	res := *baseInfo
+	resources := *baseInfo.Resources
+	gpu := *baseInfo.Resources.GPU
+	gpu.Types = gatherGPUTypes(clusters)
+	resources.GPU = &gpu
+	res.Resources = &resources
+	ctx.JSON(http.StatusOK, res)
+}
+
+// gatherGPUTypes returns the sorted gpu types offered by clusters
+func gatherGPUTypes(clusters []*clusterquery.Cluster) []string {
+	typeSet := make(map[string]struct{})
+	for _, cluster := range clusters {
+		if cluster == nil || cluster.Capacity == nil || cluster.Capacity.GPUCapacity == nil {
+			continue
+		}
+		for gpuType := range cluster.Capacity.GPUCapacity.GPU {
+			typeSet[gpuType] = struct{}{}
+		}
+	}
+	res := make([]string, 0, len(typeSet))
+	for gpuType := range typeSet {
+		res = append(res, gpuType)
+	}
+	sort.Strings(res)
+	return res
+}
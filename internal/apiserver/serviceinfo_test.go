@@ -0,0 +1,108 @@
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/apiserver/options"
+	clusterquery "github.com/GBA-BI/tes-api/internal/context/cluster/application/query"
+	"github.com/GBA-BI/tes-api/pkg/serviceinfo"
+)
+
+func TestNewServiceInfo(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name         string
+		modify       func(opts *options.Options)
+		resourcesExp *serviceinfo.ResourcesInfo
+	}{
+		{
+			name: "normalizing disabled",
+			modify: func(opts *options.Options) {
+				opts.Normalize.DiskGB.Enable = false
+				opts.Normalize.BootDiskGB.Enable = false
+				opts.Normalize.GPU.Enable = false
+			},
+			resourcesExp: &serviceinfo.ResourcesInfo{GPU: &serviceinfo.GPUInfo{}},
+		},
+		{
+			name: "normalizing enabled",
+			modify: func(opts *options.Options) {
+				opts.Normalize.DiskGB.Enable = true
+				opts.Normalize.DiskGB.Min = 10
+				opts.Normalize.DiskGB.Max = 100
+				opts.Normalize.DiskGB.IsInteger = true
+				opts.Normalize.BootDiskGB.Enable = true
+				opts.Normalize.BootDiskGB.Min = 20
+				opts.Normalize.BootDiskGB.Max = 50
+				opts.Normalize.GPU.Enable = true
+				opts.Normalize.GPU.IsInteger = true
+			},
+			resourcesExp: &serviceinfo.ResourcesInfo{
+				DiskGB:     &serviceinfo.RangeInfo{Min: 10, Max: 100, IsInteger: true},
+				BootDiskGB: &serviceinfo.RangeInfo{Min: 20, Max: 50, IsInteger: true},
+				GPU:        &serviceinfo.GPUInfo{IsInteger: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := options.NewOptions()
+			opts.ServiceInfo.Storage = []string{"s3://"}
+			opts.Normalize.BackendParameters = []string{"VmSize"}
+			test.modify(opts)
+
+			res := newServiceInfo(opts)
+			g.Expect(res.ID).To(gomega.Equal(serviceinfo.DefaultServiceInfo.ID))
+			g.Expect(res.Storage).To(gomega.Equal([]string{"s3://"}))
+			g.Expect(res.TESResourcesBackendParameters).To(gomega.Equal([]string{"VmSize"}))
+			g.Expect(res.Resources).To(gomega.Equal(test.resourcesExp))
+		})
+	}
+}
+
+func TestGatherGPUTypes(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name     string
+		clusters []*clusterquery.Cluster
+		typesExp []string
+	}{
+		{
+			name:     "no clusters",
+			clusters: nil,
+			typesExp: []string{},
+		},
+		{
+			name: "clusters without gpu",
+			clusters: []*clusterquery.Cluster{
+				nil,
+				{ID: "cluster-01"},
+				{ID: "cluster-02", Capacity: &clusterquery.Capacity{}},
+			},
+			typesExp: []string{},
+		},
+		{
+			name: "sorted and deduplicated",
+			clusters: []*clusterquery.Cluster{
+				{ID: "cluster-01", Capacity: &clusterquery.Capacity{GPUCapacity: &clusterquery.GPUCapacity{
+					GPU: map[string]float64{"V100": 8, "A100": 4},
+				}}},
+				{ID: "cluster-02", Capacity: &clusterquery.Capacity{GPUCapacity: &clusterquery.GPUCapacity{
+					GPU: map[string]float64{"A100": 2, "T4": 1},
+				}}},
+			},
+			typesExp: []string{"A100", "T4", "V100"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g.Expect(gatherGPUTypes(test.clusters)).To(gomega.Equal(test.typesExp))
+		})
+	}
+}
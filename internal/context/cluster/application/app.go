@@ -11,6 +11,7 @@ import (
 	"github.com/GBA-BI/tes-api/internal/context/cluster/application/query"
 	"github.com/GBA-BI/tes-api/internal/context/cluster/domain"
 	"github.com/GBA-BI/tes-api/internal/context/cluster/infra/persistence/sql"
+	"github.com/GBA-BI/tes-api/internal/context/cluster/infra/reconcile"
 	"github.com/GBA-BI/tes-api/pkg/consts"
 )
 
@@ -18,10 +19,12 @@ import (
 type ClusterService struct {
 	ClusterCommands *command.Commands
 	ClusterQueries  *query.Queries
+	// Reconciler marks clusters unhealthy and reclaims their tasks in background
+	Reconciler *reconcile.Reconciler
 }
 
 // NewClusterService ...
-func NewClusterService(ctx context.Context, opts *options.Options) (*ClusterService, error) {
+func NewClusterService(ctx context.Context, opts *options.Options, reclaimer domain.TaskReclaimer) (*ClusterService, error) {
 	var (
 		err       error
 		repo      domain.Repo
@@ -29,9 +32,9 @@ func NewClusterService(ctx context.Context, opts *options.Options) (*ClusterServ
 	)
 
 	switch opts.DB.Type {
-	case consts.MySQLType:
+	case consts.MySQLType, consts.PostgresType, consts.SQLiteType:
 		var db *gorm.DB
-		if db, err = opts.DB.MySQL.GetGORMInstance(); err != nil {
+		if db, err = opts.DB.GetGORMInstance(); err != nil {
 			return nil, err
 		}
 		if repo, err = sql.NewRepo(ctx, db); err != nil {
@@ -44,12 +47,13 @@ func NewClusterService(ctx context.Context, opts *options.Options) (*ClusterServ
 		return nil, fmt.Errorf("unsupported db type")
 	}
 
-	svc := domain.NewService(repo)
+	svc := domain.NewService(repo, reclaimer)
 	clusterCommands := command.NewCommands(svc)
 	clusterQueries := query.NewQueries(readModel)
 
 	return &ClusterService{
 		ClusterCommands: clusterCommands,
 		ClusterQueries:  clusterQueries,
+		Reconciler:      reconcile.NewReconciler(opts.Reconcile, svc),
 	}, nil
 }
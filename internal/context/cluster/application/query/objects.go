@@ -8,6 +8,7 @@ type Cluster struct {
 	HeartbeatTimestamp time.Time
 	Capacity           *Capacity
 	Limits             *Limits
+	Healthy            bool
 }
 
 // Capacity ...
@@ -8,6 +8,8 @@ type Cluster struct {
 	HeartbeatTimestamp time.Time
 	Capacity           *Capacity
 	Limits             *Limits
+	// Healthy is false if no heartbeat is received within the timeout
+	Healthy bool
 }
 
 // Capacity ...
@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+// TaskReclaimer takes tasks back from the unhealthy cluster
+type TaskReclaimer interface {
+	Reclaim(ctx context.Context, clusterID string) error
+}
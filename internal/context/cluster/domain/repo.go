@@ -1,10 +1,17 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repo ...
 type Repo interface {
 	Get(ctx context.Context, id string) (*Cluster, error)
 	Save(ctx context.Context, cluster *Cluster) error
 	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*Cluster, error)
+	// MarkUnhealthy marks the cluster unhealthy if its heartbeat is not updated,
+	// it returns false if the cluster is not found or a new heartbeat is received
+	MarkUnhealthy(ctx context.Context, id string, heartbeatTimestamp time.Time) (bool, error)
 }
@@ -7,6 +7,7 @@ package domain
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -63,6 +64,36 @@ func (mr *FakeRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*FakeRepo)(nil).Get), ctx, id)
 }
 
+// List mocks base method.
+func (m *FakeRepo) List(ctx context.Context) ([]*Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *FakeRepoMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*FakeRepo)(nil).List), ctx)
+}
+
+// MarkUnhealthy mocks base method.
+func (m *FakeRepo) MarkUnhealthy(ctx context.Context, id string, heartbeatTimestamp time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUnhealthy", ctx, id, heartbeatTimestamp)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkUnhealthy indicates an expected call of MarkUnhealthy.
+func (mr *FakeRepoMockRecorder) MarkUnhealthy(ctx, id, heartbeatTimestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUnhealthy", reflect.TypeOf((*FakeRepo)(nil).MarkUnhealthy), ctx, id, heartbeatTimestamp)
+}
+
 // Save mocks base method.
 func (m *FakeRepo) Save(ctx context.Context, cluster *Cluster) error {
 	m.ctrl.T.Helper()
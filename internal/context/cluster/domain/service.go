@@ -2,27 +2,33 @@ package domain
 
 import (
 	"context"
+	"time"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
 )
 
 // Service ...
 type Service interface {
 	Put(ctx context.Context, cluster *Cluster) error
 	Delete(ctx context.Context, id string) error
+	Reconcile(ctx context.Context, heartbeatTimeout time.Duration) error
 }
 
 type service struct {
-	repo Repo
+	repo      Repo
+	reclaimer TaskReclaimer
 }
 
 var _ Service = (*service)(nil)
 
 // NewService ...
-func NewService(repo Repo) Service {
-	return &service{repo: repo}
+func NewService(repo Repo, reclaimer TaskReclaimer) Service {
+	return &service{repo: repo, reclaimer: reclaimer}
 }
 
 // Put ...
 func (s *service) Put(ctx context.Context, cluster *Cluster) error {
+	cluster.Healthy = true
 	return s.repo.Save(ctx, cluster)
 }
 
@@ -33,3 +39,31 @@ func (s *service) Delete(ctx context.Context, id string) error {
 	}
 	return s.repo.Delete(ctx, id)
 }
+
+// Reconcile marks clusters unhealthy if no heartbeat is received within the timeout,
+// and reclaims tasks of unhealthy clusters. A failed cluster is logged and retried
+// by the next reconciling, so that it does not block other clusters.
+func (s *service) Reconcile(ctx context.Context, heartbeatTimeout time.Duration) error {
+	clusters, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(-heartbeatTimeout)
+	for _, cluster := range clusters {
+		if cluster.Healthy && cluster.HeartbeatTimestamp.Before(deadline) {
+			marked, err := s.repo.MarkUnhealthy(ctx, cluster.ID, cluster.HeartbeatTimestamp)
+			if err != nil {
+				applog.CtxErrorw(ctx, "failed to mark cluster unhealthy", "cluster", cluster.ID, "err", err)
+				continue
+			}
+			cluster.Healthy = !marked
+		}
+		// tasks are reclaimed every time, in case of the last reclaiming failed
+		if !cluster.Healthy {
+			if err = s.reclaimer.Reclaim(ctx, cluster.ID); err != nil {
+				applog.CtxErrorw(ctx, "failed to reclaim tasks of cluster", "cluster", cluster.ID, "err", err)
+			}
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ package domain
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -61,3 +62,17 @@ func (mr *FakeServiceMockRecorder) Put(ctx, cluster interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*FakeService)(nil).Put), ctx, cluster)
 }
+
+// Reconcile mocks base method.
+func (m *FakeService) Reconcile(ctx context.Context, heartbeatTimeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, heartbeatTimeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *FakeServiceMockRecorder) Reconcile(ctx, heartbeatTimeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*FakeService)(nil).Reconcile), ctx, heartbeatTimeout)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -48,7 +49,7 @@ func TestPut(t *testing.T) {
 	fakeRepo.EXPECT().Save(gomock.Any(), gomock.Any()).
 		Return(nil)
 
-	svc := NewService(fakeRepo)
+	svc := NewService(fakeRepo, nil)
 	err := svc.Put(context.TODO(), cluster)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
@@ -64,7 +65,7 @@ func TestDelete(t *testing.T) {
 	fakeRepo.EXPECT().Delete(gomock.Any(), cluster.ID).
 		Return(nil)
 
-	svc := NewService(fakeRepo)
+	svc := NewService(fakeRepo, nil)
 	err := svc.Delete(context.TODO(), cluster.ID)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
@@ -78,7 +79,82 @@ func TestDeleteNotFound(t *testing.T) {
 	fakeRepo.EXPECT().Get(gomock.Any(), cluster.ID).
 		Return(nil, apperrors.NewNotFoundError("cluster", cluster.ID))
 
-	svc := NewService(fakeRepo)
+	svc := NewService(fakeRepo, nil)
 	err := svc.Delete(context.TODO(), cluster.ID)
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
 }
+
+func TestReconcile(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	tests := []struct {
+		name      string
+		clusters  []*Cluster
+		marked    map[string]bool
+		reclaimed []string
+		failed    map[string]bool
+	}{
+		{
+			name: "healthy",
+			clusters: []*Cluster{
+				{ID: "cluster-a", HeartbeatTimestamp: now, Healthy: true},
+			},
+		},
+		{
+			name: "heartbeat timeout",
+			clusters: []*Cluster{
+				{ID: "cluster-a", HeartbeatTimestamp: now.Add(-time.Hour), Healthy: true},
+			},
+			marked:    map[string]bool{"cluster-a": true},
+			reclaimed: []string{"cluster-a"},
+		},
+		{
+			name: "heartbeat received while marking",
+			clusters: []*Cluster{
+				{ID: "cluster-a", HeartbeatTimestamp: now.Add(-time.Hour), Healthy: true},
+			},
+			marked: map[string]bool{"cluster-a": false},
+		},
+		{
+			name: "already unhealthy",
+			clusters: []*Cluster{
+				{ID: "cluster-a", HeartbeatTimestamp: now.Add(-time.Hour), Healthy: false},
+				{ID: "cluster-b", HeartbeatTimestamp: now, Healthy: true},
+			},
+			reclaimed: []string{"cluster-a"},
+		},
+		{
+			name: "reclaiming failed",
+			clusters: []*Cluster{
+				{ID: "cluster-a", HeartbeatTimestamp: now.Add(-time.Hour), Healthy: false},
+				{ID: "cluster-b", HeartbeatTimestamp: now.Add(-time.Hour), Healthy: false},
+			},
+			reclaimed: []string{"cluster-a", "cluster-b"},
+			failed:    map[string]bool{"cluster-a": true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeRepo := NewFakeRepo(ctrl)
+			fakeRepo.EXPECT().List(gomock.Any()).Return(test.clusters, nil)
+			for id, marked := range test.marked {
+				fakeRepo.EXPECT().MarkUnhealthy(gomock.Any(), id, now.Add(-time.Hour)).Return(marked, nil)
+			}
+			fakeReclaimer := NewFakeTaskReclaimer(ctrl)
+			for _, id := range test.reclaimed {
+				var err error
+				if test.failed[id] {
+					err = errors.New("reclaim failed")
+				}
+				fakeReclaimer.EXPECT().Reclaim(gomock.Any(), id).Return(err)
+			}
+
+			svc := NewService(fakeRepo, fakeReclaimer)
+			err := svc.Reconcile(context.TODO(), 5*time.Minute)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/cluster/application/query"
+	"github.com/GBA-BI/tes-api/internal/migrations"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+)
+
+func TestIntegration(t *testing.T) {
+	g := gomega.NewWithT(t)
+	gormDB := testutil.NewSQLiteDB()
+	migrator, err := migrations.NewMigrator(gormDB, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(migrator.Up(context.TODO(), 0)).To(gomega.Succeed())
+	r, err := NewRepo(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	rm, err := NewReadModel(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(r.Save(context.TODO(), clusterDO)).To(gomega.Succeed())
+	// saving again updates the cluster
+	g.Expect(r.Save(context.TODO(), clusterDO)).To(gomega.Succeed())
+	cluster, err := r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cluster).To(gomega.BeEquivalentTo(clusterDO))
+	clusters, err := rm.List(context.TODO(), nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(clusters).To(gomega.BeEquivalentTo([]*query.Cluster{clusterDTO}))
+
+	// the heartbeat is updated after it is checked
+	marked, err := r.MarkUnhealthy(context.TODO(), id, now.Add(-time.Minute))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(marked).To(gomega.BeFalse())
+	marked, err = r.MarkUnhealthy(context.TODO(), id, now)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(marked).To(gomega.BeTrue())
+	cluster, err = r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cluster.Healthy).To(gomega.BeFalse())
+
+	g.Expect(r.Delete(context.TODO(), id)).To(gomega.Succeed())
+	_, err = r.Get(context.TODO(), id)
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
@@ -0,0 +1,11 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.RunDialects(m)
+}
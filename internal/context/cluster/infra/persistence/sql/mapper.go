@@ -13,6 +13,9 @@ func (c *Cluster) toDTO() *query.Cluster {
 		ID:                 c.ID,
 		HeartbeatTimestamp: c.HeartbeatTimestamp,
 	}
+	if c.Healthy != nil {
+		res.Healthy = *c.Healthy
+	}
 	if c.Capacity != nil {
 		res.Capacity = &query.Capacity{
 			Count:    c.Capacity.Count,
@@ -48,6 +51,9 @@ func (c *Cluster) toDO() *domain.Cluster {
 		ID:                 c.ID,
 		HeartbeatTimestamp: c.HeartbeatTimestamp,
 	}
+	if c.Healthy != nil {
+		res.Healthy = *c.Healthy
+	}
 	if c.Capacity != nil {
 		res.Capacity = &domain.Capacity{
 			Count:    c.Capacity.Count,
@@ -82,6 +88,7 @@ func clusterDOToPO(cluster *domain.Cluster) *Cluster {
 	res := &Cluster{
 		ID:                 cluster.ID,
 		HeartbeatTimestamp: cluster.HeartbeatTimestamp,
+		Healthy:            &cluster.Healthy,
 	}
 	if cluster.Capacity != nil {
 		res.Capacity = &Capacity{
@@ -8,6 +8,8 @@ type Cluster struct {
 	HeartbeatTimestamp time.Time `gorm:"column:heartbeat_timestamp;type:DATETIME;not null"`
 	Capacity           *Capacity `gorm:"column:capacity;type:LONGTEXT;serializer:json"`
 	Limits             *Limits   `gorm:"column:limits;type:LONGTEXT;serializer:json"`
+	// Healthy may be updated to false, mark it as pointer so that gorm will not ignore it
+	Healthy *bool `gorm:"column:healthy;type:BOOLEAN;not null;default:true"`
 }
 
 // Capacity ...
@@ -16,9 +16,6 @@ type readModel struct {
 
 // NewReadModel ...
 func NewReadModel(ctx context.Context, db *gorm.DB) (query.ReadModel, error) {
-	if err := db.WithContext(ctx).AutoMigrate(&Cluster{}); err != nil {
-		return nil, err
-	}
 	return &readModel{db: db}, nil
 }
 
@@ -37,6 +37,7 @@ var clusterDTO = &query.Cluster{
 			},
 		},
 	},
+	Healthy: true,
 }
 
 func TestList(t *testing.T) {
@@ -45,7 +46,7 @@ func TestList(t *testing.T) {
 	r := &readModel{db: gormDB}
 	mock.ExpectQuery("SELECT * FROM `cluster`").
 		WillReturnRows(sqlmock.NewRows(rows).AddRow(clusterPO.ID, clusterPO.HeartbeatTimestamp,
-			testutil.MustJSONMarshal(clusterPO.Capacity), testutil.MustJSONMarshal(clusterPO.Limits)))
+			testutil.MustJSONMarshal(clusterPO.Capacity), testutil.MustJSONMarshal(clusterPO.Limits), clusterPO.Healthy))
 	resp, err := r.List(context.TODO(), nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.Cluster{clusterDTO}))
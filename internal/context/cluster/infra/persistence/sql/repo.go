@@ -3,6 +3,7 @@ package sql
 import (
 	"context"
 	"errors"
+	"time"
 
 	applog "github.com/GBA-BI/tes-api/pkg/log"
 	"gorm.io/gorm"
@@ -18,9 +19,6 @@ type repo struct {
 
 // NewRepo ...
 func NewRepo(ctx context.Context, db *gorm.DB) (domain.Repo, error) {
-	if err := db.WithContext(ctx).AutoMigrate(&Cluster{}); err != nil {
-		return nil, err
-	}
 	return &repo{db: db}, nil
 }
 
@@ -29,7 +27,7 @@ var _ domain.Repo = (*repo)(nil)
 // Get ...
 func (r *repo) Get(ctx context.Context, id string) (*domain.Cluster, error) {
 	var cluster Cluster
-	if err := r.db.WithContext(ctx).Model(&Cluster{}).Where("`id` = ?", id).First(&cluster).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&Cluster{}).Where("id = ?", id).First(&cluster).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewNotFoundError("cluster", id)
 		}
@@ -53,9 +51,35 @@ func (r *repo) Save(ctx context.Context, cluster *domain.Cluster) error {
 
 // Delete ...
 func (r *repo) Delete(ctx context.Context, id string) error {
-	if err := r.db.WithContext(ctx).Model(&Cluster{}).Where("`id` = ?", id).Delete(&Cluster{}).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&Cluster{}).Where("id = ?", id).Delete(&Cluster{}).Error; err != nil {
 		applog.Errorw("failed to delete cluster", "err", err)
 		return apperrors.NewInternalError(err)
 	}
 	return nil
 }
+
+// List ...
+func (r *repo) List(ctx context.Context) ([]*domain.Cluster, error) {
+	var clusters []*Cluster
+	if err := r.db.WithContext(ctx).Model(&Cluster{}).Find(&clusters).Error; err != nil {
+		applog.Errorw("failed to list clusters", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.Cluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		res = append(res, cluster.toDO())
+	}
+	return res, nil
+}
+
+// MarkUnhealthy ...
+func (r *repo) MarkUnhealthy(ctx context.Context, id string, heartbeatTimestamp time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&Cluster{}).
+		Where("id = ? AND heartbeat_timestamp = ?", id, heartbeatTimestamp).
+		Update("healthy", false)
+	if result.Error != nil {
+		applog.Errorw("failed to mark cluster unhealthy", "err", result.Error)
+		return false, apperrors.NewInternalError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -43,6 +43,7 @@ var clusterPO = &Cluster{
 			},
 		},
 	},
+	Healthy: utils.Point(true),
 }
 
 var clusterDO = &domain.Cluster{
@@ -70,17 +71,18 @@ var clusterDO = &domain.Cluster{
 			},
 		},
 	},
+	Healthy: true,
 }
 
-var rows = []string{"id", "heartbeat_timestamp", "capacity", "limits"}
+var rows = []string{"id", "heartbeat_timestamp", "capacity", "limits", "healthy"}
 
 func TestGet(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `cluster` WHERE `id` = ? ORDER BY `cluster`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `cluster` WHERE id = ? ORDER BY `cluster`.`id` LIMIT 1").WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(rows).AddRow(clusterPO.ID, clusterPO.HeartbeatTimestamp,
-			testutil.MustJSONMarshal(clusterPO.Capacity), testutil.MustJSONMarshal(clusterPO.Limits)))
+			testutil.MustJSONMarshal(clusterPO.Capacity), testutil.MustJSONMarshal(clusterPO.Limits), clusterPO.Healthy))
 	resp, err := r.Get(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(resp).To(gomega.BeEquivalentTo(clusterDO))
@@ -90,7 +92,7 @@ func TestGetNotFound(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `cluster` WHERE `id` = ? ORDER BY `cluster`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `cluster` WHERE id = ? ORDER BY `cluster`.`id` LIMIT 1").WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(rows))
 	_, err := r.Get(context.TODO(), id)
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
@@ -101,9 +103,9 @@ func TestSave(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec(fmt.Sprintf("INSERT INTO `cluster` %s %s", testutil.GenInsertSql(rows), testutil.GenDuplicateKeySql(rows[1:] /*without id*/))).
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO `cluster` %s %s", testutil.GenInsertSql(rows), testutil.GenDuplicateKeySql(rows[:1], rows[1:]))).
 		WithArgs(clusterPO.ID, clusterPO.HeartbeatTimestamp,
-			testutil.MustJSONMarshal(clusterPO.Capacity), testutil.MustJSONMarshal(clusterPO.Limits)).
+			testutil.MustJSONMarshal(clusterPO.Capacity), testutil.MustJSONMarshal(clusterPO.Limits), clusterPO.Healthy).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	err := r.Save(context.TODO(), clusterDO)
@@ -115,8 +117,53 @@ func TestDelete(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec("DELETE FROM `cluster` WHERE `id` = ?").WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM `cluster` WHERE id = ?").WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	err := r.Delete(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
+
+func TestRepoList(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `cluster`").
+		WillReturnRows(sqlmock.NewRows(rows).AddRow(clusterPO.ID, clusterPO.HeartbeatTimestamp,
+			testutil.MustJSONMarshal(clusterPO.Capacity), testutil.MustJSONMarshal(clusterPO.Limits), clusterPO.Healthy))
+	resp, err := r.List(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.Cluster{clusterDO}))
+}
+
+func TestMarkUnhealthy(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expMarked    bool
+	}{
+		{
+			name:         "marked",
+			rowsAffected: 1,
+			expMarked:    true,
+		},
+		{
+			name:         "heartbeat updated",
+			rowsAffected: 0,
+			expMarked:    false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			mock, gormDB := testutil.NewSqlMock()
+			r := &repo{db: gormDB}
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE `cluster` SET `healthy`=? WHERE id = ? AND heartbeat_timestamp = ?").
+				WithArgs(false, id, now).WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+			mock.ExpectCommit()
+			marked, err := r.MarkUnhealthy(context.TODO(), id, now)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(marked).To(gomega.Equal(test.expMarked))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package reconcile
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// TaskPolicyRequeue requeues INITIALIZING and RUNNING tasks of unhealthy clusters
+	TaskPolicyRequeue = "requeue"
+	// TaskPolicyFail marks INITIALIZING and RUNNING tasks of unhealthy clusters SYSTEM_ERROR
+	TaskPolicyFail = "fail"
+)
+
+// Options ...
+type Options struct {
+	Enable           bool          `mapstructure:"enable"`
+	Interval         time.Duration `mapstructure:"interval"`
+	HeartbeatTimeout time.Duration `mapstructure:"heartbeatTimeout"`
+	TaskPolicy       string        `mapstructure:"taskPolicy"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Enable:           true,
+		Interval:         30 * time.Second,
+		HeartbeatTimeout: 5 * time.Minute,
+		TaskPolicy:       TaskPolicyFail,
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if o.Interval <= 0 {
+		return fmt.Errorf("reconcile interval should be positive")
+	}
+	if o.HeartbeatTimeout <= 0 {
+		return fmt.Errorf("reconcile heartbeatTimeout should be positive")
+	}
+	if o.TaskPolicy != TaskPolicyRequeue && o.TaskPolicy != TaskPolicyFail {
+		return fmt.Errorf("reconcile taskPolicy should be %s or %s", TaskPolicyRequeue, TaskPolicyFail)
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enable, "reconcile-enable", o.Enable, "enable marking clusters unhealthy and reclaiming their tasks")
+	fs.DurationVar(&o.Interval, "reconcile-interval", o.Interval, "interval of cluster reconciling")
+	fs.DurationVar(&o.HeartbeatTimeout, "reconcile-heartbeat-timeout", o.HeartbeatTimeout, "cluster is unhealthy if no heartbeat within the timeout")
+	fs.StringVar(&o.TaskPolicy, "reconcile-task-policy", o.TaskPolicy, "action on running tasks of unhealthy clusters, requeue or fail")
+}
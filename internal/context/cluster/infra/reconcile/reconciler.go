@@ -0,0 +1,39 @@
+package reconcile
+
+import (
+	"context"
+	"time"
+
+	"github.com/GBA-BI/tes-api/internal/context/cluster/domain"
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+)
+
+// Reconciler marks clusters unhealthy periodically and reclaims their tasks
+type Reconciler struct {
+	opts *Options
+	svc  domain.Service
+}
+
+// NewReconciler ...
+func NewReconciler(opts *Options, svc domain.Service) *Reconciler {
+	return &Reconciler{opts: opts, svc: svc}
+}
+
+// Run blocks until ctx is done
+func (r *Reconciler) Run(ctx context.Context) {
+	if !r.opts.Enable {
+		return
+	}
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.svc.Reconcile(ctx, r.opts.HeartbeatTimeout); err != nil {
+				applog.Errorw("failed to reconcile clusters", "err", err)
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/GBA-BI/tes-api/internal/context/cluster/application/command"
 	"github.com/GBA-BI/tes-api/internal/context/cluster/application/query"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
@@ -20,10 +21,12 @@ import (
 //	@Accept			application/json
 //	@Produce		application/json
 //	@Router			/api/v1/clusters/{id} [put]
+//	@Security		BearerAuth
 //	@Param			id		path		string				true	"put cluster id"
 //	@Param			request	body		PutClusterRequest	true	"put cluster request"
 //	@Success		200		{object}	PutClusterResponse
 //	@Failure		400		{object}	apperrors.AppError	"invalid param"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
 //	@Failure		500		{object}	apperrors.AppError	"internal system error"
 func PutCluster(c context.Context, ctx *app.RequestContext, handler command.PutHandler) {
 	var req PutClusterRequest
@@ -33,7 +36,18 @@ func PutCluster(c context.Context, ctx *app.RequestContext, handler command.PutH
 		return
 	}
 
-	if err := handler.Handle(c, req.toDTO()); err != nil {
+	// a cluster agent only reports its own cluster
+	clusterID, err := auth.GetIdentity(c).ClusterScope()
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	if clusterID != "" && clusterID != req.ID {
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewPermissionDeniedError("cluster id is different from the caller"))
+		return
+	}
+
+	if err = handler.Handle(c, req.toDTO()); err != nil {
 		utils.WriteHertzErrorResponse(ctx, err)
 		return
 	}
@@ -49,8 +63,10 @@ func PutCluster(c context.Context, ctx *app.RequestContext, handler command.PutH
 //	@Tags			cluster
 //	@Produce		application/json
 //	@Router			/api/v1/clusters [get]
+//	@Security		BearerAuth
 //	@Success		200	{object}	ListClustersResponse
 //	@Failure		400	{object}	apperrors.AppError	"invalid param"
+//	@Failure		403	{object}	apperrors.AppError	"permission denied"
 //	@Failure		500	{object}	apperrors.AppError	"internal system error"
 func ListClusters(c context.Context, ctx *app.RequestContext, handler query.ListHandler) {
 	var req ListClustersRequest
@@ -79,10 +95,12 @@ func ListClusters(c context.Context, ctx *app.RequestContext, handler query.List
 //	@Tags			cluster
 //	@Produce		application/json
 //	@Router			/api/v1/clusters/{id} [delete]
+//	@Security		BearerAuth
 //	@Param			id	path		string	true	"delete cluster id"
 //	@Success		200	{object}	DeleteClusterResponse
 //	@Failure		400	{object}	apperrors.AppError	"invalid param"
 //	@Failure		404	{object}	apperrors.AppError	"not found"
+//	@Failure		403	{object}	apperrors.AppError	"permission denied"
 //	@Failure		500	{object}	apperrors.AppError	"internal system error"
 func DeleteCluster(c context.Context, ctx *app.RequestContext, handler command.DeleteHandler) {
 	var req DeleteClusterRequest
@@ -71,6 +71,7 @@ func clusterDTOToVO(cluster *query.Cluster) *Cluster {
 		ID:       cluster.ID,
 		Capacity: nil,
 		Limits:   nil,
+		Healthy:  cluster.Healthy,
 	}
 	if !cluster.HeartbeatTimestamp.IsZero() {
 		res.HeartbeatTimestamp = cluster.HeartbeatTimestamp.Format(time.RFC3339)
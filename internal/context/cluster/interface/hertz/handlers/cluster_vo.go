@@ -31,6 +31,7 @@ type Cluster struct {
 	HeartbeatTimestamp string    `json:"heartbeat_timestamp"`
 	Capacity           *Capacity `json:"capacity,omitempty"`
 	Limits             *Limits   `json:"limits,omitempty"`
+	Healthy            bool      `json:"healthy"`
 }
 
 // Capacity ...
@@ -8,6 +8,7 @@ import (
 
 	"github.com/GBA-BI/tes-api/internal/context/cluster/application"
 	"github.com/GBA-BI/tes-api/internal/context/cluster/interface/hertz/handlers"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	"github.com/GBA-BI/tes-api/pkg/consts"
 	appserver "github.com/GBA-BI/tes-api/pkg/server"
 )
@@ -27,15 +28,15 @@ func NewRouterRegister(clusterService *application.ClusterService) appserver.Rou
 func (r *register) AddRoute(h route.IRouter) {
 	cluster := h.Group(consts.OtherAPIPrefix + "/clusters")
 
-	cluster.PUT("/:id", func(c context.Context, ctx *app.RequestContext) {
+	cluster.PUT("/:id", auth.RequireRoles(auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.PutCluster(c, ctx, r.svc.ClusterCommands.Put)
 	})
 
-	cluster.GET("", func(c context.Context, ctx *app.RequestContext) {
+	cluster.GET("", auth.RequireRoles(auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.ListClusters(c, ctx, r.svc.ClusterQueries.List)
 	})
 
-	cluster.DELETE("/:id", func(c context.Context, ctx *app.RequestContext) {
+	cluster.DELETE("/:id", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.DeleteCluster(c, ctx, r.svc.ClusterCommands.Delete)
 	})
 }
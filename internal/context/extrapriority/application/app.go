@@ -21,7 +21,7 @@ type ExtraPriorityService struct {
 }
 
 // NewExtraPriorityService ...
-func NewExtraPriorityService(ctx context.Context, opts *options.Options) (*ExtraPriorityService, error) {
+func NewExtraPriorityService(ctx context.Context, opts *options.Options, refresher domain.PriorityRefresher) (*ExtraPriorityService, error) {
 	var (
 		err       error
 		repo      domain.Repo
@@ -29,9 +29,9 @@ func NewExtraPriorityService(ctx context.Context, opts *options.Options) (*Extra
 	)
 
 	switch opts.DB.Type {
-	case consts.MySQLType:
+	case consts.MySQLType, consts.PostgresType, consts.SQLiteType:
 		var db *gorm.DB
-		if db, err = opts.DB.MySQL.GetGORMInstance(); err != nil {
+		if db, err = opts.DB.GetGORMInstance(); err != nil {
 			return nil, err
 		}
 		if repo, err = sql.NewRepo(ctx, db); err != nil {
@@ -44,7 +44,7 @@ func NewExtraPriorityService(ctx context.Context, opts *options.Options) (*Extra
 		return nil, fmt.Errorf("unsupported db type")
 	}
 
-	svc := domain.NewService(repo)
+	svc := domain.NewService(repo, refresher)
 	extraPriorityCommands := command.NewCommands(svc)
 	extraPriorityQueries := query.NewQueries(readModel)
 
@@ -36,3 +36,22 @@ func NewExtraPriorityID(accountID, userID, submissionID, runID string) (string,
 	}
 	return fmt.Sprintf("%s/%s/%s/%s", accountID, userID, submissionID, runID), nil
 }
+
+// MatchedExtraPriorityIDs returns ids of extra priorities which apply to a task
+// belonging to the account/user, submission and run
+func MatchedExtraPriorityIDs(accountID, userID, submissionID, runID string) []string {
+	ids := make([]string, 0, 4)
+	if accountID != "" {
+		ids = append(ids, fmt.Sprintf("%s///", accountID))
+		if userID != "" {
+			ids = append(ids, fmt.Sprintf("%s/%s//", accountID, userID))
+		}
+	}
+	if submissionID != "" {
+		ids = append(ids, fmt.Sprintf("//%s/", submissionID))
+	}
+	if runID != "" {
+		ids = append(ids, fmt.Sprintf("///%s", runID))
+	}
+	return ids
+}
@@ -80,3 +80,47 @@ func TestNewExtraPriorityID(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchedExtraPriorityIDs(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name         string
+		accountID    string
+		userID       string
+		submissionID string
+		runID        string
+		expIDs       []string
+	}{
+		{
+			name:   "all empty",
+			expIDs: []string{},
+		},
+		{
+			name:         "all non-empty",
+			accountID:    "ac1",
+			userID:       "u1",
+			submissionID: "sb1",
+			runID:        "r1",
+			expIDs:       []string{"ac1///", "ac1/u1//", "//sb1/", "///r1"},
+		},
+		{
+			name:      "empty userID",
+			accountID: "ac1",
+			runID:     "r1",
+			expIDs:    []string{"ac1///", "///r1"},
+		},
+		{
+			name:   "empty accountID with non-empty userID",
+			userID: "u1",
+			expIDs: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ids := MatchedExtraPriorityIDs(test.accountID, test.userID, test.submissionID, test.runID)
+			g.Expect(ids).To(gomega.Equal(test.expIDs))
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// PriorityRefresher refreshes effective priorities of tasks affected by
+// the extra priority of account/user, submission or run
+type PriorityRefresher interface {
+	Refresh(ctx context.Context, accountID, userID, submissionID, runID string) error
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
 )
 
 // Service ...
@@ -11,14 +13,15 @@ type Service interface {
 }
 
 type service struct {
-	repo Repo
+	repo      Repo
+	refresher PriorityRefresher
 }
 
 var _ Service = (*service)(nil)
 
 // NewService ...
-func NewService(repo Repo) Service {
-	return &service{repo: repo}
+func NewService(repo Repo, refresher PriorityRefresher) Service {
+	return &service{repo: repo, refresher: refresher}
 }
 
 // Put ...
@@ -35,7 +38,11 @@ func (s *service) Put(ctx context.Context, accountID, userID, submissionID, runI
 		RunID:              runID,
 		ExtraPriorityValue: extraPriorityValue,
 	}
-	return s.repo.Save(ctx, priority)
+	if err = s.repo.Save(ctx, priority); err != nil {
+		return err
+	}
+	s.refresh(ctx, accountID, userID, submissionID, runID)
+	return nil
 }
 
 // Delete ...
@@ -47,5 +54,18 @@ func (s *service) Delete(ctx context.Context, accountID, userID, submissionID, r
 	if _, err = s.repo.Get(ctx, id); err != nil {
 		return err
 	}
-	return s.repo.Delete(ctx, id)
+	if err = s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.refresh(ctx, accountID, userID, submissionID, runID)
+	return nil
+}
+
+// refresh does not fail the change of the extra priority which is already saved,
+// effective priorities left behind are refreshed periodically later
+func (s *service) refresh(ctx context.Context, accountID, userID, submissionID, runID string) {
+	if err := s.refresher.Refresh(ctx, accountID, userID, submissionID, runID); err != nil {
+		applog.CtxErrorw(ctx, "failed to refresh priorities", "accountID", accountID, "userID", userID,
+			"submissionID", submissionID, "runID", runID, "err", err)
+	}
 }
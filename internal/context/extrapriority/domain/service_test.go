@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -24,12 +25,30 @@ func TestPut(t *testing.T) {
 		RunID:              "",
 		ExtraPriorityValue: 20,
 	}).Return(nil)
+	fakeRefresher := NewFakePriorityRefresher(ctrl)
+	fakeRefresher.EXPECT().Refresh(gomock.Any(), "ac1", "u1", "", "").Return(nil)
 
-	svc := NewService(fakeRepo)
+	svc := NewService(fakeRepo, fakeRefresher)
 	err := svc.Put(context.TODO(), "ac1", "u1", "", "", 20)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
 
+func TestPutRefreshFailed(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+	fakeRefresher := NewFakePriorityRefresher(ctrl)
+	fakeRefresher.EXPECT().Refresh(gomock.Any(), "", "", "s1", "").
+		Return(apperrors.NewInternalError(fmt.Errorf("db error")))
+
+	svc := NewService(fakeRepo, fakeRefresher)
+	err := svc.Put(context.TODO(), "", "", "s1", "", 20)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
 func TestDelete(t *testing.T) {
 	g := gomega.NewWithT(t)
 	ctrl := gomock.NewController(t)
@@ -44,8 +63,10 @@ func TestDelete(t *testing.T) {
 		}, nil)
 	fakeRepo.EXPECT().Delete(gomock.Any(), "ac1///").
 		Return(nil)
+	fakeRefresher := NewFakePriorityRefresher(ctrl)
+	fakeRefresher.EXPECT().Refresh(gomock.Any(), "ac1", "", "", "").Return(nil)
 
-	svc := NewService(fakeRepo)
+	svc := NewService(fakeRepo, fakeRefresher)
 	err := svc.Delete(context.TODO(), "ac1", "", "", "")
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
@@ -59,7 +80,7 @@ func TestDeleteNotFound(t *testing.T) {
 	fakeRepo.EXPECT().Get(gomock.Any(), "///r1").
 		Return(nil, apperrors.NewNotFoundError("extra priority", "///r1"))
 
-	svc := NewService(fakeRepo)
+	svc := NewService(fakeRepo, NewFakePriorityRefresher(ctrl))
 	err := svc.Delete(context.TODO(), "", "", "", "r1")
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
 }
@@ -0,0 +1,42 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/extrapriority/application/query"
+	"github.com/GBA-BI/tes-api/internal/migrations"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+)
+
+func TestIntegration(t *testing.T) {
+	g := gomega.NewWithT(t)
+	gormDB := testutil.NewSQLiteDB()
+	migrator, err := migrations.NewMigrator(gormDB, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(migrator.Up(context.TODO(), 0)).To(gomega.Succeed())
+	r, err := NewRepo(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	rm, err := NewReadModel(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(r.Save(context.TODO(), priorityDO)).To(gomega.Succeed())
+	priority, err := r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(priority).To(gomega.BeEquivalentTo(priorityDO))
+
+	priorities, err := rm.List(context.TODO(), &query.ListFilter{AccountID: "ac1", SubmissionID: "sb1", RunID: "r1"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(priorities).To(gomega.BeEquivalentTo([]*query.ExtraPriority{priorityDTO}))
+	priorities, err = rm.List(context.TODO(), &query.ListFilter{AccountID: "ac2"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(priorities).To(gomega.BeEmpty())
+
+	g.Expect(r.Delete(context.TODO(), id)).To(gomega.Succeed())
+	_, err = r.Get(context.TODO(), id)
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
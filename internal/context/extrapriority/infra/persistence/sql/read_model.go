@@ -16,9 +16,6 @@ type readModel struct {
 
 // NewReadModel ...
 func NewReadModel(ctx context.Context, db *gorm.DB) (query.ReadModel, error) {
-	if err := db.WithContext(ctx).AutoMigrate(&ExtraPriority{}); err != nil {
-		return nil, err
-	}
 	return &readModel{db: db}, nil
 }
 
@@ -47,13 +44,13 @@ func listFilter(db *gorm.DB, filter *query.ListFilter) *gorm.DB {
 		return db
 	}
 	if filter.AccountID != "" {
-		db = db.Where("`account_id` = ?", filter.AccountID)
+		db = db.Where("account_id = ?", filter.AccountID)
 	}
 	if filter.SubmissionID != "" {
-		db = db.Where("`submission_id` = ?", filter.SubmissionID)
+		db = db.Where("submission_id = ?", filter.SubmissionID)
 	}
 	if filter.RunID != "" {
-		db = db.Where("`run_id` = ?", filter.RunID)
+		db = db.Where("run_id = ?", filter.RunID)
 	}
 	return db
 }
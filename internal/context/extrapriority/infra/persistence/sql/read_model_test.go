@@ -23,7 +23,7 @@ func TestList(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `extra_priority` WHERE `account_id` = ? AND `submission_id` = ? AND `run_id` = ?").
+	mock.ExpectQuery("SELECT * FROM `extra_priority` WHERE account_id = ? AND submission_id = ? AND run_id = ?").
 		WithArgs("ac1", "sb1", "r1").
 		WillReturnRows(sqlmock.NewRows(rows).AddRow(priorityPO.ID, priorityPO.AccountID, priorityPO.UserID,
 			priorityPO.SubmissionID, priorityPO.RunID, priorityPO.ExtraPriorityValue))
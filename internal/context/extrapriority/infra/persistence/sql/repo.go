@@ -18,9 +18,6 @@ type repo struct {
 
 // NewRepo ...
 func NewRepo(ctx context.Context, db *gorm.DB) (domain.Repo, error) {
-	if err := db.WithContext(ctx).AutoMigrate(&ExtraPriority{}); err != nil {
-		return nil, err
-	}
 	return &repo{db: db}, nil
 }
 
@@ -29,7 +26,7 @@ var _ domain.Repo = (*repo)(nil)
 // Get ...
 func (r *repo) Get(ctx context.Context, id string) (*domain.ExtraPriority, error) {
 	var extraPriority ExtraPriority
-	if err := r.db.WithContext(ctx).Model(&ExtraPriority{}).Where("`id` = ?", id).First(&extraPriority).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&ExtraPriority{}).Where("id = ?", id).First(&extraPriority).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewNotFoundError("extra priority", id)
 		}
@@ -53,7 +50,7 @@ func (r *repo) Save(ctx context.Context, priority *domain.ExtraPriority) error {
 
 // Delete ...
 func (r *repo) Delete(ctx context.Context, id string) error {
-	if err := r.db.WithContext(ctx).Model(&ExtraPriority{}).Where("`id` = ?", id).Delete(&ExtraPriority{}).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&ExtraPriority{}).Where("id = ?", id).Delete(&ExtraPriority{}).Error; err != nil {
 		applog.Errorw("failed to delete extra priority", "err", err)
 		return apperrors.NewInternalError(err)
 	}
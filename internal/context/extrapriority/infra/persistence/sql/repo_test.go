@@ -39,7 +39,7 @@ func TestGet(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `extra_priority` WHERE `id` = ? ORDER BY `extra_priority`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `extra_priority` WHERE id = ? ORDER BY `extra_priority`.`id` LIMIT 1").WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(rows).AddRow(priorityPO.ID, priorityPO.AccountID, priorityPO.UserID,
 			priorityPO.SubmissionID, priorityPO.RunID, priorityPO.ExtraPriorityValue))
 	resp, err := r.Get(context.TODO(), id)
@@ -51,7 +51,7 @@ func TestGetNotFound(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `extra_priority` WHERE `id` = ? ORDER BY `extra_priority`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `extra_priority` WHERE id = ? ORDER BY `extra_priority`.`id` LIMIT 1").WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(rows))
 	_, err := r.Get(context.TODO(), id)
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
@@ -62,7 +62,7 @@ func TestSave(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec(fmt.Sprintf("INSERT INTO `extra_priority` %s %s", testutil.GenInsertSql(rows), testutil.GenDuplicateKeySql(rows[1:] /*without id*/))).
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO `extra_priority` %s %s", testutil.GenInsertSql(rows), testutil.GenDuplicateKeySql(rows[:1], rows[1:]))).
 		WithArgs(priorityPO.ID, priorityPO.AccountID, priorityPO.UserID, priorityPO.SubmissionID, priorityPO.RunID, priorityPO.ExtraPriorityValue).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
@@ -75,7 +75,7 @@ func TestDelete(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec("DELETE FROM `extra_priority` WHERE `id` = ?").WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM `extra_priority` WHERE id = ?").WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	err := r.Delete(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
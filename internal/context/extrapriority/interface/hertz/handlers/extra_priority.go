@@ -20,6 +20,7 @@ import (
 //	@Accept			application/json
 //	@Produce		application/json
 //	@Router			/api/v1/extra_priority [put]
+//	@Security		BearerAuth
 //	@Param			account_id		query		string					false	"query account id"
 //	@Param			user_id			query		string					false	"query user id"
 //	@Param			submission_id	query		string					false	"query submission id"
@@ -27,6 +28,7 @@ import (
 //	@Param			request			body		PutExtraPriorityRequest	true	"put tasks extra priority request"
 //	@Success		200				{object}	PutExtraPriorityResponse
 //	@Failure		400				{object}	apperrors.AppError	"invalid param"
+//	@Failure		403				{object}	apperrors.AppError	"permission denied"
 //	@Failure		500				{object}	apperrors.AppError	"internal system error"
 func PutExtraPriority(c context.Context, ctx *app.RequestContext, handler command.PutHandler) {
 	var req PutExtraPriorityRequest
@@ -50,11 +52,13 @@ func PutExtraPriority(c context.Context, ctx *app.RequestContext, handler comman
 //	@Tags			priority
 //	@Produce		application/json
 //	@Router			/api/v1/extra_priority [get]
+//	@Security		BearerAuth
 //	@Param			account_id		query		string	false	"query account id"
 //	@Param			submission_id	query		string	false	"query submission id"
 //	@Param			run_id			query		string	false	"query run id"
 //	@Success		200				{object}	ListExtraPriorityResponse
 //	@Failure		400				{object}	apperrors.AppError	"invalid param"
+//	@Failure		403				{object}	apperrors.AppError	"permission denied"
 //	@Failure		500				{object}	apperrors.AppError	"internal system error"
 func ListExtraPriority(c context.Context, ctx *app.RequestContext, handler query.ListHandler) {
 	var req ListExtraPriorityRequest
@@ -83,6 +87,7 @@ func ListExtraPriority(c context.Context, ctx *app.RequestContext, handler query
 //	@Tags			priority
 //	@Produce		application/json
 //	@Router			/api/v1/extra_priority [delete]
+//	@Security		BearerAuth
 //	@Param			account_id		query		string	false	"query account id"
 //	@Param			user_id			query		string	false	"query user id"
 //	@Param			submission_id	query		string	false	"query submission id"
@@ -90,6 +95,7 @@ func ListExtraPriority(c context.Context, ctx *app.RequestContext, handler query
 //	@Success		200				{object}	DeleteExtraPriorityResponse
 //	@Failure		400				{object}	apperrors.AppError	"invalid param"
 //	@Failure		404				{object}	apperrors.AppError	"not found"
+//	@Failure		403				{object}	apperrors.AppError	"permission denied"
 //	@Failure		500				{object}	apperrors.AppError	"internal system error"
 func DeleteExtraPriority(c context.Context, ctx *app.RequestContext, handler command.DeleteHandler) {
 	var req DeleteExtraPriorityRequest
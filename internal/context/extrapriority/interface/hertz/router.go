@@ -8,6 +8,7 @@ import (
 
 	"github.com/GBA-BI/tes-api/internal/context/extrapriority/application"
 	"github.com/GBA-BI/tes-api/internal/context/extrapriority/interface/hertz/handlers"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	"github.com/GBA-BI/tes-api/pkg/consts"
 	appserver "github.com/GBA-BI/tes-api/pkg/server"
 )
@@ -27,15 +28,15 @@ func NewRouterRegister(extraPriorityService *application.ExtraPriorityService) a
 func (r *register) AddRoute(h route.IRouter) {
 	extraPriority := h.Group(consts.OtherAPIPrefix + "/extra_priority")
 
-	extraPriority.PUT("", func(c context.Context, ctx *app.RequestContext) {
+	extraPriority.PUT("", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.PutExtraPriority(c, ctx, r.svc.ExtraPriorityCommands.Put)
 	})
 
-	extraPriority.GET("", func(c context.Context, ctx *app.RequestContext) {
+	extraPriority.GET("", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.ListExtraPriority(c, ctx, r.svc.ExtraPriorityQueries.List)
 	})
 
-	extraPriority.DELETE("", func(c context.Context, ctx *app.RequestContext) {
+	extraPriority.DELETE("", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.DeleteExtraPriority(c, ctx, r.svc.ExtraPriorityCommands.Delete)
 	})
 }
@@ -28,9 +28,9 @@ func NewQuotaService(ctx context.Context, opts *options.Options) (*QuotaService,
 	)
 
 	switch opts.DB.Type {
-	case consts.MySQLType:
+	case consts.MySQLType, consts.PostgresType, consts.SQLiteType:
 		var db *gorm.DB
-		if db, err = opts.DB.MySQL.GetGORMInstance(); err != nil {
+		if db, err = opts.DB.GetGORMInstance(); err != nil {
 			return nil, err
 		}
 		if repo, err = sql.NewRepo(ctx, db); err != nil {
@@ -0,0 +1,43 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/migrations"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func TestIntegration(t *testing.T) {
+	g := gomega.NewWithT(t)
+	gormDB := testutil.NewSQLiteDB()
+	migrator, err := migrations.NewMigrator(gormDB, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(migrator.Up(context.TODO(), 0)).To(gomega.Succeed())
+	r, err := NewRepo(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(r.Save(context.TODO(), quotaDO)).To(gomega.Succeed())
+	quota, err := r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(quota).To(gomega.BeEquivalentTo(quotaDO))
+
+	// saving again overwrites the quota
+	resourceQuota := *quotaDO.ResourceQuota
+	resourceQuota.Count = utils.Point(20)
+	updated := *quotaDO
+	updated.ResourceQuota = &resourceQuota
+	g.Expect(r.Save(context.TODO(), &updated)).To(gomega.Succeed())
+	quota, err = r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(quota).To(gomega.BeEquivalentTo(&updated))
+
+	g.Expect(r.Delete(context.TODO(), id)).To(gomega.Succeed())
+	_, err = r.Get(context.TODO(), id)
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
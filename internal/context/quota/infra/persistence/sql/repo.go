@@ -18,9 +18,6 @@ type repo struct {
 
 // NewRepo ...
 func NewRepo(ctx context.Context, db *gorm.DB) (domain.Repo, error) {
-	if err := db.WithContext(ctx).AutoMigrate(&Quota{}); err != nil {
-		return nil, err
-	}
 	return &repo{db: db}, nil
 }
 
@@ -29,7 +26,7 @@ var _ domain.Repo = (*repo)(nil)
 // Get ...
 func (r *repo) Get(ctx context.Context, id string) (*domain.Quota, error) {
 	var quota Quota
-	if err := r.db.WithContext(ctx).Model(&Quota{}).Where("`id` = ?", id).First(&quota).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&Quota{}).Where("id = ?", id).First(&quota).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewNotFoundError("quota", id)
 		}
@@ -53,7 +50,7 @@ func (r *repo) Save(ctx context.Context, quota *domain.Quota) error {
 
 // Delete ...
 func (r *repo) Delete(ctx context.Context, id string) error {
-	if err := r.db.WithContext(ctx).Model(&Quota{}).Where("`id` = ?", id).Delete(&Quota{}).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&Quota{}).Where("id = ?", id).Delete(&Quota{}).Error; err != nil {
 		applog.Errorw("failed to delete quota", "err", err)
 		return apperrors.NewInternalError(err)
 	}
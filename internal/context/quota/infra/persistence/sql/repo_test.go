@@ -58,7 +58,7 @@ func TestGet(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `quota` WHERE `id` = ? ORDER BY `quota`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `quota` WHERE id = ? ORDER BY `quota`.`id` LIMIT 1").WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(rows).AddRow(quotaPO.ID, quotaPO.AccountID, quotaPO.UserID, testutil.MustJSONMarshal(quotaPO.ResourceQuota)))
 	resp, err := r.Get(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -69,7 +69,7 @@ func TestGetNotFound(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `quota` WHERE `id` = ? ORDER BY `quota`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `quota` WHERE id = ? ORDER BY `quota`.`id` LIMIT 1").WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(rows))
 	_, err := r.Get(context.TODO(), id)
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
@@ -80,7 +80,7 @@ func TestSave(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec(fmt.Sprintf("INSERT INTO `quota` %s %s", testutil.GenInsertSql(rows), testutil.GenDuplicateKeySql(rows[1:] /*without id*/))).
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO `quota` %s %s", testutil.GenInsertSql(rows), testutil.GenDuplicateKeySql(rows[:1], rows[1:]))).
 		WithArgs(quotaPO.ID, quotaPO.AccountID, quotaPO.UserID, testutil.MustJSONMarshal(quotaPO.ResourceQuota)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
@@ -93,7 +93,7 @@ func TestDelete(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec("DELETE FROM `quota` WHERE `id` = ?").WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM `quota` WHERE id = ?").WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	err := r.Delete(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -19,12 +19,14 @@ import (
 //	@Tags			quota
 //	@Produce		application/json
 //	@Router			/api/v1/quota [get]
+//	@Security		BearerAuth
 //	@Param			global		query		bool	false	"query global quota"
 //	@Param			account_id	query		string	false	"query account quota"
 //	@Param			user_id		query		string	false	"query user quota"
 //	@Success		200			{object}	GetQuotaResponse
 //	@Failure		400			{object}	apperrors.AppError	"invalid param"
 //	@Failure		404			{object}	apperrors.AppError	"not found"
+//	@Failure		403			{object}	apperrors.AppError	"permission denied"
 //	@Failure		500			{object}	apperrors.AppError	"internal system error"
 func GetQuota(c context.Context, ctx *app.RequestContext, handler query.GetHandler) {
 	var req GetQuotaRequest
@@ -50,9 +52,11 @@ func GetQuota(c context.Context, ctx *app.RequestContext, handler query.GetHandl
 //	@Accept			application/json
 //	@Produce		application/json
 //	@Router			/api/v1/quota [put]
+//	@Security		BearerAuth
 //	@Param			request	body		PutQuotaRequest	true	"put quota request"
 //	@Success		200		{object}	PutQuotaResponse
 //	@Failure		400		{object}	apperrors.AppError	"invalid param"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
 //	@Failure		500		{object}	apperrors.AppError	"internal system error"
 func PutQuota(c context.Context, ctx *app.RequestContext, handler command.PutHandler) {
 	var req PutQuotaRequest
@@ -78,12 +82,14 @@ func PutQuota(c context.Context, ctx *app.RequestContext, handler command.PutHan
 //	@Tags			quota
 //	@Produce		application/json
 //	@Router			/api/v1/quota [delete]
+//	@Security		BearerAuth
 //	@Param			global		query		bool	false	"query global quota"
 //	@Param			account_id	query		string	false	"query account quota"
 //	@Param			user_id		query		string	false	"query user quota"
 //	@Success		200			{object}	DeleteQuotaResponse
 //	@Failure		400			{object}	apperrors.AppError	"invalid param"
 //	@Failure		404			{object}	apperrors.AppError	"not found"
+//	@Failure		403			{object}	apperrors.AppError	"permission denied"
 //	@Failure		500			{object}	apperrors.AppError	"internal system error"
 func DeleteQuota(c context.Context, ctx *app.RequestContext, handler command.DeleteHandler) {
 	var req DeleteQuotaRequest
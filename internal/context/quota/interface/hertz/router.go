@@ -8,6 +8,7 @@ import (
 
 	"github.com/GBA-BI/tes-api/internal/context/quota/application"
 	"github.com/GBA-BI/tes-api/internal/context/quota/interface/hertz/handlers"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	"github.com/GBA-BI/tes-api/pkg/consts"
 	appserver "github.com/GBA-BI/tes-api/pkg/server"
 )
@@ -27,15 +28,15 @@ func NewRouterRegister(quotaService *application.QuotaService) appserver.RouteRe
 func (r *register) AddRoute(h route.IRouter) {
 	quota := h.Group(consts.OtherAPIPrefix + "/quota")
 
-	quota.GET("", func(c context.Context, ctx *app.RequestContext) {
+	quota.GET("", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.GetQuota(c, ctx, r.svc.QuotaQueries.Get)
 	})
 
-	quota.PUT("", func(c context.Context, ctx *app.RequestContext) {
+	quota.PUT("", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.PutQuota(c, ctx, r.svc.QuotaCommands.Put)
 	})
 
-	quota.DELETE("", func(c context.Context, ctx *app.RequestContext) {
+	quota.DELETE("", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.DeleteQuota(c, ctx, r.svc.QuotaCommands.Delete)
 	})
 }
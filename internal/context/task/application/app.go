@@ -7,11 +7,25 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/GBA-BI/tes-api/internal/apiserver/options"
+	clusterdomain "github.com/GBA-BI/tes-api/internal/context/cluster/domain"
+	clustersql "github.com/GBA-BI/tes-api/internal/context/cluster/infra/persistence/sql"
+	"github.com/GBA-BI/tes-api/internal/context/cluster/infra/reconcile"
+	extraprioritydomain "github.com/GBA-BI/tes-api/internal/context/extrapriority/domain"
+	extraprioritysql "github.com/GBA-BI/tes-api/internal/context/extrapriority/infra/persistence/sql"
+	quotadomain "github.com/GBA-BI/tes-api/internal/context/quota/domain"
+	quotasql "github.com/GBA-BI/tes-api/internal/context/quota/infra/persistence/sql"
 	"github.com/GBA-BI/tes-api/internal/context/task/application/command"
 	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
 	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/admission"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/capacity"
 	"github.com/GBA-BI/tes-api/internal/context/task/infra/normalize"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/notification"
 	"github.com/GBA-BI/tes-api/internal/context/task/infra/persistence/sql"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/priority"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/reclaim"
+	"github.com/GBA-BI/tes-api/internal/context/task/infra/retention"
+	webhookdomain "github.com/GBA-BI/tes-api/internal/context/webhook/domain"
 	"github.com/GBA-BI/tes-api/pkg/consts"
 )
 
@@ -19,28 +33,50 @@ import (
 type TaskService struct {
 	TaskCommands *command.Commands
 	TaskQueries  *query.Queries
+	// PriorityRefresher refreshes effective priorities of tasks when extra priorities change
+	PriorityRefresher extraprioritydomain.PriorityRefresher
+	// PriorityReconciler refreshes effective priorities of all executing tasks in background
+	PriorityReconciler *priority.Reconciler
+	// TaskReclaimer takes tasks back from unhealthy clusters
+	TaskReclaimer clusterdomain.TaskReclaimer
+	// NotificationSource provides notifications of finished tasks to webhooks
+	NotificationSource webhookdomain.NotificationSource
+	// Retainer moves finished tasks out of the task table in background
+	Retainer *retention.Retainer
 }
 
 // NewTaskService ...
 func NewTaskService(ctx context.Context, opts *options.Options) (*TaskService, error) {
 	var (
-		err       error
-		repo      domain.Repo
-		readModel query.ReadModel
+		err               error
+		repo              domain.Repo
+		readModel         query.ReadModel
+		quotaRepo         quotadomain.Repo
+		extraPriorityRepo extraprioritydomain.Repo
+		clusterRepo       clusterdomain.Repo
 	)
 
 	switch opts.DB.Type {
-	case consts.MySQLType:
+	case consts.MySQLType, consts.PostgresType, consts.SQLiteType:
 		var db *gorm.DB
-		if db, err = opts.DB.MySQL.GetGORMInstance(); err != nil {
+		if db, err = opts.DB.GetGORMInstance(); err != nil {
 			return nil, err
 		}
-		if repo, err = sql.NewRepo(ctx, db); err != nil {
+		if repo, err = sql.NewRepo(ctx, db, opts.Webhook.Enable); err != nil {
 			return nil, err
 		}
 		if readModel, err = sql.NewReadModel(ctx, db); err != nil {
 			return nil, err
 		}
+		if quotaRepo, err = quotasql.NewRepo(ctx, db); err != nil {
+			return nil, err
+		}
+		if extraPriorityRepo, err = extraprioritysql.NewRepo(ctx, db); err != nil {
+			return nil, err
+		}
+		if clusterRepo, err = clustersql.NewRepo(ctx, db); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported db type")
 	}
@@ -49,12 +85,23 @@ func NewTaskService(ctx context.Context, opts *options.Options) (*TaskService, e
 	if err != nil {
 		return nil, err
 	}
-	svc := domain.NewService(repo, normalizer)
+	admitter, err := admission.NewAdmitter(opts.Admission, quotadomain.NewService(quotaRepo), readModel)
+	if err != nil {
+		return nil, err
+	}
+	svc := domain.NewService(repo, normalizer, admitter,
+		priority.NewExtraPriorityGetter(extraPriorityRepo), capacity.NewClusterGetter(clusterRepo, readModel),
+		opts.Idempotency.Window)
 	taskCommands := command.NewCommands(svc)
 	taskQueries := query.NewQueries(readModel)
 
 	return &TaskService{
-		TaskCommands: taskCommands,
-		TaskQueries:  taskQueries,
+		TaskCommands:       taskCommands,
+		TaskQueries:        taskQueries,
+		PriorityRefresher:  priority.NewPriorityRefresher(svc),
+		PriorityReconciler: priority.NewReconciler(opts.Priority, svc),
+		TaskReclaimer:      reclaim.NewTaskReclaimer(svc, opts.Reconcile.TaskPolicy == reconcile.TaskPolicyRequeue),
+		NotificationSource: notification.NewNotificationSource(repo, readModel),
+		Retainer:           retention.NewRetainer(opts.Retention, svc),
 	}, nil
 }
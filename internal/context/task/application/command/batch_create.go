@@ -0,0 +1,103 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+// BatchCreateCommand ...
+type BatchCreateCommand struct {
+	// Tasks are validated one by one, their errors are returned by index
+	Tasks []*CreateCommand `validate:"gt=0,max=1000"`
+	Mode  string           `validate:"oneof=ALL_OR_NOTHING BEST_EFFORT"`
+}
+
+func (c *BatchCreateCommand) setDefault() {
+	if c.Mode == "" {
+		c.Mode = consts.AllOrNothingMode
+	}
+	for _, task := range c.Tasks {
+		if task != nil {
+			task.setDefault()
+		}
+	}
+}
+
+func (c *BatchCreateCommand) validate() error {
+	return validator.Validate(c)
+}
+
+// validateTask validates a task of the batch
+func validateTask(index int, task *CreateCommand) error {
+	if task == nil {
+		return apperrors.NewInvalidError(fmt.Sprintf("tasks[%d]", index))
+	}
+	// repeated batches cannot be deduplicated by tasks partially
+	if task.IdempotencyKey != "" {
+		return apperrors.NewInvalidError(fmt.Sprintf("tasks[%d].idempotency_key", index))
+	}
+	return task.validate()
+}
+
+// BatchCreateResult is the result of a task in the batch, ID is set if the task is created
+type BatchCreateResult struct {
+	ID  string
+	Err error
+}
+
+// BatchCreateHandler ...
+type BatchCreateHandler interface {
+	Handle(ctx context.Context, cmd *BatchCreateCommand) ([]*BatchCreateResult, error)
+}
+
+type batchCreateHandler struct {
+	svc domain.Service
+}
+
+var _ BatchCreateHandler = (*batchCreateHandler)(nil)
+
+// NewBatchCreateHandler ...
+func NewBatchCreateHandler(svc domain.Service) BatchCreateHandler {
+	return &batchCreateHandler{svc: svc}
+}
+
+// Handle ...
+func (h *batchCreateHandler) Handle(ctx context.Context, cmd *BatchCreateCommand) ([]*BatchCreateResult, error) {
+	cmd.setDefault()
+	if err := cmd.validate(); err != nil {
+		return nil, err
+	}
+	allOrNothing := cmd.Mode == consts.AllOrNothingMode
+
+	res := make([]*BatchCreateResult, len(cmd.Tasks))
+	indexes := make([]int, 0, len(cmd.Tasks))
+	tasks := make([]*domain.Task, 0, len(cmd.Tasks))
+	var invalid bool
+	for index, task := range cmd.Tasks {
+		res[index] = &BatchCreateResult{}
+		if res[index].Err = validateTask(index, task); res[index].Err != nil {
+			invalid = true
+			continue
+		}
+		indexes = append(indexes, index)
+		tasks = append(tasks, task.toDO())
+	}
+	if len(tasks) == 0 || (invalid && allOrNothing) {
+		return res, nil
+	}
+
+	results, err := h.svc.BatchCreate(ctx, tasks, allOrNothing)
+	if err != nil {
+		return nil, err
+	}
+	for i, index := range indexes {
+		res[index].ID = results[i].ID
+		res[index].Err = results[i].Err
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestBatchCreate(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := domain.NewFakeService(ctrl)
+	fakeService.EXPECT().BatchCreate(gomock.Any(), gomock.Len(1), false).
+		Return([]*domain.CreateResult{{ID: "task-1234"}}, nil)
+
+	handler := NewBatchCreateHandler(fakeService)
+	res, err := handler.Handle(context.TODO(), &BatchCreateCommand{
+		Tasks: []*CreateCommand{
+			{Name: "invalid"},
+			{Name: "valid", Executors: []*Executor{{Image: "image:tag", Command: []string{"command"}}}},
+		},
+		Mode: "BEST_EFFORT",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(apperrors.IsCode(res[0].Err, apperrors.InvalidCode)).To(gomega.BeTrue())
+	g.Expect(res[1]).To(gomega.Equal(&BatchCreateResult{ID: "task-1234"}))
+}
+
+func TestBatchCreateAllOrNothing(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// nothing is created if any task is invalid
+	handler := NewBatchCreateHandler(domain.NewFakeService(ctrl))
+	res, err := handler.Handle(context.TODO(), &BatchCreateCommand{
+		Tasks: []*CreateCommand{
+			{Name: "invalid"},
+			{Name: "valid", Executors: []*Executor{{Image: "image:tag", Command: []string{"command"}}}},
+		},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(apperrors.IsCode(res[0].Err, apperrors.InvalidCode)).To(gomega.BeTrue())
+	g.Expect(res[1]).To(gomega.Equal(&BatchCreateResult{}))
+}
+
+func TestBatchCreateEmpty(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewBatchCreateHandler(domain.NewFakeService(ctrl))
+	_, err := handler.Handle(context.TODO(), &BatchCreateCommand{})
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
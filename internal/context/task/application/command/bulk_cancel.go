@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+// BulkCancelCommand ...
+type BulkCancelCommand struct {
+	BioosInfo *BulkCancelBioosInfo
+	// Tags must all be matched, empty value matches any value of the key
+	Tags  map[string]string `validate:"dive,keys,required,endkeys"`
+	State []string          `validate:"dive,oneof=QUEUED INITIALIZING RUNNING COMPLETE SYSTEM_ERROR EXECUTOR_ERROR CANCELING CANCELED PREEMPTED"`
+}
+
+// BulkCancelBioosInfo ...
+type BulkCancelBioosInfo struct {
+	AccountID    string
+	UserID       string
+	SubmissionID string
+	RunID        string
+}
+
+func (c *BulkCancelCommand) setDefault() {
+	if c.BioosInfo == nil {
+		c.BioosInfo = &BulkCancelBioosInfo{}
+	}
+}
+
+func (c *BulkCancelCommand) validate() error {
+	if err := validator.Validate(c); err != nil {
+		return err
+	}
+	if c.BioosInfo.UserID != "" && c.BioosInfo.AccountID == "" {
+		return apperrors.NewInvalidError("bioos_info.account_id")
+	}
+	// all tasks shall not be canceled by an empty filter
+	if c.BioosInfo.AccountID == "" && c.BioosInfo.SubmissionID == "" && c.BioosInfo.RunID == "" && len(c.Tags) == 0 {
+		return apperrors.NewInvalidError("bioos_info", "tags")
+	}
+	return nil
+}
+
+func (c *BulkCancelCommand) toDO() *domain.CancelFilter {
+	return &domain.CancelFilter{
+		AccountID:    c.BioosInfo.AccountID,
+		UserID:       c.BioosInfo.UserID,
+		SubmissionID: c.BioosInfo.SubmissionID,
+		RunID:        c.BioosInfo.RunID,
+		Tags:         c.Tags,
+		State:        c.State,
+	}
+}
+
+// BulkCancelResult ...
+type BulkCancelResult struct {
+	Canceled        int
+	AlreadyFinished int
+	Failed          int
+	FailedIDs       []string
+}
+
+// BulkCancelHandler ...
+type BulkCancelHandler interface {
+	Handle(ctx context.Context, cmd *BulkCancelCommand) (*BulkCancelResult, error)
+}
+
+type bulkCancelHandler struct {
+	svc domain.Service
+}
+
+var _ BulkCancelHandler = (*bulkCancelHandler)(nil)
+
+// NewBulkCancelHandler ...
+func NewBulkCancelHandler(svc domain.Service) BulkCancelHandler {
+	return &bulkCancelHandler{svc: svc}
+}
+
+// Handle ...
+func (h *bulkCancelHandler) Handle(ctx context.Context, cmd *BulkCancelCommand) (*BulkCancelResult, error) {
+	cmd.setDefault()
+	if err := cmd.validate(); err != nil {
+		return nil, err
+	}
+	res, err := h.svc.BulkCancel(ctx, cmd.toDO())
+	if err != nil {
+		return nil, err
+	}
+	return &BulkCancelResult{
+		Canceled:        res.Canceled,
+		AlreadyFinished: res.AlreadyFinished,
+		Failed:          res.Failed,
+		FailedIDs:       res.FailedIDs,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestBulkCancel(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := domain.NewFakeService(ctrl)
+	fakeService.EXPECT().BulkCancel(gomock.Any(), &domain.CancelFilter{
+		AccountID:    "account-01",
+		SubmissionID: "submission-01",
+		Tags:         map[string]string{"key": ""},
+	}).Return(&domain.CancelResult{Canceled: 2, AlreadyFinished: 1}, nil)
+
+	handler := NewBulkCancelHandler(fakeService)
+	res, err := handler.Handle(context.TODO(), &BulkCancelCommand{
+		BioosInfo: &BulkCancelBioosInfo{AccountID: "account-01", SubmissionID: "submission-01"},
+		Tags:      map[string]string{"key": ""},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(res).To(gomega.Equal(&BulkCancelResult{Canceled: 2, AlreadyFinished: 1}))
+}
+
+func TestBulkCancelInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *BulkCancelCommand
+	}{
+		{
+			name: "empty filter",
+			cmd:  &BulkCancelCommand{State: []string{"QUEUED"}},
+		},
+		{
+			name: "user without account",
+			cmd:  &BulkCancelCommand{BioosInfo: &BulkCancelBioosInfo{UserID: "user-01", RunID: "run-01"}},
+		},
+		{
+			name: "invalid state",
+			cmd:  &BulkCancelCommand{BioosInfo: &BulkCancelBioosInfo{RunID: "run-01"}, State: []string{"UNKNOWN"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := NewBulkCancelHandler(domain.NewFakeService(ctrl))
+			_, err := handler.Handle(context.TODO(), test.cmd)
+			g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+		})
+	}
+}
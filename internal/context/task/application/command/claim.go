@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+const defaultClaimLimit = 1
+
+// ClaimCommand ...
+type ClaimCommand struct {
+	ClusterID string `validate:"required"`
+	Limit     int    `validate:"gte=0,lte=256"`
+}
+
+func (c *ClaimCommand) setDefault() {
+	if c.Limit == 0 {
+		c.Limit = defaultClaimLimit
+	}
+}
+
+func (c *ClaimCommand) validate() error {
+	return validator.Validate(c)
+}
+
+// ClaimHandler ...
+type ClaimHandler interface {
+	Handle(ctx context.Context, cmd *ClaimCommand) ([]string, error)
+}
+
+type claimHandler struct {
+	svc domain.Service
+}
+
+var _ ClaimHandler = (*claimHandler)(nil)
+
+// NewClaimHandler ...
+func NewClaimHandler(svc domain.Service) ClaimHandler {
+	return &claimHandler{svc: svc}
+}
+
+// Handle ...
+func (h *claimHandler) Handle(ctx context.Context, cmd *ClaimCommand) ([]string, error) {
+	cmd.setDefault()
+	if err := cmd.validate(); err != nil {
+		return nil, err
+	}
+	return h.svc.Claim(ctx, cmd.ClusterID, cmd.Limit)
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestClaim(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := domain.NewFakeService(ctrl)
+	fakeService.EXPECT().Claim(gomock.Any(), "cluster-01", defaultClaimLimit).
+		Return([]string{"task-1111"}, nil)
+
+	handler := NewClaimHandler(fakeService)
+	ids, err := handler.Handle(context.TODO(), &ClaimCommand{ClusterID: "cluster-01"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(ids).To(gomega.Equal([]string{"task-1111"}))
+}
+
+func TestClaimInvalid(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewClaimHandler(domain.NewFakeService(ctrl))
+	_, err := handler.Handle(context.TODO(), &ClaimCommand{Limit: 10})
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
@@ -4,16 +4,24 @@ import "github.com/GBA-BI/tes-api/internal/context/task/domain"
 
 // Commands ...
 type Commands struct {
-	Create CreateHandler
-	Cancel CancelHandler
-	Update UpdateHandler
+	Create      CreateHandler
+	BatchCreate BatchCreateHandler
+	Resubmit    ResubmitHandler
+	Cancel      CancelHandler
+	Update      UpdateHandler
+	Claim       ClaimHandler
+	BulkCancel  BulkCancelHandler
 }
 
 // NewCommands ...
 func NewCommands(svc domain.Service) *Commands {
 	return &Commands{
-		Create: NewCreateHandler(svc),
-		Cancel: NewCancelHandler(svc),
-		Update: NewUpdateHandler(svc),
+		Create:      NewCreateHandler(svc),
+		BatchCreate: NewBatchCreateHandler(svc),
+		Resubmit:    NewResubmitHandler(svc),
+		Cancel:      NewCancelHandler(svc),
+		Update:      NewUpdateHandler(svc),
+		Claim:       NewClaimHandler(svc),
+		BulkCancel:  NewBulkCancelHandler(svc),
 	}
 }
@@ -2,12 +2,14 @@ package command
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/onsi/gomega"
 
 	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
@@ -49,13 +51,14 @@ func TestCreate(t *testing.T) {
 			},
 		},
 		Executors: []*Executor{{
-			Image:   "image:tag",
-			Command: []string{"command"},
-			Workdir: "/base/workdir",
-			Stdin:   "/base/stdin",
-			Stdout:  "/base/stdout",
-			Stderr:  "/base/stderr",
-			Env:     map[string]string{"abc": "def"},
+			Image:       "image:tag",
+			Command:     []string{"command"},
+			Workdir:     "/base/workdir",
+			Stdin:       "/base/stdin",
+			Stdout:      "/base/stdout",
+			Stderr:      "/base/stderr",
+			Env:         map[string]string{"abc": "def"},
+			IgnoreError: true,
 		}},
 		Volumes: []string{"volume"},
 		Tags:    map[string]string{"kkk": "vvv"},
@@ -83,3 +86,19 @@ func TestCreate(t *testing.T) {
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(id).To(gomega.Equal("task-1234"))
 }
+
+func TestCreateIdempotencyKeyTooLong(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewCreateHandler(domain.NewFakeService(ctrl))
+	_, err := handler.Handle(context.TODO(), &CreateCommand{
+		Executors: []*Executor{{
+			Image:   "image:tag",
+			Command: []string{"command"},
+		}},
+		IdempotencyKey: strings.Repeat("k", 129),
+	})
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
@@ -17,9 +17,15 @@ type CreateCommand struct {
 	Resources     *Resources
 	Executors     []*Executor `validate:"gt=0,dive"`
 	Volumes       []string
-	Tags          map[string]string
+	Tags          map[string]string `validate:"dive,keys,required,max=128,endkeys,max=512"`
 	BioosInfo     *BioosInfo
 	PriorityValue int
+	// CallbackURL is notified when the task is finished
+	CallbackURL string `validate:"omitempty,http_url,max=1024"`
+	// MaxRetries is how many times SYSTEM_ERROR is retried, the policy of the account or server is applied if it is not set
+	MaxRetries *int `validate:"omitempty,gte=0,lte=10"`
+	// IdempotencyKey makes repeated creations of the same owner return the task created first
+	IdempotencyKey string `validate:"max=128"`
 }
 
 // Input ...
@@ -43,11 +49,15 @@ type Output struct {
 
 // Resources ...
 type Resources struct {
-	CPUCores   int     `validate:"gte=0"`
-	RamGB      float64 `validate:"gte=0"` // nolint
-	DiskGB     float64 `validate:"gte=0"`
-	BootDiskGB *int    `validate:"omitempty,gte=0"`
-	GPU        *GPUResource
+	CPUCores                int     `validate:"gte=0"`
+	RamGB                   float64 `validate:"gte=0"` // nolint
+	DiskGB                  float64 `validate:"gte=0"`
+	BootDiskGB              *int    `validate:"omitempty,gte=0"`
+	GPU                     *GPUResource
+	Preemptible             bool
+	Zones                   []string          `validate:"dive,required"`
+	BackendParameters       map[string]string `validate:"dive,keys,required,endkeys"`
+	BackendParametersStrict bool
 }
 
 // GPUResource ...
@@ -58,13 +68,14 @@ type GPUResource struct {
 
 // Executor ...
 type Executor struct {
-	Image   string   `validate:"required"`
-	Command []string `validate:"gt=0,dive,required"`
-	Workdir string   `validate:"omitempty,abspath"`
-	Stdin   string   `validate:"omitempty,abspath"`
-	Stdout  string   `validate:"omitempty,abspath"`
-	Stderr  string   `validate:"omitempty,abspath"`
-	Env     map[string]string
+	Image       string   `validate:"required"`
+	Command     []string `validate:"gt=0,dive,required"`
+	Workdir     string   `validate:"omitempty,abspath"`
+	Stdin       string   `validate:"omitempty,abspath"`
+	Stdout      string   `validate:"omitempty,abspath"`
+	Stderr      string   `validate:"omitempty,abspath"`
+	Env         map[string]string
+	IgnoreError bool
 }
 
 // BioosInfo ...
@@ -116,6 +127,11 @@ func (c *CreateCommand) toDO() *domain.Task {
 		Tags:          c.Tags,
 		BioosInfo:     c.BioosInfo.toDO(),
 		PriorityValue: c.PriorityValue,
+		CallbackURL:   c.CallbackURL,
+		Attempt:       1,
+
+		RequestedMaxRetries: c.MaxRetries,
+		IdempotencyKey:      c.IdempotencyKey,
 	}
 	if len(c.Inputs) > 0 {
 		res.Inputs = make([]*domain.Input, len(c.Inputs))
@@ -170,10 +186,14 @@ func (r *Resources) toDO() *domain.Resources {
 		return nil
 	}
 	res := &domain.Resources{
-		CPUCores:   r.CPUCores,
-		RamGB:      r.RamGB,
-		DiskGB:     r.DiskGB,
-		BootDiskGB: r.BootDiskGB,
+		CPUCores:                r.CPUCores,
+		RamGB:                   r.RamGB,
+		DiskGB:                  r.DiskGB,
+		BootDiskGB:              r.BootDiskGB,
+		Preemptible:             r.Preemptible,
+		Zones:                   r.Zones,
+		BackendParameters:       r.BackendParameters,
+		BackendParametersStrict: r.BackendParametersStrict,
 	}
 	if r.GPU != nil {
 		res.GPU = &domain.GPUResource{Count: r.GPU.Count, Type: r.GPU.Type}
@@ -186,13 +206,14 @@ func (e *Executor) toDO() *domain.Executor {
 		return nil
 	}
 	return &domain.Executor{
-		Image:   e.Image,
-		Command: e.Command,
-		Workdir: e.Workdir,
-		Stdin:   e.Stdin,
-		Stdout:  e.Stdout,
-		Stderr:  e.Stderr,
-		Env:     e.Env,
+		Image:       e.Image,
+		Command:     e.Command,
+		Workdir:     e.Workdir,
+		Stdin:       e.Stdin,
+		Stdout:      e.Stdout,
+		Stderr:      e.Stderr,
+		Env:         e.Env,
+		IgnoreError: e.IgnoreError,
 	}
 }
 
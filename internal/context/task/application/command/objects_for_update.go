@@ -11,8 +11,11 @@ import (
 type UpdateCommand struct {
 	ID        string `validate:"required"`
 	ClusterID *string
-	State     *string    `validate:"omitempty,oneof=QUEUED INITIALIZING RUNNING COMPLETE SYSTEM_ERROR EXECUTOR_ERROR CANCELING CANCELED"`
+	State     *string    `validate:"omitempty,oneof=QUEUED INITIALIZING RUNNING COMPLETE SYSTEM_ERROR EXECUTOR_ERROR CANCELING CANCELED PREEMPTED"`
 	Logs      []*TaskLog `validate:"unique=ClusterID,dive"`
+	// AssignedClusterID restricts the update to the task assigned to the cluster,
+	// it is set by the caller scope rather than the request
+	AssignedClusterID string
 }
 
 // TaskLog ...
@@ -22,6 +25,14 @@ type TaskLog struct {
 	StartTime  *time.Time
 	EndTime    *time.Time
 	SystemLogs []string
+	Outputs    []*OutputFileLog `validate:"unique=Path,dive"`
+}
+
+// OutputFileLog ...
+type OutputFileLog struct {
+	URL       string `validate:"required"`
+	Path      string `validate:"required"`
+	SizeBytes int64  `validate:"gte=0"`
 }
 
 // ExecutorLog ...
@@ -29,6 +40,9 @@ type ExecutorLog struct {
 	ExecutorID string `validate:"required"`
 	StartTime  *time.Time
 	EndTime    *time.Time
+	ExitCode   *int32
+	Stdout     string
+	Stderr     string
 }
 
 func (c *UpdateCommand) setDefault() {}
@@ -71,12 +85,32 @@ func (t *TaskLog) toDO() *domain.TaskLog {
 			}
 		}
 	}
+	if len(t.Outputs) > 0 {
+		res.Outputs = make([]*domain.OutputFileLog, len(t.Outputs))
+		for index, output := range t.Outputs {
+			res.Outputs[index] = output.toDO()
+		}
+	}
 	return res
 }
 
+func (o *OutputFileLog) toDO() *domain.OutputFileLog {
+	if o == nil {
+		return nil
+	}
+	return &domain.OutputFileLog{URL: o.URL, Path: o.Path, SizeBytes: o.SizeBytes}
+}
+
 func (e *ExecutorLog) toDO() *domain.ExecutorLog {
 	if e == nil {
 		return nil
 	}
-	return &domain.ExecutorLog{ExecutorID: e.ExecutorID, StartTime: e.StartTime, EndTime: e.EndTime}
+	return &domain.ExecutorLog{
+		ExecutorID: e.ExecutorID,
+		StartTime:  e.StartTime,
+		EndTime:    e.EndTime,
+		ExitCode:   e.ExitCode,
+		Stdout:     e.Stdout,
+		Stderr:     e.Stderr,
+	}
 }
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+// ResubmitCommand ...
+type ResubmitCommand struct {
+	ID string `validate:"required"`
+	// Resources overrides resources of the finished task if set
+	Resources *ResubmitResources
+}
+
+// ResubmitResources ...
+type ResubmitResources struct {
+	CPUCores    *int     `validate:"omitempty,gte=0"`
+	RamGB       *float64 `validate:"omitempty,gte=0"` // nolint
+	DiskGB      *float64 `validate:"omitempty,gte=0"`
+	BootDiskGB  *int     `validate:"omitempty,gte=0"`
+	Preemptible *bool
+}
+
+func (c *ResubmitCommand) setDefault() {}
+
+func (c *ResubmitCommand) validate() error {
+	return validator.Validate(c)
+}
+
+func (r *ResubmitResources) toDO() *domain.ResourcesOverride {
+	if r == nil {
+		return nil
+	}
+	return &domain.ResourcesOverride{
+		CPUCores:    r.CPUCores,
+		RamGB:       r.RamGB,
+		DiskGB:      r.DiskGB,
+		BootDiskGB:  r.BootDiskGB,
+		Preemptible: r.Preemptible,
+	}
+}
+
+// ResubmitHandler ...
+type ResubmitHandler interface {
+	Handle(ctx context.Context, cmd *ResubmitCommand) (string, error)
+}
+
+type resubmitHandler struct {
+	svc domain.Service
+}
+
+var _ ResubmitHandler = (*resubmitHandler)(nil)
+
+// NewResubmitHandler ...
+func NewResubmitHandler(svc domain.Service) ResubmitHandler {
+	return &resubmitHandler{svc: svc}
+}
+
+// Handle ...
+func (h *resubmitHandler) Handle(ctx context.Context, cmd *ResubmitCommand) (string, error) {
+	cmd.setDefault()
+	if err := cmd.validate(); err != nil {
+		return "", err
+	}
+	return h.svc.Resubmit(ctx, cmd.ID, cmd.Resources.toDO())
+}
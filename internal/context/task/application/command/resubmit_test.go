@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func TestResubmit(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := domain.NewFakeService(ctrl)
+	fakeService.EXPECT().Resubmit(gomock.Any(), "task-1111", &domain.ResourcesOverride{RamGB: utils.Point(16.0)}).
+		Return("task-2222", nil)
+
+	handler := NewResubmitHandler(fakeService)
+	id, err := handler.Handle(context.TODO(), &ResubmitCommand{
+		ID:        "task-1111",
+		Resources: &ResubmitResources{RamGB: utils.Point(16.0)},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(id).To(gomega.Equal("task-2222"))
+}
+
+func TestResubmitInvalid(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewResubmitHandler(domain.NewFakeService(ctrl))
+	_, err := handler.Handle(context.TODO(), &ResubmitCommand{
+		ID:        "task-1111",
+		Resources: &ResubmitResources{CPUCores: utils.Point(-1)},
+	})
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
@@ -28,5 +28,5 @@ func (h *updateHandler) Handle(ctx context.Context, cmd *UpdateCommand) error {
 	if err := cmd.validate(); err != nil {
 		return err
 	}
-	return h.svc.Update(ctx, cmd.ID, cmd.State, cmd.ClusterID, taskLogs(cmd.Logs).toDO())
+	return h.svc.Update(ctx, cmd.ID, cmd.AssignedClusterID, cmd.State, cmd.ClusterID, taskLogs(cmd.Logs).toDO())
 }
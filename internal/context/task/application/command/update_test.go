@@ -10,6 +10,7 @@ import (
 
 	"github.com/GBA-BI/tes-api/internal/context/task/domain"
 	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
@@ -21,7 +22,7 @@ func TestUpdate(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Second)
 
 	fakeService := domain.NewFakeService(ctrl)
-	fakeService.EXPECT().Update(gomock.Any(), "task-1111", utils.Point(consts.TaskQueued), utils.Point(""), gomock.Any()).
+	fakeService.EXPECT().Update(gomock.Any(), "task-1111", "", utils.Point(consts.TaskQueued), utils.Point(""), gomock.Any()).
 		Return(nil)
 
 	handler := NewUpdateHandler(fakeService)
@@ -43,3 +44,24 @@ func TestUpdate(t *testing.T) {
 	})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
+
+func TestUpdateDuplicateOutputs(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := domain.NewFakeService(ctrl)
+
+	handler := NewUpdateHandler(fakeService)
+	err := handler.Handle(context.TODO(), &UpdateCommand{
+		ID: "task-1111",
+		Logs: []*TaskLog{{
+			ClusterID: "cluster-01",
+			Outputs: []*OutputFileLog{
+				{URL: "s3://bucket/a.txt", Path: "/cromwell-executions/a.txt", SizeBytes: 1},
+				{URL: "s3://bucket/b.txt", Path: "/cromwell-executions/a.txt", SizeBytes: 2},
+			},
+		}},
+	})
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
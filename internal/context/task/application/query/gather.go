@@ -14,11 +14,12 @@ type GatherQuery struct {
 
 // GatherFilter ...
 type GatherFilter struct {
-	State       []string `validate:"dive,oneof=QUEUED INITIALIZING RUNNING COMPLETE SYSTEM_ERROR EXECUTOR_ERROR CANCELING CANCELED"`
+	State       []string `validate:"dive,oneof=QUEUED INITIALIZING RUNNING COMPLETE SYSTEM_ERROR EXECUTOR_ERROR CANCELING CANCELED PREEMPTED"`
 	ClusterID   string
 	WithCluster bool
 	AccountID   string
 	UserID      string
+	QuotaHeld   *bool
 }
 
 func (q *GatherQuery) setDefault() {}
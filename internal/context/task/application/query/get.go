@@ -12,6 +12,8 @@ import (
 type GetQuery struct {
 	ID   string `validate:"required"`
 	View string `validate:"oneof=MINIMAL BASIC FULL"`
+	// AccountID restricts the task to the account, it is set by the caller scope rather than the request
+	AccountID string
 }
 
 func (q *GetQuery) setDefault() {
@@ -49,6 +51,17 @@ func (h *getHandler) Handle(ctx context.Context, query *GetQuery) (*Task, error)
 
 	switch query.View {
 	case consts.MinimalView:
+		// the minimal view has no owner, so the basic view is read to check the account
+		if query.AccountID != "" {
+			resBasic, err := h.readModel.GetBasic(ctx, query.ID)
+			if err != nil {
+				return nil, err
+			}
+			if err = checkAccount(resBasic, query.AccountID); err != nil {
+				return nil, err
+			}
+			return &Task{TaskBasic: TaskBasic{TaskMinimal: resBasic.TaskMinimal}}, nil
+		}
 		resMinimal, err := h.readModel.GetMinimal(ctx, query.ID)
 		if err != nil {
 			return nil, err
@@ -59,19 +72,37 @@ func (h *getHandler) Handle(ctx context.Context, query *GetQuery) (*Task, error)
 		if err != nil {
 			return nil, err
 		}
+		if err = checkAccount(resBasic, query.AccountID); err != nil {
+			return nil, err
+		}
 		removeSystemLogs(resBasic)
+		removeExecutorOutputs(resBasic)
 		return &Task{TaskBasic: *resBasic}, nil
 	case consts.FullView:
 		res, err := h.readModel.GetFull(ctx, query.ID)
 		if err != nil {
 			return nil, err
 		}
+		if err = checkAccount(&res.TaskBasic, query.AccountID); err != nil {
+			return nil, err
+		}
 		return res, nil
 	default:
 		return nil, apperrors.NewInvalidError("view")
 	}
 }
 
+// checkAccount hides tasks of other accounts as not found, so that their existence is not leaked
+func checkAccount(task *TaskBasic, accountID string) error {
+	if accountID == "" {
+		return nil
+	}
+	if task.BioosInfo == nil || task.BioosInfo.AccountID != accountID {
+		return apperrors.NewNotFoundError("task", task.ID)
+	}
+	return nil
+}
+
 func removeSystemLogs(task *TaskBasic) {
 	if task == nil {
 		return
@@ -82,3 +113,24 @@ func removeSystemLogs(task *TaskBasic) {
 		}
 	}
 }
+
+// removeExecutorOutputs removes stdout/stderr of executors, which are only shown in FULL view,
+// output file logs are kept in BASIC view
+func removeExecutorOutputs(task *TaskBasic) {
+	if task == nil {
+		return
+	}
+	for _, taskLog := range task.Logs {
+		if taskLog == nil {
+			continue
+		}
+		for _, executorLogs := range taskLog.Logs {
+			for _, executorLog := range executorLogs {
+				if executorLog != nil {
+					executorLog.Stdout = ""
+					executorLog.Stderr = ""
+				}
+			}
+		}
+	}
+}
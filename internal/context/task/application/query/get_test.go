@@ -8,6 +8,8 @@ import (
 	"github.com/onsi/gomega"
 
 	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
 func TestGetMinimal(t *testing.T) {
@@ -44,7 +46,11 @@ func TestGetBasic(t *testing.T) {
 	fakeReadModel.EXPECT().GetBasic(gomock.Any(), "task-1234").
 		Return(&TaskBasic{
 			TaskMinimal: TaskMinimal{ID: "task-1234", State: consts.TaskComplete},
-			Logs:        []*TaskLog{{SystemLogs: []string{"abcd"}}},
+			Logs: []*TaskLog{{
+				Logs:       [][]*ExecutorLog{{{ExecutorID: "ex-01", ExitCode: utils.Point[int32](1), Stdout: "out", Stderr: "err"}}},
+				SystemLogs: []string{"abcd"},
+				Outputs:    []*OutputFileLog{{URL: "s3://bucket/a.txt", Path: "/cromwell-executions/a.txt", SizeBytes: 1}},
+			}},
 		}, nil)
 
 	handler := NewGetHandler(fakeReadModel)
@@ -59,7 +65,10 @@ func TestGetBasic(t *testing.T) {
 				ID:    "task-1234",
 				State: consts.TaskComplete,
 			},
-			Logs: []*TaskLog{{}}, // no systemLogs
+			Logs: []*TaskLog{{ // no systemLogs, stdout and stderr
+				Logs:    [][]*ExecutorLog{{{ExecutorID: "ex-01", ExitCode: utils.Point[int32](1)}}},
+				Outputs: []*OutputFileLog{{URL: "s3://bucket/a.txt", Path: "/cromwell-executions/a.txt", SizeBytes: 1}},
+			}},
 		},
 	}))
 }
@@ -94,3 +103,38 @@ func TestGetFull(t *testing.T) {
 		},
 	}))
 }
+
+func TestGetWithAccount(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeReadModel := NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GetBasic(gomock.Any(), "task-1234").
+		Return(&TaskBasic{
+			TaskMinimal: TaskMinimal{ID: "task-1234", State: consts.TaskComplete},
+			BioosInfo:   &BioosInfo{AccountID: "account-01"},
+		}, nil).Times(2)
+
+	handler := NewGetHandler(fakeReadModel)
+	resp, err := handler.Handle(context.TODO(), &GetQuery{
+		ID:        "task-1234",
+		AccountID: "account-01",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo(&Task{
+		TaskBasic: TaskBasic{
+			TaskMinimal: TaskMinimal{
+				ID:    "task-1234",
+				State: consts.TaskComplete,
+			},
+		},
+	}))
+
+	_, err = handler.Handle(context.TODO(), &GetQuery{
+		ID:        "task-1234",
+		View:      consts.BasicView,
+		AccountID: "account-02",
+	})
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
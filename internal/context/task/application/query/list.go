@@ -11,20 +11,35 @@ import (
 
 const defaultPageSize = 256
 
+const (
+	// SortByID sorts tasks by id
+	SortByID = "ID"
+	// SortByPriority sorts tasks by effective priority descending, then by creation time
+	SortByPriority = "PRIORITY"
+)
+
 // ListQuery ...
 type ListQuery struct {
 	View      string `validate:"oneof=MINIMAL BASIC FULL"`
 	PageSize  int    `validate:"gte=0,lte=2048"`
 	PageToken *utils.PageToken
+	SortBy    string `validate:"oneof=ID PRIORITY"`
 	Filter    *ListFilter
 }
 
 // ListFilter ...
 type ListFilter struct {
 	NamePrefix     string
-	State          []string `validate:"dive,oneof=QUEUED INITIALIZING RUNNING COMPLETE SYSTEM_ERROR EXECUTOR_ERROR CANCELING CANCELED"`
+	State          []string `validate:"dive,oneof=QUEUED INITIALIZING RUNNING COMPLETE SYSTEM_ERROR EXECUTOR_ERROR CANCELING CANCELED PREEMPTED"`
 	ClusterID      string
 	WithoutCluster bool
+	QuotaHeld      *bool
+	// AccountID restricts tasks to the account, it is set by the caller scope rather than the request
+	AccountID string
+	// RetryOf lists resubmissions of the task
+	RetryOf string
+	// Tags must all be matched, empty value matches any value of the key
+	Tags map[string]string `validate:"dive,keys,required,endkeys"`
 }
 
 func (q *ListQuery) setDefault() {
@@ -34,12 +49,19 @@ func (q *ListQuery) setDefault() {
 	if q.PageSize == 0 {
 		q.PageSize = defaultPageSize
 	}
+	if q.SortBy == "" {
+		q.SortBy = SortByID
+	}
 }
 
 func (q *ListQuery) validate() error {
 	if err := validator.Validate(q); err != nil {
 		return err
 	}
+	if q.SortBy == SortByPriority && q.PageToken != nil &&
+		(q.PageToken.LastEffectivePriority == nil || q.PageToken.LastCreationTime == nil) {
+		return apperrors.NewInvalidError("page_token")
+	}
 	if q.Filter == nil {
 		return nil
 	}
@@ -74,7 +96,7 @@ func (h *listHandler) Handle(ctx context.Context, query *ListQuery) ([]*Task, *u
 
 	switch query.View {
 	case consts.MinimalView:
-		resMinimal, nextPageToken, err := h.readModel.ListMinimal(ctx, query.PageSize, query.PageToken, query.Filter)
+		resMinimal, nextPageToken, err := h.readModel.ListMinimal(ctx, query.PageSize, query.PageToken, query.SortBy, query.Filter)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -84,18 +106,19 @@ func (h *listHandler) Handle(ctx context.Context, query *ListQuery) ([]*Task, *u
 		}
 		return res, nextPageToken, nil
 	case consts.BasicView:
-		resBasic, nextPageToken, err := h.readModel.ListBasic(ctx, query.PageSize, query.PageToken, query.Filter)
+		resBasic, nextPageToken, err := h.readModel.ListBasic(ctx, query.PageSize, query.PageToken, query.SortBy, query.Filter)
 		if err != nil {
 			return nil, nil, err
 		}
 		res := make([]*Task, len(resBasic))
 		for index := range resBasic {
 			removeSystemLogs(resBasic[index])
+			removeExecutorOutputs(resBasic[index])
 			res[index] = &Task{TaskBasic: *resBasic[index]}
 		}
 		return res, nextPageToken, nil
 	case consts.FullView:
-		res, nextPageToken, err := h.readModel.ListFull(ctx, query.PageSize, query.PageToken, query.Filter)
+		res, nextPageToken, err := h.readModel.ListFull(ctx, query.PageSize, query.PageToken, query.SortBy, query.Filter)
 		if err != nil {
 			return nil, nil, err
 		}
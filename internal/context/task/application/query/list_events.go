@@ -0,0 +1,58 @@
+package query
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+// ListEventsQuery ...
+type ListEventsQuery struct {
+	ID string `validate:"required"`
+	// AccountID restricts the task to the account, it is set by the caller scope rather than the request
+	AccountID string
+}
+
+func (q *ListEventsQuery) setDefault() {}
+
+func (q *ListEventsQuery) validate() error {
+	return validator.Validate(q)
+}
+
+// ListEventsHandler ...
+type ListEventsHandler interface {
+	Handle(ctx context.Context, query *ListEventsQuery) ([]*TaskEvent, error)
+}
+
+type listEventsHandler struct {
+	readModel ReadModel
+}
+
+var _ ListEventsHandler = (*listEventsHandler)(nil)
+
+// NewListEventsHandler ...
+func NewListEventsHandler(readModel ReadModel) ListEventsHandler {
+	return &listEventsHandler{readModel: readModel}
+}
+
+// Handle ...
+func (h *listEventsHandler) Handle(ctx context.Context, query *ListEventsQuery) ([]*TaskEvent, error) {
+	query.setDefault()
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+	// make sure the task exists, otherwise empty events are returned,
+	// and the minimal view has no owner, so the basic view is read to check the account
+	if query.AccountID != "" {
+		task, err := h.readModel.GetBasic(ctx, query.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkAccount(task, query.AccountID); err != nil {
+			return nil, err
+		}
+	} else if _, err := h.readModel.GetMinimal(ctx, query.ID); err != nil {
+		return nil, err
+	}
+	return h.readModel.ListEvents(ctx, query.ID)
+}
@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestListEvents(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeReadModel := NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GetMinimal(gomock.Any(), "task-1111").
+		Return(&TaskMinimal{ID: "task-1111", State: consts.TaskQueued}, nil)
+	fakeReadModel.EXPECT().ListEvents(gomock.Any(), "task-1111").
+		Return([]*TaskEvent{{Time: now, State: consts.TaskQueued}}, nil)
+
+	handler := NewListEventsHandler(fakeReadModel)
+	resp, err := handler.Handle(context.TODO(), &ListEventsQuery{ID: "task-1111"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal([]*TaskEvent{{Time: now, State: consts.TaskQueued}}))
+}
+
+func TestListEventsNotFound(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeReadModel := NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GetMinimal(gomock.Any(), "task-1111").
+		Return(nil, apperrors.NewNotFoundError("task", "task-1111"))
+
+	handler := NewListEventsHandler(fakeReadModel)
+	_, err := handler.Handle(context.TODO(), &ListEventsQuery{ID: "task-1111"})
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
+
+func TestListEventsAccountScope(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeReadModel := NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GetBasic(gomock.Any(), "task-1111").
+		Return(&TaskBasic{TaskMinimal: TaskMinimal{ID: "task-1111"}, BioosInfo: &BioosInfo{AccountID: "account-01"}}, nil).Times(2)
+	fakeReadModel.EXPECT().ListEvents(gomock.Any(), "task-1111").
+		Return([]*TaskEvent{{Time: now, State: consts.TaskQueued}}, nil)
+
+	handler := NewListEventsHandler(fakeReadModel)
+	resp, err := handler.Handle(context.TODO(), &ListEventsQuery{ID: "task-1111", AccountID: "account-01"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal([]*TaskEvent{{Time: now, State: consts.TaskQueued}}))
+
+	// tasks of other accounts are not found
+	_, err = handler.Handle(context.TODO(), &ListEventsQuery{ID: "task-1111", AccountID: "account-02"})
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
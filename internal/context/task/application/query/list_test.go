@@ -8,6 +8,8 @@ import (
 	"github.com/onsi/gomega"
 
 	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
 func TestListMinimal(t *testing.T) {
@@ -16,7 +18,7 @@ func TestListMinimal(t *testing.T) {
 	defer ctrl.Finish()
 
 	fakeReadModel := NewFakeReadModel(ctrl)
-	fakeReadModel.EXPECT().ListMinimal(gomock.Any(), defaultPageSize, nil, &ListFilter{WithoutCluster: true}).
+	fakeReadModel.EXPECT().ListMinimal(gomock.Any(), defaultPageSize, nil, SortByID, &ListFilter{WithoutCluster: true}).
 		Return([]*TaskMinimal{{ID: "task-1111", State: consts.TaskQueued}}, nil, nil)
 
 	handler := NewListHandler(fakeReadModel)
@@ -37,13 +39,14 @@ func TestListBasic(t *testing.T) {
 	defer ctrl.Finish()
 
 	fakeReadModel := NewFakeReadModel(ctrl)
-	fakeReadModel.EXPECT().ListBasic(gomock.Any(), 1024, nil, nil).
+	fakeReadModel.EXPECT().ListBasic(gomock.Any(), 1024, nil, SortByPriority, nil).
 		Return([]*TaskBasic{{TaskMinimal: TaskMinimal{ID: "task-1111", State: consts.TaskQueued}}}, nil, nil)
 
 	handler := NewListHandler(fakeReadModel)
 	resp, nextPageToken, err := handler.Handle(context.TODO(), &ListQuery{
 		View:     consts.BasicView,
 		PageSize: 1024,
+		SortBy:   SortByPriority,
 	})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(resp).To(gomega.HaveLen(1))
@@ -56,7 +59,7 @@ func TestListFull(t *testing.T) {
 	defer ctrl.Finish()
 
 	fakeReadModel := NewFakeReadModel(ctrl)
-	fakeReadModel.EXPECT().ListFull(gomock.Any(), defaultPageSize, nil, nil).
+	fakeReadModel.EXPECT().ListFull(gomock.Any(), defaultPageSize, nil, SortByID, nil).
 		Return([]*Task{{TaskBasic: TaskBasic{TaskMinimal: TaskMinimal{ID: "task-1111", State: consts.TaskQueued}}}}, nil, nil)
 
 	handler := NewListHandler(fakeReadModel)
@@ -68,3 +71,28 @@ func TestListFull(t *testing.T) {
 	g.Expect(resp).To(gomega.HaveLen(1))
 	g.Expect(nextPageToken).To(gomega.BeNil())
 }
+
+func TestListInvalidPriorityPageToken(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewListHandler(NewFakeReadModel(ctrl))
+	_, _, err := handler.Handle(context.TODO(), &ListQuery{
+		PageToken: &utils.PageToken{LastID: "task-1111"},
+		SortBy:    SortByPriority,
+	})
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
+
+func TestListInvalidTags(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewListHandler(NewFakeReadModel(ctrl))
+	_, _, err := handler.Handle(context.TODO(), &ListQuery{
+		Filter: &ListFilter{Tags: map[string]string{"": "vvv"}},
+	})
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
@@ -21,7 +21,19 @@ type TaskBasic struct {
 	CreationTime  time.Time
 	BioosInfo     *BioosInfo
 	PriorityValue int
-	ClusterID     string
+	// EffectivePriority is PriorityValue plus extra priorities applied to the task
+	EffectivePriority int
+	ClusterID         string
+	QuotaHeld         bool
+	CallbackURL       string
+	// RetryOf is id of the task resubmitted as this one
+	RetryOf string
+	// Attempt is 1 for the first submission, and increased by each resubmission
+	Attempt int
+	// MaxRetries is how many times SYSTEM_ERROR is retried by requeueing the task
+	MaxRetries int
+	// Retries is how many times the task is requeued after SYSTEM_ERROR
+	Retries int
 }
 
 // Task ...
@@ -52,11 +64,15 @@ type Output struct {
 
 // Resources ...
 type Resources struct {
-	CPUCores   int
-	RamGB      float64 // nolint
-	DiskGB     float64
-	BootDiskGB *int
-	GPU        *GPUResource
+	CPUCores                int
+	RamGB                   float64 // nolint
+	DiskGB                  float64
+	BootDiskGB              *int
+	GPU                     *GPUResource
+	Preemptible             bool
+	Zones                   []string
+	BackendParameters       map[string]string
+	BackendParametersStrict bool
 }
 
 // GPUResource ...
@@ -67,13 +83,14 @@ type GPUResource struct {
 
 // Executor ...
 type Executor struct {
-	Image   string
-	Command []string
-	Workdir string
-	Stdin   string
-	Stdout  string
-	Stderr  string
-	Env     map[string]string
+	Image       string
+	Command     []string
+	Workdir     string
+	Stdin       string
+	Stdout      string
+	Stderr      string
+	Env         map[string]string
+	IgnoreError bool
 }
 
 // TaskLog ...
@@ -83,6 +100,14 @@ type TaskLog struct {
 	StartTime  *time.Time
 	EndTime    *time.Time
 	SystemLogs []string
+	Outputs    []*OutputFileLog
+}
+
+// OutputFileLog ...
+type OutputFileLog struct {
+	URL       string
+	Path      string
+	SizeBytes int64
 }
 
 // ExecutorLog ...
@@ -90,6 +115,9 @@ type ExecutorLog struct {
 	ExecutorID string
 	StartTime  *time.Time
 	EndTime    *time.Time
+	ExitCode   *int32
+	Stdout     string
+	Stderr     string
 }
 
 // BioosInfo ...
@@ -136,3 +164,19 @@ type AccountInfo struct {
 	AccountID string
 	UserIDs   []string
 }
+
+// TaskEvent ...
+type TaskEvent struct {
+	Time          time.Time
+	PreviousState string
+	State         string
+	ClusterID     string
+	RequestID     string
+}
+
+// WatchEvent is a TaskEvent in the change log
+type WatchEvent struct {
+	TaskEvent
+	ResourceVersion int64
+	TaskID          string
+}
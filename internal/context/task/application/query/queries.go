@@ -6,6 +6,8 @@ type Queries struct {
 	Get          GetHandler
 	Gather       GatherHandler
 	ListAccounts ListAccountsHandler
+	ListEvents   ListEventsHandler
+	Watch        WatchHandler
 }
 
 // NewQueries ...
@@ -15,5 +17,7 @@ func NewQueries(readModel ReadModel) *Queries {
 		Get:          NewGetHandler(readModel),
 		Gather:       NewGatherHandler(readModel),
 		ListAccounts: NewListAccountsHandler(readModel),
+		ListEvents:   NewListEventsHandler(readModel),
+		Watch:        NewWatchHandler(readModel),
 	}
 }
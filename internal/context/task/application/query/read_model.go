@@ -2,18 +2,30 @@ package query
 
 import (
 	"context"
+	"time"
 
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
 // ReadModel ...
 type ReadModel interface {
-	ListMinimal(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *ListFilter) ([]*TaskMinimal, *utils.PageToken, error)
-	ListBasic(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *ListFilter) ([]*TaskBasic, *utils.PageToken, error)
-	ListFull(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *ListFilter) ([]*Task, *utils.PageToken, error)
+	ListMinimal(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *ListFilter) ([]*TaskMinimal, *utils.PageToken, error)
+	ListBasic(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *ListFilter) ([]*TaskBasic, *utils.PageToken, error)
+	ListFull(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *ListFilter) ([]*Task, *utils.PageToken, error)
 	GetMinimal(ctx context.Context, id string) (*TaskMinimal, error)
 	GetBasic(ctx context.Context, id string) (*TaskBasic, error)
 	GetFull(ctx context.Context, id string) (*Task, error)
 	GatherResources(ctx context.Context, filter *GatherFilter) (*TasksResources, error)
 	ListAccounts(ctx context.Context) ([]*AccountInfo, error)
+	// ListEvents lists state transitions of the task in order
+	ListEvents(ctx context.Context, id string) ([]*TaskEvent, error)
+	// ListEventsAfter lists state transitions of tasks after the resource version and not after until in order
+	ListEventsAfter(ctx context.Context, resourceVersion, until int64, limit int, filter *WatchFilter) ([]*WatchEvent, error)
+	// GetSettledResourceVersion scans at most limit events after the resource version, and returns the latest
+	// resource version that no event can be committed before. Resource versions are assigned when events are
+	// inserted rather than committed, so a gap of resource versions is only skipped if the event after it
+	// happened before settledBefore, otherwise the missing events may still be committed.
+	GetSettledResourceVersion(ctx context.Context, resourceVersion int64, limit int, settledBefore time.Time) (int64, error)
+	// GetLatestResourceVersion returns resource version of the latest event, 0 if no event
+	GetLatestResourceVersion(ctx context.Context) (int64, error)
 }
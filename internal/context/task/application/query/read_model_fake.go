@@ -7,6 +7,7 @@ package query
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	utils "github.com/GBA-BI/tes-api/pkg/utils"
 	gomock "github.com/golang/mock/gomock"
@@ -80,6 +81,36 @@ func (mr *FakeReadModelMockRecorder) GetFull(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFull", reflect.TypeOf((*FakeReadModel)(nil).GetFull), ctx, id)
 }
 
+// GetLatestResourceVersion mocks base method.
+func (m *FakeReadModel) GetLatestResourceVersion(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestResourceVersion", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestResourceVersion indicates an expected call of GetLatestResourceVersion.
+func (mr *FakeReadModelMockRecorder) GetLatestResourceVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestResourceVersion", reflect.TypeOf((*FakeReadModel)(nil).GetLatestResourceVersion), ctx)
+}
+
+// GetSettledResourceVersion mocks base method.
+func (m *FakeReadModel) GetSettledResourceVersion(ctx context.Context, resourceVersion int64, limit int, settledBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettledResourceVersion", ctx, resourceVersion, limit, settledBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettledResourceVersion indicates an expected call of GetSettledResourceVersion.
+func (mr *FakeReadModelMockRecorder) GetSettledResourceVersion(ctx, resourceVersion, limit, settledBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettledResourceVersion", reflect.TypeOf((*FakeReadModel)(nil).GetSettledResourceVersion), ctx, resourceVersion, limit, settledBefore)
+}
+
 // GetMinimal mocks base method.
 func (m *FakeReadModel) GetMinimal(ctx context.Context, id string) (*TaskMinimal, error) {
 	m.ctrl.T.Helper()
@@ -111,9 +142,9 @@ func (mr *FakeReadModelMockRecorder) ListAccounts(ctx interface{}) *gomock.Call
 }
 
 // ListBasic mocks base method.
-func (m *FakeReadModel) ListBasic(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *ListFilter) ([]*TaskBasic, *utils.PageToken, error) {
+func (m *FakeReadModel) ListBasic(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *ListFilter) ([]*TaskBasic, *utils.PageToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBasic", ctx, pageSize, pageToken, filter)
+	ret := m.ctrl.Call(m, "ListBasic", ctx, pageSize, pageToken, sortBy, filter)
 	ret0, _ := ret[0].([]*TaskBasic)
 	ret1, _ := ret[1].(*utils.PageToken)
 	ret2, _ := ret[2].(error)
@@ -121,15 +152,45 @@ func (m *FakeReadModel) ListBasic(ctx context.Context, pageSize int, pageToken *
 }
 
 // ListBasic indicates an expected call of ListBasic.
-func (mr *FakeReadModelMockRecorder) ListBasic(ctx, pageSize, pageToken, filter interface{}) *gomock.Call {
+func (mr *FakeReadModelMockRecorder) ListBasic(ctx, pageSize, pageToken, sortBy, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBasic", reflect.TypeOf((*FakeReadModel)(nil).ListBasic), ctx, pageSize, pageToken, sortBy, filter)
+}
+
+// ListEvents mocks base method.
+func (m *FakeReadModel) ListEvents(ctx context.Context, id string) ([]*TaskEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, id)
+	ret0, _ := ret[0].([]*TaskEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *FakeReadModelMockRecorder) ListEvents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*FakeReadModel)(nil).ListEvents), ctx, id)
+}
+
+// ListEventsAfter mocks base method.
+func (m *FakeReadModel) ListEventsAfter(ctx context.Context, resourceVersion, until int64, limit int, filter *WatchFilter) ([]*WatchEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventsAfter", ctx, resourceVersion, until, limit, filter)
+	ret0, _ := ret[0].([]*WatchEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventsAfter indicates an expected call of ListEventsAfter.
+func (mr *FakeReadModelMockRecorder) ListEventsAfter(ctx, resourceVersion, until, limit, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBasic", reflect.TypeOf((*FakeReadModel)(nil).ListBasic), ctx, pageSize, pageToken, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventsAfter", reflect.TypeOf((*FakeReadModel)(nil).ListEventsAfter), ctx, resourceVersion, until, limit, filter)
 }
 
 // ListFull mocks base method.
-func (m *FakeReadModel) ListFull(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *ListFilter) ([]*Task, *utils.PageToken, error) {
+func (m *FakeReadModel) ListFull(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *ListFilter) ([]*Task, *utils.PageToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListFull", ctx, pageSize, pageToken, filter)
+	ret := m.ctrl.Call(m, "ListFull", ctx, pageSize, pageToken, sortBy, filter)
 	ret0, _ := ret[0].([]*Task)
 	ret1, _ := ret[1].(*utils.PageToken)
 	ret2, _ := ret[2].(error)
@@ -137,15 +198,15 @@ func (m *FakeReadModel) ListFull(ctx context.Context, pageSize int, pageToken *u
 }
 
 // ListFull indicates an expected call of ListFull.
-func (mr *FakeReadModelMockRecorder) ListFull(ctx, pageSize, pageToken, filter interface{}) *gomock.Call {
+func (mr *FakeReadModelMockRecorder) ListFull(ctx, pageSize, pageToken, sortBy, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFull", reflect.TypeOf((*FakeReadModel)(nil).ListFull), ctx, pageSize, pageToken, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFull", reflect.TypeOf((*FakeReadModel)(nil).ListFull), ctx, pageSize, pageToken, sortBy, filter)
 }
 
 // ListMinimal mocks base method.
-func (m *FakeReadModel) ListMinimal(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *ListFilter) ([]*TaskMinimal, *utils.PageToken, error) {
+func (m *FakeReadModel) ListMinimal(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *ListFilter) ([]*TaskMinimal, *utils.PageToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListMinimal", ctx, pageSize, pageToken, filter)
+	ret := m.ctrl.Call(m, "ListMinimal", ctx, pageSize, pageToken, sortBy, filter)
 	ret0, _ := ret[0].([]*TaskMinimal)
 	ret1, _ := ret[1].(*utils.PageToken)
 	ret2, _ := ret[2].(error)
@@ -153,7 +214,7 @@ func (m *FakeReadModel) ListMinimal(ctx context.Context, pageSize int, pageToken
 }
 
 // ListMinimal indicates an expected call of ListMinimal.
-func (mr *FakeReadModelMockRecorder) ListMinimal(ctx, pageSize, pageToken, filter interface{}) *gomock.Call {
+func (mr *FakeReadModelMockRecorder) ListMinimal(ctx, pageSize, pageToken, sortBy, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMinimal", reflect.TypeOf((*FakeReadModel)(nil).ListMinimal), ctx, pageSize, pageToken, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMinimal", reflect.TypeOf((*FakeReadModel)(nil).ListMinimal), ctx, pageSize, pageToken, sortBy, filter)
 }
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+const (
+	watchPollInterval = time.Second
+	watchBatchSize    = 500
+	watchScanSize     = 5000
+	// watchSettleWindow is longer than transactions saving events, events committed out of
+	// order of their resource versions are waited for within it
+	watchSettleWindow = 30 * time.Second
+)
+
+// WatchQuery ...
+type WatchQuery struct {
+	Filter *WatchFilter
+	// ResourceVersion is the last event received, nil means watching from now on
+	ResourceVersion *int64 `validate:"omitempty,gte=0"`
+}
+
+// WatchFilter ...
+type WatchFilter struct {
+	IDs       []string `validate:"lte=1000"`
+	AccountID string
+	UserID    string
+	RunID     string
+}
+
+func (q *WatchQuery) setDefault() {}
+
+func (q *WatchQuery) validate() error {
+	if err := validator.Validate(q); err != nil {
+		return err
+	}
+	if q.Filter == nil {
+		return nil
+	}
+	if q.Filter.AccountID == "" && q.Filter.UserID != "" {
+		return apperrors.NewInvalidError("empty account_id with non-empty user_id")
+	}
+	return nil
+}
+
+// WatchHandler ...
+type WatchHandler interface {
+	// Handle polls the change log and sends events in order until ctx is done or send fails,
+	// send is called with empty events if nothing changed since last poll.
+	Handle(ctx context.Context, query *WatchQuery, send func([]*WatchEvent) error) error
+}
+
+type watchHandler struct {
+	readModel ReadModel
+}
+
+var _ WatchHandler = (*watchHandler)(nil)
+
+// NewWatchHandler ...
+func NewWatchHandler(readModel ReadModel) WatchHandler {
+	return &watchHandler{readModel: readModel}
+}
+
+// Handle ...
+func (h *watchHandler) Handle(ctx context.Context, query *WatchQuery, send func([]*WatchEvent) error) error {
+	query.setDefault()
+	if err := query.validate(); err != nil {
+		return err
+	}
+
+	var resourceVersion int64
+	if query.ResourceVersion != nil {
+		resourceVersion = *query.ResourceVersion
+	} else {
+		latest, err := h.readModel.GetLatestResourceVersion(ctx)
+		if err != nil {
+			return err
+		}
+		resourceVersion = latest
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		// only settled events are sent, so that resuming after any sent event never misses one
+		settled, err := h.readModel.GetSettledResourceVersion(ctx, resourceVersion, watchScanSize, time.Now().Add(-watchSettleWindow))
+		if err != nil {
+			return err
+		}
+		events := make([]*WatchEvent, 0)
+		if settled > resourceVersion {
+			if events, err = h.readModel.ListEventsAfter(ctx, resourceVersion, settled, watchBatchSize, query.Filter); err != nil {
+				return err
+			}
+		}
+		if err = send(events); err != nil {
+			return err
+		}
+		// fetch the rest immediately if the batch is full
+		if len(events) == watchBatchSize {
+			resourceVersion = events[len(events)-1].ResourceVersion
+			continue
+		}
+		// scan the rest immediately if the scan may be full
+		previous := resourceVersion
+		resourceVersion = settled
+		if settled-previous >= watchScanSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func TestWatch(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := &WatchFilter{AccountID: "account-01"}
+	fakeReadModel := NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GetLatestResourceVersion(gomock.Any()).Return(int64(10), nil)
+	gomock.InOrder(
+		fakeReadModel.EXPECT().GetSettledResourceVersion(gomock.Any(), int64(10), watchScanSize, gomock.Any()).Return(int64(14), nil),
+		fakeReadModel.EXPECT().ListEventsAfter(gomock.Any(), int64(10), int64(14), watchBatchSize, filter).
+			Return([]*WatchEvent{
+				{ResourceVersion: 11, TaskID: "task-1111", TaskEvent: TaskEvent{State: consts.TaskRunning}},
+				{ResourceVersion: 13, TaskID: "task-2222", TaskEvent: TaskEvent{State: consts.TaskQueued}},
+			}, nil),
+		// events after 14 are not settled yet
+		fakeReadModel.EXPECT().GetSettledResourceVersion(gomock.Any(), int64(14), watchScanSize, gomock.Any()).Return(int64(14), nil),
+	)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	var received []int64
+	handler := NewWatchHandler(fakeReadModel)
+	err := handler.Handle(ctx, &WatchQuery{Filter: filter}, func(events []*WatchEvent) error {
+		if len(events) == 0 {
+			cancel()
+		}
+		for _, event := range events {
+			received = append(received, event.ResourceVersion)
+		}
+		return nil
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(received).To(gomega.Equal([]int64{11, 13}))
+}
+
+func TestWatchResume(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeReadModel := NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GetSettledResourceVersion(gomock.Any(), int64(5), watchScanSize, gomock.Any()).Return(int64(6), nil)
+	fakeReadModel.EXPECT().ListEventsAfter(gomock.Any(), int64(5), int64(6), watchBatchSize, nil).
+		Return([]*WatchEvent{{ResourceVersion: 6, TaskID: "task-1111"}}, nil)
+
+	handler := NewWatchHandler(fakeReadModel)
+	err := handler.Handle(context.TODO(), &WatchQuery{ResourceVersion: utils.Point[int64](5)}, func(_ []*WatchEvent) error {
+		return apperrors.NewInternalError(context.Canceled)
+	})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestWatchInvalid(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewWatchHandler(NewFakeReadModel(ctrl))
+	err := handler.Handle(context.TODO(), &WatchQuery{Filter: &WatchFilter{UserID: "user-01"}}, nil)
+	g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+}
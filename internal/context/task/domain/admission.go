@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+// Admitter checks whether a task can be admitted before it is created.
+// It may reject the task, or accept it but mark it as quota held.
+type Admitter interface {
+	Admit(ctx context.Context, task *Task) error
+	// AdmitBatch admits tasks in order, tasks admitted earlier are counted in usages of later ones,
+	// it returns errors of tasks by index
+	AdmitBatch(ctx context.Context, tasks []*Task) ([]error, error)
+	// Admissible reports whether the task fits quotas of its owner now,
+	// it is used to release quota held tasks
+	Admissible(ctx context.Context, task *Task) (bool, error)
+}
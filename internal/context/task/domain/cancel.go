@@ -0,0 +1,67 @@
+package domain
+
+import "github.com/GBA-BI/tes-api/pkg/consts"
+
+// CancelFilter selects tasks to be canceled in bulk, tags must all be matched,
+// empty tag value matches any value of the key
+type CancelFilter struct {
+	AccountID    string
+	UserID       string
+	SubmissionID string
+	RunID        string
+	Tags         map[string]string
+	State        []string
+}
+
+// CancelResult counts tasks selected by bulk canceling
+type CancelResult struct {
+	// Canceled is count of tasks which are CANCELING or CANCELED
+	Canceled int
+	// AlreadyFinished is count of tasks which are finished before canceling
+	AlreadyFinished int
+	// Failed is count of tasks which failed to be canceled
+	Failed    int
+	FailedIDs []string
+}
+
+// cancelableStates are states of tasks which can be canceled, including PREEMPTED
+var cancelableStates = []string{consts.TaskQueued, consts.TaskInitializing, consts.TaskRunning, consts.TaskCanceling,
+	consts.TaskPreempted}
+
+// uncancelableStates are states of finished tasks which can not be canceled
+var uncancelableStates = []string{consts.TaskComplete, consts.TaskExecutorError, consts.TaskSystemError,
+	consts.TaskCanceled}
+
+// splitStates splits the filter into the one of tasks to be canceled one by one, and the one of finished tasks
+// only to be counted. Either is nil if no state of it is selected.
+func (f *CancelFilter) splitStates() (cancelable, uncancelable *CancelFilter) {
+	cancelableStateSet := make(map[string]struct{}, len(cancelableStates))
+	for _, state := range cancelableStates {
+		cancelableStateSet[state] = struct{}{}
+	}
+	states := f.State
+	if len(states) == 0 {
+		states = append(append([]string{}, cancelableStates...), uncancelableStates...)
+	}
+	var cancelableSelected, uncancelableSelected []string
+	for _, state := range states {
+		if _, ok := cancelableStateSet[state]; ok {
+			cancelableSelected = append(cancelableSelected, state)
+		} else {
+			uncancelableSelected = append(uncancelableSelected, state)
+		}
+	}
+	if len(cancelableSelected) > 0 {
+		cancelable = f.withState(cancelableSelected)
+	}
+	if len(uncancelableSelected) > 0 {
+		uncancelable = f.withState(uncancelableSelected)
+	}
+	return cancelable, uncancelable
+}
+
+func (f *CancelFilter) withState(state []string) *CancelFilter {
+	res := *f
+	res.State = state
+	return &res
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "context"
+
+// ClusterGetter gets remaining capacity and per task limits of a cluster
+type ClusterGetter interface {
+	GetClusterResources(ctx context.Context, clusterID string) (*ClusterResources, error)
+}
+
+// ClusterResources contains remaining capacity and per task limits of a cluster
+type ClusterResources struct {
+	Remaining *ResourceAmount
+	Limits    *ResourceAmount
+}
+
+// ResourceAmount is an amount of resources, nil fields are unlimited
+type ResourceAmount struct {
+	Count    *int
+	CPUCores *int
+	RamGB    *float64 // nolint
+	DiskGB   *float64
+	// GPU types not in a non-nil GPU are unavailable
+	GPU map[string]float64
+}
+
+// Fit reports whether the resources of a task fit the limits and remaining capacity
+func (c *ClusterResources) Fit(resources *Resources) bool {
+	if resources == nil {
+		resources = &Resources{}
+	}
+	return c.Limits.cover(resources, 0) && c.Remaining.cover(resources, 1)
+}
+
+// Occupy deducts the resources of a task from the remaining capacity
+func (c *ClusterResources) Occupy(resources *Resources) {
+	if c.Remaining == nil {
+		return
+	}
+	if resources == nil {
+		resources = &Resources{}
+	}
+	if c.Remaining.Count != nil {
+		*c.Remaining.Count--
+	}
+	if c.Remaining.CPUCores != nil {
+		*c.Remaining.CPUCores -= resources.CPUCores
+	}
+	if c.Remaining.RamGB != nil {
+		*c.Remaining.RamGB -= resources.RamGB
+	}
+	if c.Remaining.DiskGB != nil {
+		*c.Remaining.DiskGB -= resources.DiskGB
+	}
+	if c.Remaining.GPU != nil && resources.GPU != nil {
+		c.Remaining.GPU[resources.GPU.Type] -= resources.GPU.Count
+	}
+}
+
+func (a *ResourceAmount) cover(resources *Resources, count int) bool {
+	if a == nil {
+		return true
+	}
+	if a.Count != nil && *a.Count < count {
+		return false
+	}
+	if a.CPUCores != nil && *a.CPUCores < resources.CPUCores {
+		return false
+	}
+	if a.RamGB != nil && *a.RamGB < resources.RamGB {
+		return false
+	}
+	if a.DiskGB != nil && *a.DiskGB < resources.DiskGB {
+		return false
+	}
+	if a.GPU != nil && resources.GPU != nil && a.GPU[resources.GPU.Type] < resources.GPU.Count {
+		return false
+	}
+	return true
+}
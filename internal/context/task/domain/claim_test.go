@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func TestClusterResourcesFit(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name             string
+		clusterResources *ClusterResources
+		resources        *Resources
+		expFit           bool
+	}{
+		{
+			name:             "unlimited",
+			clusterResources: &ClusterResources{},
+			resources:        &Resources{CPUCores: 64, RamGB: 256, GPU: &GPUResource{Count: 8, Type: "gpu-01"}},
+			expFit:           true,
+		},
+		{
+			name: "fit",
+			clusterResources: &ClusterResources{
+				Remaining: &ResourceAmount{Count: utils.Point(1), CPUCores: utils.Point(8), GPU: map[string]float64{"gpu-01": 2}},
+				Limits:    &ResourceAmount{CPUCores: utils.Point(4), RamGB: utils.Point[float64](8)},
+			},
+			resources: &Resources{CPUCores: 4, RamGB: 8, GPU: &GPUResource{Count: 2, Type: "gpu-01"}},
+			expFit:    true,
+		},
+		{
+			name: "count exhausted",
+			clusterResources: &ClusterResources{
+				Remaining: &ResourceAmount{Count: utils.Point(0)},
+			},
+			resources: &Resources{CPUCores: 1},
+			expFit:    false,
+		},
+		{
+			name: "exceed limits",
+			clusterResources: &ClusterResources{
+				Limits: &ResourceAmount{RamGB: utils.Point[float64](8)},
+			},
+			resources: &Resources{CPUCores: 1, RamGB: 16},
+			expFit:    false,
+		},
+		{
+			name: "gpu type unavailable",
+			clusterResources: &ClusterResources{
+				Remaining: &ResourceAmount{GPU: map[string]float64{"gpu-01": 2}},
+			},
+			resources: &Resources{CPUCores: 1, GPU: &GPUResource{Count: 1, Type: "gpu-02"}},
+			expFit:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g.Expect(test.clusterResources.Fit(test.resources)).To(gomega.Equal(test.expFit))
+		})
+	}
+}
+
+func TestClusterResourcesOccupy(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	clusterResources := &ClusterResources{
+		Remaining: &ResourceAmount{Count: utils.Point(2), CPUCores: utils.Point(8), DiskGB: utils.Point[float64](100), GPU: map[string]float64{"gpu-01": 2}},
+	}
+	clusterResources.Occupy(&Resources{CPUCores: 4, RamGB: 8, DiskGB: 40, GPU: &GPUResource{Count: 2, Type: "gpu-01"}})
+	g.Expect(clusterResources.Remaining).To(gomega.Equal(&ResourceAmount{
+		Count: utils.Point(1), CPUCores: utils.Point(4), DiskGB: utils.Point[float64](60), GPU: map[string]float64{"gpu-01": 0},
+	}))
+}
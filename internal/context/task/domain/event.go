@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+)
+
+// TaskEvent is an accepted state transition of a task
+type TaskEvent struct {
+	Time          time.Time
+	PreviousState string
+	State         string
+	ClusterID     string
+	// RequestID is the request which triggers the transition, it is filled by repo if empty
+	RequestID string
+}
+
+// timeNow is replaceable in tests
+var timeNow = time.Now
+
+// recordEvent records the transition from previousState to current state
+func (t *TaskStatus) recordEvent(previousState, clusterID string) {
+	event := &TaskEvent{
+		Time:          timeNow().UTC().Truncate(time.Second),
+		PreviousState: previousState,
+		State:         t.State,
+		ClusterID:     clusterID,
+	}
+	t.Events = append(t.Events, event)
+	if event.Finished() {
+		t.FinishTime = &event.Time
+	}
+}
+
+// Finished returns whether the event transits the task to a finished state
+func (e *TaskEvent) Finished() bool {
+	_, ok := finishedStates[e.State]
+	return ok
+}
+
+// Reopened returns whether the event transits the task from a finished state back to executing
+func (e *TaskEvent) Reopened() bool {
+	_, ok := finishedStates[e.PreviousState]
+	return ok && !e.Finished()
+}
+
+// Notified returns whether the event is notified to callback URLs and webhooks,
+// PREEMPTED is not since the task may be requeued
+func (e *TaskEvent) Notified() bool {
+	return e.Finished() && e.State != consts.TaskPreempted
+}
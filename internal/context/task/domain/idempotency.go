@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// IdempotencyKey is given by the client to deduplicate repeated creations of a task,
+// it is scoped by the owner of the task and released after it expires
+type IdempotencyKey struct {
+	AccountID  string
+	UserID     string
+	Key        string
+	ExpireTime time.Time
+}
+
+func newIdempotencyKey(key string, bioosInfo *BioosInfo, expireTime time.Time) *IdempotencyKey {
+	res := &IdempotencyKey{Key: key, ExpireTime: expireTime}
+	if bioosInfo != nil {
+		res.AccountID = bioosInfo.AccountID
+		res.UserID = bioosInfo.UserID
+	}
+	return res
+}
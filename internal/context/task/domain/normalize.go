@@ -3,4 +3,5 @@ package domain
 // Normalizer ...
 type Normalizer interface {
 	Normalize(task *Task) error
+	NormalizeTaskLogs(logs []*TaskLog)
 }
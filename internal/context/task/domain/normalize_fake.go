@@ -46,3 +46,15 @@ func (mr *FakeNormalizerMockRecorder) Normalize(task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Normalize", reflect.TypeOf((*FakeNormalizer)(nil).Normalize), task)
 }
+
+// NormalizeTaskLogs mocks base method.
+func (m *FakeNormalizer) NormalizeTaskLogs(logs []*TaskLog) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NormalizeTaskLogs", logs)
+}
+
+// NormalizeTaskLogs indicates an expected call of NormalizeTaskLogs.
+func (mr *FakeNormalizerMockRecorder) NormalizeTaskLogs(logs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizeTaskLogs", reflect.TypeOf((*FakeNormalizer)(nil).NormalizeTaskLogs), logs)
+}
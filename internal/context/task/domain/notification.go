@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Notification is an outbox record of a task reaching a finished state,
+// it is saved together with the status update and removed after it is dispatched
+type Notification struct {
+	ID     int64
+	TaskID string
+	State  string
+	Time   time.Time
+	// Attempts is how many times the notification failed to be dispatched
+	Attempts int
+}
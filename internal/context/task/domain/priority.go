@@ -0,0 +1,27 @@
+package domain
+
+import "context"
+
+// ExtraPriorityGetter gets the sum of extra priorities applied to tasks
+// belonging to the bioosInfo
+type ExtraPriorityGetter interface {
+	GetExtraPriorityValue(ctx context.Context, bioosInfo *BioosInfo) (int, error)
+}
+
+// TaskPriority contains fields to calculate effective priority of the task
+type TaskPriority struct {
+	ID                string
+	BioosInfo         *BioosInfo
+	PriorityValue     int
+	EffectivePriority int
+}
+
+// PriorityFilter selects tasks whose effective priority may be affected by
+// the extra priority of account/user, submission or run
+type PriorityFilter struct {
+	AccountID    string
+	UserID       string
+	SubmissionID string
+	RunID        string
+	State        []string
+}
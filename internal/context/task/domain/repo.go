@@ -1,11 +1,46 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repo ...
 type Repo interface {
-	Create(ctx context.Context, task *Task) error
+	// Create saves the task and returns its id. The idempotency key is optional, keys expired
+	// at the creation time of the task are released, if the key is still held by another task,
+	// nothing is saved and the id of that task is returned
+	Create(ctx context.Context, task *Task, idempotencyKey *IdempotencyKey) (string, error)
+	// CreateBatch saves the tasks in one transaction
+	CreateBatch(ctx context.Context, tasks []*Task) error
+	// GetIdempotentTaskID returns the id of the task holding the idempotency key not expired at now, or empty if none
+	GetIdempotentTaskID(ctx context.Context, idempotencyKey *IdempotencyKey, now time.Time) (string, error)
+	Get(ctx context.Context, id string) (*Task, error)
 	GetStatus(ctx context.Context, id string) (*TaskStatus, error)
 	UpdateStatus(ctx context.Context, taskStatus *TaskStatus) (bool, error)
 	CheckIDExist(ctx context.Context, id string) (bool, error)
+	ListPriorities(ctx context.Context, filter *PriorityFilter) ([]*TaskPriority, error)
+	UpdateEffectivePriority(ctx context.Context, ids []string, effectivePriority int) error
+	// ListClaimCandidates lists QUEUED tasks without cluster after the task,
+	// ordered by effective priority descending, then by creation time
+	ListClaimCandidates(ctx context.Context, after *Task, limit int) ([]*Task, error)
+	ListStatusesByCluster(ctx context.Context, clusterID string, states []string) ([]*TaskStatus, error)
+	// ListStatuses lists up to limit task statuses matching the filter whose id is after afterID, ordered by id
+	ListStatuses(ctx context.Context, filter *CancelFilter, afterID string, limit int) ([]*TaskStatus, error)
+	// CountStatuses counts tasks matching the filter
+	CountStatuses(ctx context.Context, filter *CancelFilter) (int64, error)
+	// ListNotifications lists the oldest notifications in the outbox
+	ListNotifications(ctx context.Context, limit int) ([]*Notification, error)
+	DeleteNotifications(ctx context.Context, ids []int64) error
+	// IncreaseNotificationAttempts counts a failed attempt of dispatching the notifications
+	IncreaseNotificationAttempts(ctx context.Context, ids []int64) error
+	// ListFinishedIDs lists up to limit ids of finished tasks matching the filter
+	ListFinishedIDs(ctx context.Context, filter *RetentionFilter, limit int) ([]string, error)
+	// ArchiveTasks moves the tasks still finished before the time out of the task table into the archive,
+	// where they can still be got
+	ArchiveTasks(ctx context.Context, ids []string, finishedBefore time.Time) error
+	// PurgeTasks deletes the tasks still finished before the time together with their events and idempotency keys
+	PurgeTasks(ctx context.Context, ids []string, finishedBefore time.Time) error
+	// DeleteExpiredIdempotencyKeys deletes idempotency keys expired at now and returns how many are deleted
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
 }
@@ -7,6 +7,7 @@ package domain
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +35,20 @@ func (m *FakeRepo) EXPECT() *FakeRepoMockRecorder {
 	return m.recorder
 }
 
+// ArchiveTasks mocks base method.
+func (m *FakeRepo) ArchiveTasks(ctx context.Context, ids []string, finishedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveTasks", ctx, ids, finishedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveTasks indicates an expected call of ArchiveTasks.
+func (mr *FakeRepoMockRecorder) ArchiveTasks(ctx, ids, finishedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveTasks", reflect.TypeOf((*FakeRepo)(nil).ArchiveTasks), ctx, ids, finishedBefore)
+}
+
 // CheckIDExist mocks base method.
 func (m *FakeRepo) CheckIDExist(ctx context.Context, id string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -49,18 +64,107 @@ func (mr *FakeRepoMockRecorder) CheckIDExist(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIDExist", reflect.TypeOf((*FakeRepo)(nil).CheckIDExist), ctx, id)
 }
 
+// CountStatuses mocks base method.
+func (m *FakeRepo) CountStatuses(ctx context.Context, filter *CancelFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStatuses", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStatuses indicates an expected call of CountStatuses.
+func (mr *FakeRepoMockRecorder) CountStatuses(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStatuses", reflect.TypeOf((*FakeRepo)(nil).CountStatuses), ctx, filter)
+}
+
 // Create mocks base method.
-func (m *FakeRepo) Create(ctx context.Context, task *Task) error {
+func (m *FakeRepo) Create(ctx context.Context, task *Task, idempotencyKey *IdempotencyKey) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, task)
+	ret := m.ctrl.Call(m, "Create", ctx, task, idempotencyKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *FakeRepoMockRecorder) Create(ctx, task, idempotencyKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*FakeRepo)(nil).Create), ctx, task, idempotencyKey)
+}
+
+// CreateBatch mocks base method.
+func (m *FakeRepo) CreateBatch(ctx context.Context, tasks []*Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, tasks)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Create indicates an expected call of Create.
-func (mr *FakeRepoMockRecorder) Create(ctx, task interface{}) *gomock.Call {
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *FakeRepoMockRecorder) CreateBatch(ctx, tasks interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*FakeRepo)(nil).Create), ctx, task)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*FakeRepo)(nil).CreateBatch), ctx, tasks)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *FakeRepo) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *FakeRepoMockRecorder) DeleteExpiredIdempotencyKeys(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*FakeRepo)(nil).DeleteExpiredIdempotencyKeys), ctx, now)
+}
+
+// DeleteNotifications mocks base method.
+func (m *FakeRepo) DeleteNotifications(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotifications", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotifications indicates an expected call of DeleteNotifications.
+func (mr *FakeRepoMockRecorder) DeleteNotifications(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotifications", reflect.TypeOf((*FakeRepo)(nil).DeleteNotifications), ctx, ids)
+}
+
+// Get mocks base method.
+func (m *FakeRepo) Get(ctx context.Context, id string) (*Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *FakeRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*FakeRepo)(nil).Get), ctx, id)
+}
+
+// GetIdempotentTaskID mocks base method.
+func (m *FakeRepo) GetIdempotentTaskID(ctx context.Context, idempotencyKey *IdempotencyKey, now time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotentTaskID", ctx, idempotencyKey, now)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotentTaskID indicates an expected call of GetIdempotentTaskID.
+func (mr *FakeRepoMockRecorder) GetIdempotentTaskID(ctx, idempotencyKey, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotentTaskID", reflect.TypeOf((*FakeRepo)(nil).GetIdempotentTaskID), ctx, idempotencyKey, now)
 }
 
 // GetStatus mocks base method.
@@ -78,6 +182,138 @@ func (mr *FakeRepoMockRecorder) GetStatus(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*FakeRepo)(nil).GetStatus), ctx, id)
 }
 
+// IncreaseNotificationAttempts mocks base method.
+func (m *FakeRepo) IncreaseNotificationAttempts(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseNotificationAttempts", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncreaseNotificationAttempts indicates an expected call of IncreaseNotificationAttempts.
+func (mr *FakeRepoMockRecorder) IncreaseNotificationAttempts(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseNotificationAttempts", reflect.TypeOf((*FakeRepo)(nil).IncreaseNotificationAttempts), ctx, ids)
+}
+
+// ListClaimCandidates mocks base method.
+func (m *FakeRepo) ListClaimCandidates(ctx context.Context, after *Task, limit int) ([]*Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClaimCandidates", ctx, after, limit)
+	ret0, _ := ret[0].([]*Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClaimCandidates indicates an expected call of ListClaimCandidates.
+func (mr *FakeRepoMockRecorder) ListClaimCandidates(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClaimCandidates", reflect.TypeOf((*FakeRepo)(nil).ListClaimCandidates), ctx, after, limit)
+}
+
+// ListFinishedIDs mocks base method.
+func (m *FakeRepo) ListFinishedIDs(ctx context.Context, filter *RetentionFilter, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFinishedIDs", ctx, filter, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFinishedIDs indicates an expected call of ListFinishedIDs.
+func (mr *FakeRepoMockRecorder) ListFinishedIDs(ctx, filter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFinishedIDs", reflect.TypeOf((*FakeRepo)(nil).ListFinishedIDs), ctx, filter, limit)
+}
+
+// ListNotifications mocks base method.
+func (m *FakeRepo) ListNotifications(ctx context.Context, limit int) ([]*Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, limit)
+	ret0, _ := ret[0].([]*Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *FakeRepoMockRecorder) ListNotifications(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*FakeRepo)(nil).ListNotifications), ctx, limit)
+}
+
+// ListPriorities mocks base method.
+func (m *FakeRepo) ListPriorities(ctx context.Context, filter *PriorityFilter) ([]*TaskPriority, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriorities", ctx, filter)
+	ret0, _ := ret[0].([]*TaskPriority)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPriorities indicates an expected call of ListPriorities.
+func (mr *FakeRepoMockRecorder) ListPriorities(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriorities", reflect.TypeOf((*FakeRepo)(nil).ListPriorities), ctx, filter)
+}
+
+// ListStatuses mocks base method.
+func (m *FakeRepo) ListStatuses(ctx context.Context, filter *CancelFilter, afterID string, limit int) ([]*TaskStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatuses", ctx, filter, afterID, limit)
+	ret0, _ := ret[0].([]*TaskStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatuses indicates an expected call of ListStatuses.
+func (mr *FakeRepoMockRecorder) ListStatuses(ctx, filter, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatuses", reflect.TypeOf((*FakeRepo)(nil).ListStatuses), ctx, filter, afterID, limit)
+}
+
+// ListStatusesByCluster mocks base method.
+func (m *FakeRepo) ListStatusesByCluster(ctx context.Context, clusterID string, states []string) ([]*TaskStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatusesByCluster", ctx, clusterID, states)
+	ret0, _ := ret[0].([]*TaskStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatusesByCluster indicates an expected call of ListStatusesByCluster.
+func (mr *FakeRepoMockRecorder) ListStatusesByCluster(ctx, clusterID, states interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatusesByCluster", reflect.TypeOf((*FakeRepo)(nil).ListStatusesByCluster), ctx, clusterID, states)
+}
+
+// PurgeTasks mocks base method.
+func (m *FakeRepo) PurgeTasks(ctx context.Context, ids []string, finishedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTasks", ctx, ids, finishedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTasks indicates an expected call of PurgeTasks.
+func (mr *FakeRepoMockRecorder) PurgeTasks(ctx, ids, finishedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTasks", reflect.TypeOf((*FakeRepo)(nil).PurgeTasks), ctx, ids, finishedBefore)
+}
+
+// UpdateEffectivePriority mocks base method.
+func (m *FakeRepo) UpdateEffectivePriority(ctx context.Context, ids []string, effectivePriority int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEffectivePriority", ctx, ids, effectivePriority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEffectivePriority indicates an expected call of UpdateEffectivePriority.
+func (mr *FakeRepoMockRecorder) UpdateEffectivePriority(ctx, ids, effectivePriority interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEffectivePriority", reflect.TypeOf((*FakeRepo)(nil).UpdateEffectivePriority), ctx, ids, effectivePriority)
+}
+
 // UpdateStatus mocks base method.
 func (m *FakeRepo) UpdateStatus(ctx context.Context, taskStatus *TaskStatus) (bool, error) {
 	m.ctrl.T.Helper()
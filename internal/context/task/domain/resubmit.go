@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+// ResourcesOverride replaces resources of the resubmitted task if they are set
+type ResourcesOverride struct {
+	CPUCores    *int
+	RamGB       *float64 // nolint
+	DiskGB      *float64
+	BootDiskGB  *int
+	Preemptible *bool
+}
+
+// resubmit clones the finished task into a new QUEUED task which retries it,
+// the id of the new task is not generated yet
+func (t *Task) resubmit(override *ResourcesOverride, now time.Time) (*Task, error) {
+	if _, ok := finishedStates[t.State]; !ok {
+		return nil, apperrors.NewCannotExecError("only finished task can be resubmitted")
+	}
+	// retries of the new task start over with the same policy
+	maxRetries := t.MaxRetries
+	res := &Task{
+		TaskStatus: TaskStatus{
+			State:        consts.TaskQueued,
+			CreationTime: now,
+		},
+		Name:                t.Name,
+		Description:         t.Description,
+		Inputs:              t.Inputs,
+		Outputs:             t.Outputs,
+		Resources:           override.apply(t.Resources),
+		Executors:           t.Executors,
+		Volumes:             t.Volumes,
+		Tags:                t.Tags,
+		BioosInfo:           t.BioosInfo,
+		PriorityValue:       t.PriorityValue,
+		CallbackURL:         t.CallbackURL,
+		RetryOf:             t.ID,
+		Attempt:             t.Attempt + 1,
+		RequestedMaxRetries: &maxRetries,
+	}
+	return res, nil
+}
+
+// apply returns a copy of resources with the override applied
+func (o *ResourcesOverride) apply(resources *Resources) *Resources {
+	if o == nil {
+		return resources
+	}
+	res := &Resources{}
+	if resources != nil {
+		*res = *resources
+	}
+	if o.CPUCores != nil {
+		res.CPUCores = *o.CPUCores
+	}
+	if o.RamGB != nil {
+		res.RamGB = *o.RamGB
+	}
+	if o.DiskGB != nil {
+		res.DiskGB = *o.DiskGB
+	}
+	if o.BootDiskGB != nil {
+		res.BootDiskGB = o.BootDiskGB
+	}
+	if o.Preemptible != nil {
+		res.Preemptible = *o.Preemptible
+	}
+	return res
+}
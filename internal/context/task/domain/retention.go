@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// RetentionPolicy decides how long finished tasks are kept in the task table
+type RetentionPolicy struct {
+	// Age is how long tasks are kept after they are finished, zero keeps them forever
+	Age time.Duration
+	// AccountAges overrides Age for tasks of the accounts
+	AccountAges map[string]time.Duration
+	// Purge deletes the tasks instead of archiving them
+	Purge bool
+	// BatchSize is how many tasks are moved in one transaction
+	BatchSize int
+}
+
+// RetentionFilter selects tasks finished before FinishedBefore, AccountID is matched if it is not empty,
+// tasks of ExcludedAccountIDs are skipped
+type RetentionFilter struct {
+	AccountID          string
+	ExcludedAccountIDs []string
+	FinishedBefore     time.Time
+}
+
+// filters returns a filter for each account with its own age, and one for all the other accounts
+func (p *RetentionPolicy) filters(now time.Time) []*RetentionFilter {
+	accountIDs := make([]string, 0, len(p.AccountAges))
+	for accountID := range p.AccountAges {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	res := make([]*RetentionFilter, 0, len(accountIDs)+1)
+	for _, accountID := range accountIDs {
+		if age := p.AccountAges[accountID]; age > 0 {
+			res = append(res, &RetentionFilter{AccountID: accountID, FinishedBefore: now.Add(-age)})
+		}
+	}
+	if p.Age > 0 {
+		res = append(res, &RetentionFilter{ExcludedAccountIDs: accountIDs, FinishedBefore: now.Add(-p.Age)})
+	}
+	return res
+}
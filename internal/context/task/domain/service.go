@@ -2,50 +2,210 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 )
 
 // Service ...
 type Service interface {
 	Create(ctx context.Context, task *Task) (string, error)
+	// BatchCreate creates the tasks and returns results by index, if allOrNothing is set,
+	// none is created when any task fails
+	BatchCreate(ctx context.Context, tasks []*Task, allOrNothing bool) ([]*CreateResult, error)
 	Cancel(ctx context.Context, id string) error
-	Update(ctx context.Context, id string, state, clusterID *string, logs []*TaskLog) error
+	// Resubmit clones the finished task into a new QUEUED task, and returns id of the new task
+	Resubmit(ctx context.Context, id string, override *ResourcesOverride) (string, error)
+	// BulkCancel moves every matching non-finished task to CANCELING
+	BulkCancel(ctx context.Context, filter *CancelFilter) (*CancelResult, error)
+	// Update updates status of the task, if assignedClusterID is not empty, only the task assigned to
+	// the cluster can be updated, and it cannot be assigned to another cluster
+	Update(ctx context.Context, id, assignedClusterID string, state, clusterID *string, logs []*TaskLog) error
+	RefreshPriority(ctx context.Context, accountID, userID, submissionID, runID string) error
+	Claim(ctx context.Context, clusterID string, limit int) ([]string, error)
+	Reclaim(ctx context.Context, clusterID string, requeueRunning bool) error
+	// Retain archives or purges tasks finished longer than the retention policy allows and deletes expired
+	// idempotency keys, it returns how many tasks are moved out of the task table
+	Retain(ctx context.Context, policy *RetentionPolicy) (int, error)
 }
 
 type service struct {
-	repo       Repo
-	normalizer Normalizer
+	repo           Repo
+	normalizer     Normalizer
+	admitter       Admitter
+	priorityGetter ExtraPriorityGetter
+	clusterGetter  ClusterGetter
+	// idempotencyWindow is how long an idempotency key is kept after the task is created
+	idempotencyWindow time.Duration
 }
 
 var _ Service = (*service)(nil)
 
 // NewService ...
-func NewService(repo Repo, normalizer Normalizer) Service {
+func NewService(repo Repo, normalizer Normalizer, admitter Admitter, priorityGetter ExtraPriorityGetter, clusterGetter ClusterGetter, idempotencyWindow time.Duration) Service {
 	return &service{
-		repo:       repo,
-		normalizer: normalizer,
+		repo:           repo,
+		normalizer:     normalizer,
+		admitter:       admitter,
+		priorityGetter: priorityGetter,
+		clusterGetter:  clusterGetter,
+
+		idempotencyWindow: idempotencyWindow,
 	}
 }
 
 // Create ...
 func (s *service) Create(ctx context.Context, task *Task) (string, error) {
-	var id string
-	for {
-		id = GenTaskID()
-		exist, err := s.repo.CheckIDExist(ctx, id)
+	var idempotencyKey *IdempotencyKey
+	if task.IdempotencyKey != "" {
+		now := timeNow().UTC().Truncate(time.Second)
+		idempotencyKey = newIdempotencyKey(task.IdempotencyKey, task.BioosInfo, now.Add(s.idempotencyWindow))
+		// repeated creation returns before admission, so it is not rejected by quotas held by the first one
+		existID, err := s.repo.GetIdempotentTaskID(ctx, idempotencyKey, now)
 		if err != nil {
 			return "", err
 		}
-		if !exist {
-			break
+		if existID != "" {
+			return existID, nil
 		}
 	}
+
+	return s.create(ctx, task, idempotencyKey)
+}
+
+func (s *service) create(ctx context.Context, task *Task, idempotencyKey *IdempotencyKey) (string, error) {
+	id, err := s.genTaskID(ctx, nil)
+	if err != nil {
+		return "", err
+	}
 	task.ID = id
 
 	if err := s.normalizer.Normalize(task); err != nil {
 		return "", err
 	}
+	if err := s.admitter.Admit(ctx, task); err != nil {
+		return "", err
+	}
+	extraPriorityValue, err := s.priorityGetter.GetExtraPriorityValue(ctx, task.BioosInfo)
+	if err != nil {
+		return "", err
+	}
+	task.EffectivePriority = task.PriorityValue + extraPriorityValue
+	// creation is recorded as the first event, from empty state
+	task.recordEvent("", task.ClusterID)
+
+	return s.repo.Create(ctx, task, idempotencyKey)
+}
 
-	return id, s.repo.Create(ctx, task)
+// genTaskID generates an id which neither exists nor is one of used
+func (s *service) genTaskID(ctx context.Context, used map[string]struct{}) (string, error) {
+	for {
+		id := GenTaskID()
+		if _, ok := used[id]; ok {
+			continue
+		}
+		exist, err := s.repo.CheckIDExist(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if !exist {
+			return id, nil
+		}
+	}
+}
+
+// BatchCreate ...
+func (s *service) BatchCreate(ctx context.Context, tasks []*Task, allOrNothing bool) ([]*CreateResult, error) {
+	res := make([]*CreateResult, len(tasks))
+	used := make(map[string]struct{}, len(tasks))
+	for index, task := range tasks {
+		res[index] = &CreateResult{}
+		id, err := s.genTaskID(ctx, used)
+		if err != nil {
+			return nil, err
+		}
+		used[id] = struct{}{}
+		task.ID = id
+		res[index].Err = s.normalizer.Normalize(task)
+	}
+	if failed(res, allOrNothing) {
+		return res, nil
+	}
+
+	// tasks are admitted together, so they are counted in usages of each other
+	admitIndexes := make([]int, 0, len(tasks))
+	admitTasks := make([]*Task, 0, len(tasks))
+	for index, task := range tasks {
+		if res[index].Err == nil {
+			admitIndexes = append(admitIndexes, index)
+			admitTasks = append(admitTasks, task)
+		}
+	}
+	errs, err := s.admitter.AdmitBatch(ctx, admitTasks)
+	if err != nil {
+		return nil, err
+	}
+	for i, index := range admitIndexes {
+		res[index].Err = errs[i]
+	}
+	if failed(res, allOrNothing) {
+		return res, nil
+	}
+
+	createTasks := make([]*Task, 0, len(tasks))
+	for index, task := range tasks {
+		if res[index].Err != nil {
+			continue
+		}
+		extraPriorityValue, err := s.priorityGetter.GetExtraPriorityValue(ctx, task.BioosInfo)
+		if err != nil {
+			return nil, err
+		}
+		task.EffectivePriority = task.PriorityValue + extraPriorityValue
+		task.recordEvent("", task.ClusterID)
+		createTasks = append(createTasks, task)
+	}
+	if len(createTasks) > 0 {
+		if err = s.repo.CreateBatch(ctx, createTasks); err != nil {
+			return nil, err
+		}
+	}
+	for index, task := range tasks {
+		if res[index].Err == nil {
+			res[index].ID = task.ID
+		}
+	}
+	return res, nil
+}
+
+// failed reports whether the batch creation should stop,
+// it is true if allOrNothing is set and any task fails
+func failed(res []*CreateResult, allOrNothing bool) bool {
+	if !allOrNothing {
+		return false
+	}
+	for _, item := range res {
+		if item.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Resubmit ...
+func (s *service) Resubmit(ctx context.Context, id string, override *ResourcesOverride) (string, error) {
+	task, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	newTask, err := task.resubmit(override, timeNow().UTC().Truncate(time.Second))
+	if err != nil {
+		return "", err
+	}
+	return s.create(ctx, newTask, nil)
 }
 
 // Cancel ...
@@ -72,10 +232,77 @@ func (s *service) cancel(ctx context.Context, id string) (bool, error) {
 	return s.repo.UpdateStatus(ctx, taskStatus)
 }
 
+const bulkCancelBatchSize = 500
+
+// BulkCancel cancels the tasks matching the filter, finished tasks are only counted
+// instead of being listed one by one
+func (s *service) BulkCancel(ctx context.Context, filter *CancelFilter) (*CancelResult, error) {
+	res := &CancelResult{}
+	cancelable, uncancelable := filter.splitStates()
+	// finished tasks are counted before canceling, so that tasks canceled here are not counted again
+	if uncancelable != nil {
+		count, err := s.repo.CountStatuses(ctx, uncancelable)
+		if err != nil {
+			return nil, err
+		}
+		res.AlreadyFinished = int(count)
+	}
+	if cancelable == nil {
+		return res, nil
+	}
+	var afterID string
+	for {
+		taskStatuses, err := s.repo.ListStatuses(ctx, cancelable, afterID, bulkCancelBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, taskStatus := range taskStatuses {
+			switch canceled, err := s.cancelStatus(ctx, taskStatus); {
+			case err != nil:
+				res.Failed++
+				res.FailedIDs = append(res.FailedIDs, taskStatus.ID)
+			case canceled:
+				res.Canceled++
+			default:
+				res.AlreadyFinished++
+			}
+		}
+		if len(taskStatuses) < bulkCancelBatchSize {
+			return res, nil
+		}
+		afterID = taskStatuses[len(taskStatuses)-1].ID
+	}
+}
+
+// cancelStatus cancels the task with retry on conflict, it returns false if the task is already finished
+func (s *service) cancelStatus(ctx context.Context, taskStatus *TaskStatus) (bool, error) {
+	for {
+		if taskStatus.State == consts.TaskCanceling {
+			return true, nil
+		}
+		if err := taskStatus.Cancel(); err != nil {
+			if apperrors.IsCode(err, apperrors.CannotExecCode) {
+				return false, nil
+			}
+			return false, err
+		}
+		updated, err := s.repo.UpdateStatus(ctx, taskStatus)
+		if err != nil {
+			return false, err
+		}
+		if updated {
+			return true, nil
+		}
+		if taskStatus, err = s.repo.GetStatus(ctx, taskStatus.ID); err != nil {
+			return false, err
+		}
+	}
+}
+
 // Update ...
-func (s *service) Update(ctx context.Context, id string, state, clusterID *string, logs []*TaskLog) error {
+func (s *service) Update(ctx context.Context, id, assignedClusterID string, state, clusterID *string, logs []*TaskLog) error {
 	for {
-		updated, err := s.update(ctx, id, state, clusterID, logs)
+		updated, err := s.update(ctx, id, assignedClusterID, state, clusterID, logs)
 		if err != nil {
 			return err
 		}
@@ -85,25 +312,36 @@ func (s *service) Update(ctx context.Context, id string, state, clusterID *strin
 	}
 }
 
-func (s *service) update(ctx context.Context, id string, state, clusterID *string, logs []*TaskLog) (bool, error) {
+func (s *service) update(ctx context.Context, id, assignedClusterID string, state, clusterID *string, logs []*TaskLog) (bool, error) {
 	taskStatus, err := s.repo.GetStatus(ctx, id)
 	if err != nil {
 		return false, err
 	}
+	if assignedClusterID != "" {
+		if taskStatus.ClusterID != assignedClusterID {
+			return false, apperrors.NewPermissionDeniedError("task is not assigned to the cluster")
+		}
+		if clusterID != nil && *clusterID != assignedClusterID {
+			return false, apperrors.NewPermissionDeniedError("task cannot be assigned to another cluster")
+		}
+	}
 
+	retries := taskStatus.Retries
 	if state != nil {
 		if err = taskStatus.UpdateState(*state); err != nil {
 			return false, err
 		}
 	}
 
-	if clusterID != nil {
+	// a task retried by the state is not assigned back to the cluster
+	if clusterID != nil && taskStatus.Retries == retries {
 		if err = taskStatus.UpdateClusterID(*clusterID); err != nil {
 			return false, err
 		}
 	}
 
 	if len(logs) > 0 {
+		s.normalizer.NormalizeTaskLogs(logs)
 		if err = taskStatus.UpdateLogs(logs); err != nil {
 			return false, err
 		}
@@ -111,3 +349,206 @@ func (s *service) update(ctx context.Context, id string, state, clusterID *strin
 
 	return s.repo.UpdateStatus(ctx, taskStatus)
 }
+
+// RefreshPriority recalculates effective priorities of executing tasks
+// affected by the extra priority of account/user, submission or run
+func (s *service) RefreshPriority(ctx context.Context, accountID, userID, submissionID, runID string) error {
+	taskPriorities, err := s.repo.ListPriorities(ctx, &PriorityFilter{
+		AccountID:    accountID,
+		UserID:       userID,
+		SubmissionID: submissionID,
+		RunID:        runID,
+		State:        []string{consts.TaskQueued, consts.TaskInitializing, consts.TaskRunning},
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks of the same account/user, submission and run share extra priorities
+	extraPriorityValues := make(map[string]int)
+	idsByEffectivePriority := make(map[int][]string)
+	for _, taskPriority := range taskPriorities {
+		key := bioosInfoKey(taskPriority.BioosInfo)
+		extraPriorityValue, ok := extraPriorityValues[key]
+		if !ok {
+			if extraPriorityValue, err = s.priorityGetter.GetExtraPriorityValue(ctx, taskPriority.BioosInfo); err != nil {
+				return err
+			}
+			extraPriorityValues[key] = extraPriorityValue
+		}
+		effectivePriority := taskPriority.PriorityValue + extraPriorityValue
+		if effectivePriority != taskPriority.EffectivePriority {
+			idsByEffectivePriority[effectivePriority] = append(idsByEffectivePriority[effectivePriority], taskPriority.ID)
+		}
+	}
+
+	for effectivePriority, ids := range idsByEffectivePriority {
+		if err = s.repo.UpdateEffectivePriority(ctx, ids, effectivePriority); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimBatchSize is the number of candidates listed at once when claiming tasks
+const claimBatchSize = 64
+
+// Claim assigns up to limit QUEUED tasks without cluster to the cluster, in the order of
+// effective priority and creation time. Tasks which do not fit the cluster or quotas of
+// their owners are skipped.
+func (s *service) Claim(ctx context.Context, clusterID string, limit int) ([]string, error) {
+	clusterResources, err := s.clusterGetter.GetClusterResources(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, limit)
+	var after *Task
+	for len(res) < limit {
+		candidates, err := s.repo.ListClaimCandidates(ctx, after, claimBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if !clusterResources.Fit(candidate.Resources) {
+				continue
+			}
+			claimed, err := s.claim(ctx, candidate, clusterID)
+			if err != nil {
+				return nil, err
+			}
+			if !claimed {
+				continue
+			}
+			clusterResources.Occupy(candidate.Resources)
+			if res = append(res, candidate.ID); len(res) == limit {
+				break
+			}
+		}
+		if len(candidates) < claimBatchSize {
+			break
+		}
+		after = candidates[len(candidates)-1]
+	}
+	return res, nil
+}
+
+// claim assigns the task to the cluster, quota held task is released if it fits quotas now.
+// It returns false if the task is claimed by others or still exceeds quotas.
+func (s *service) claim(ctx context.Context, task *Task, clusterID string) (bool, error) {
+	for {
+		if task.State != consts.TaskQueued || task.ClusterID != "" {
+			return false, nil
+		}
+		if task.QuotaHeld {
+			admissible, err := s.admitter.Admissible(ctx, task)
+			if err != nil {
+				return false, err
+			}
+			if !admissible {
+				return false, nil
+			}
+			task.QuotaHeld = false
+		}
+		if err := task.UpdateClusterID(clusterID); err != nil {
+			return false, err
+		}
+
+		updated, err := s.repo.UpdateStatus(ctx, &task.TaskStatus)
+		if err != nil {
+			return false, err
+		}
+		if updated {
+			return true, nil
+		}
+		taskStatus, err := s.repo.GetStatus(ctx, task.ID)
+		if err != nil {
+			return false, err
+		}
+		task.TaskStatus = *taskStatus
+	}
+}
+
+// reclaimStates are states of tasks which may be held by a cluster
+var reclaimStates = []string{consts.TaskQueued, consts.TaskInitializing, consts.TaskRunning, consts.TaskCanceling}
+
+// Reclaim takes tasks back from the unhealthy cluster, a task failed to be reclaimed
+// does not stop the others, and errors of all failed tasks are returned together
+func (s *service) Reclaim(ctx context.Context, clusterID string, requeueRunning bool) error {
+	taskStatuses, err := s.repo.ListStatusesByCluster(ctx, clusterID, reclaimStates)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, taskStatus := range taskStatuses {
+		if err = s.reclaim(ctx, taskStatus, clusterID, requeueRunning); err != nil {
+			errs = append(errs, fmt.Errorf("reclaim task %s: %w", taskStatus.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *service) reclaim(ctx context.Context, taskStatus *TaskStatus, clusterID string, requeueRunning bool) error {
+	for {
+		if taskStatus.ClusterID != clusterID {
+			return nil
+		}
+		if err := taskStatus.Reclaim(requeueRunning); err != nil {
+			if apperrors.IsCode(err, apperrors.CannotExecCode) {
+				return nil
+			}
+			return err
+		}
+
+		updated, err := s.repo.UpdateStatus(ctx, taskStatus)
+		if err != nil {
+			return err
+		}
+		if updated {
+			return nil
+		}
+		if taskStatus, err = s.repo.GetStatus(ctx, taskStatus.ID); err != nil {
+			return err
+		}
+	}
+}
+
+// Retain ...
+func (s *service) Retain(ctx context.Context, policy *RetentionPolicy) (int, error) {
+	count := 0
+	now := timeNow().UTC().Truncate(time.Second)
+	for _, filter := range policy.filters(now) {
+		for {
+			ids, err := s.repo.ListFinishedIDs(ctx, filter, policy.BatchSize)
+			if err != nil {
+				return count, err
+			}
+			if len(ids) > 0 {
+				if policy.Purge {
+					err = s.repo.PurgeTasks(ctx, ids, filter.FinishedBefore)
+				} else {
+					err = s.repo.ArchiveTasks(ctx, ids, filter.FinishedBefore)
+				}
+				if err != nil {
+					return count, err
+				}
+				count += len(ids)
+			}
+			if len(ids) < policy.BatchSize {
+				break
+			}
+		}
+	}
+	// expired idempotency keys are useless whether their tasks are moved out or not
+	if _, err := s.repo.DeleteExpiredIdempotencyKeys(ctx, now); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+func bioosInfoKey(bioosInfo *BioosInfo) string {
+	if bioosInfo == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", bioosInfo.AccountID, bioosInfo.UserID, bioosInfo.SubmissionID, bioosInfo.RunID)
+}
@@ -34,6 +34,36 @@ func (m *FakeService) EXPECT() *FakeServiceMockRecorder {
 	return m.recorder
 }
 
+// BatchCreate mocks base method.
+func (m *FakeService) BatchCreate(ctx context.Context, tasks []*Task, allOrNothing bool) ([]*CreateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreate", ctx, tasks, allOrNothing)
+	ret0, _ := ret[0].([]*CreateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreate indicates an expected call of BatchCreate.
+func (mr *FakeServiceMockRecorder) BatchCreate(ctx, tasks, allOrNothing interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*FakeService)(nil).BatchCreate), ctx, tasks, allOrNothing)
+}
+
+// BulkCancel mocks base method.
+func (m *FakeService) BulkCancel(ctx context.Context, filter *CancelFilter) (*CancelResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkCancel", ctx, filter)
+	ret0, _ := ret[0].(*CancelResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkCancel indicates an expected call of BulkCancel.
+func (mr *FakeServiceMockRecorder) BulkCancel(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCancel", reflect.TypeOf((*FakeService)(nil).BulkCancel), ctx, filter)
+}
+
 // Cancel mocks base method.
 func (m *FakeService) Cancel(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -48,6 +78,21 @@ func (mr *FakeServiceMockRecorder) Cancel(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*FakeService)(nil).Cancel), ctx, id)
 }
 
+// Claim mocks base method.
+func (m *FakeService) Claim(ctx context.Context, clusterID string, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, clusterID, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *FakeServiceMockRecorder) Claim(ctx, clusterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*FakeService)(nil).Claim), ctx, clusterID, limit)
+}
+
 // Create mocks base method.
 func (m *FakeService) Create(ctx context.Context, task *Task) (string, error) {
 	m.ctrl.T.Helper()
@@ -63,16 +108,74 @@ func (mr *FakeServiceMockRecorder) Create(ctx, task interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*FakeService)(nil).Create), ctx, task)
 }
 
+// Reclaim mocks base method.
+func (m *FakeService) Reclaim(ctx context.Context, clusterID string, requeueRunning bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reclaim", ctx, clusterID, requeueRunning)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reclaim indicates an expected call of Reclaim.
+func (mr *FakeServiceMockRecorder) Reclaim(ctx, clusterID, requeueRunning interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reclaim", reflect.TypeOf((*FakeService)(nil).Reclaim), ctx, clusterID, requeueRunning)
+}
+
+// RefreshPriority mocks base method.
+func (m *FakeService) RefreshPriority(ctx context.Context, accountID, userID, submissionID, runID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshPriority", ctx, accountID, userID, submissionID, runID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshPriority indicates an expected call of RefreshPriority.
+func (mr *FakeServiceMockRecorder) RefreshPriority(ctx, accountID, userID, submissionID, runID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshPriority", reflect.TypeOf((*FakeService)(nil).RefreshPriority), ctx, accountID, userID, submissionID, runID)
+}
+
+// Resubmit mocks base method.
+func (m *FakeService) Resubmit(ctx context.Context, id string, override *ResourcesOverride) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resubmit", ctx, id, override)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resubmit indicates an expected call of Resubmit.
+func (mr *FakeServiceMockRecorder) Resubmit(ctx, id, override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resubmit", reflect.TypeOf((*FakeService)(nil).Resubmit), ctx, id, override)
+}
+
+// Retain mocks base method.
+func (m *FakeService) Retain(ctx context.Context, policy *RetentionPolicy) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retain", ctx, policy)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retain indicates an expected call of Retain.
+func (mr *FakeServiceMockRecorder) Retain(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retain", reflect.TypeOf((*FakeService)(nil).Retain), ctx, policy)
+}
+
 // Update mocks base method.
-func (m *FakeService) Update(ctx context.Context, id string, state, clusterID *string, logs []*TaskLog) error {
+func (m *FakeService) Update(ctx context.Context, id, assignedClusterID string, state, clusterID *string, logs []*TaskLog) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, state, clusterID, logs)
+	ret := m.ctrl.Call(m, "Update", ctx, id, assignedClusterID, state, clusterID, logs)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *FakeServiceMockRecorder) Update(ctx, id, state, clusterID, logs interface{}) *gomock.Call {
+func (mr *FakeServiceMockRecorder) Update(ctx, id, assignedClusterID, state, clusterID, logs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*FakeService)(nil).Update), ctx, id, state, clusterID, logs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*FakeService)(nil).Update), ctx, id, assignedClusterID, state, clusterID, logs)
 }
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -9,12 +10,17 @@ import (
 	"github.com/onsi/gomega"
 
 	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
 var id = "task-1111"
 var now = time.Now().UTC().Truncate(time.Second)
 
+func init() {
+	timeNow = func() time.Time { return now }
+}
+
 func TestCreate(t *testing.T) {
 	g := gomega.NewWithT(t)
 	ctrl := gomock.NewController(t)
@@ -26,12 +32,214 @@ func TestCreate(t *testing.T) {
 	fakeRepo := NewFakeRepo(ctrl)
 	fakeRepo.EXPECT().CheckIDExist(gomock.Any(), gomock.Any()).
 		Return(false, nil)
-	fakeRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+	fakeRepo.EXPECT().Create(gomock.Any(), gomock.Any(), nil).
+		Return(id, nil)
+	fakeAdmitter := NewFakeAdmitter(ctrl)
+	fakeAdmitter.EXPECT().Admit(gomock.Any(), gomock.Any()).
 		Return(nil)
+	fakePriorityGetter := NewFakeExtraPriorityGetter(ctrl)
+	fakePriorityGetter.EXPECT().GetExtraPriorityValue(gomock.Any(), gomock.Any()).
+		Return(20, nil)
+
+	svc := NewService(fakeRepo, fakeNormalizer, fakeAdmitter, fakePriorityGetter, nil, 0)
+	task := &Task{PriorityValue: 100}
+	_, err := svc.Create(context.TODO(), task)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(task.EffectivePriority).To(gomega.Equal(120))
+}
+
+func TestCreateNotAdmitted(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	svc := NewService(fakeRepo, fakeNormalizer)
+	fakeNormalizer := NewFakeNormalizer(ctrl)
+	fakeNormalizer.EXPECT().Normalize(gomock.Any()).
+		Return(nil)
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().CheckIDExist(gomock.Any(), gomock.Any()).
+		Return(false, nil)
+	fakeAdmitter := NewFakeAdmitter(ctrl)
+	fakeAdmitter.EXPECT().Admit(gomock.Any(), gomock.Any()).
+		Return(apperrors.NewQuotaExceededError("cpu_cores"))
+
+	svc := NewService(fakeRepo, fakeNormalizer, fakeAdmitter, nil, nil, 0)
 	_, err := svc.Create(context.TODO(), &Task{})
+	g.Expect(apperrors.IsCode(err, apperrors.QuotaExceededCode)).To(gomega.BeTrue())
+}
+
+func TestCreateIdempotent(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idempotencyKey := &IdempotencyKey{
+		AccountID:  "account-01",
+		UserID:     "user-01",
+		Key:        "key-01",
+		ExpireTime: now.Add(time.Hour),
+	}
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().GetIdempotentTaskID(gomock.Any(), idempotencyKey, now).
+		Return("", nil)
+	fakeRepo.EXPECT().CheckIDExist(gomock.Any(), gomock.Any()).
+		Return(false, nil)
+	fakeRepo.EXPECT().Create(gomock.Any(), gomock.Any(), idempotencyKey).
+		Return(id, nil)
+	fakeNormalizer := NewFakeNormalizer(ctrl)
+	fakeNormalizer.EXPECT().Normalize(gomock.Any()).
+		Return(nil)
+	fakeAdmitter := NewFakeAdmitter(ctrl)
+	fakeAdmitter.EXPECT().Admit(gomock.Any(), gomock.Any()).
+		Return(nil)
+	fakePriorityGetter := NewFakeExtraPriorityGetter(ctrl)
+	fakePriorityGetter.EXPECT().GetExtraPriorityValue(gomock.Any(), gomock.Any()).
+		Return(0, nil)
+
+	svc := NewService(fakeRepo, fakeNormalizer, fakeAdmitter, fakePriorityGetter, nil, time.Hour)
+	resp, err := svc.Create(context.TODO(), &Task{
+		BioosInfo:      &BioosInfo{AccountID: "account-01", UserID: "user-01"},
+		IdempotencyKey: "key-01",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(id))
+}
+
+func TestCreateIdempotentRepeated(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().GetIdempotentTaskID(gomock.Any(), &IdempotencyKey{
+		AccountID:  "account-01",
+		UserID:     "user-01",
+		Key:        "key-01",
+		ExpireTime: now.Add(time.Hour),
+	}, now).Return("task-2222", nil)
+
+	// neither admitted nor created again
+	svc := NewService(fakeRepo, nil, nil, nil, nil, time.Hour)
+	resp, err := svc.Create(context.TODO(), &Task{
+		BioosInfo:      &BioosInfo{AccountID: "account-01", UserID: "user-01"},
+		IdempotencyKey: "key-01",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal("task-2222"))
+}
+
+func TestBatchCreate(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tasks := []*Task{{Name: "invalid"}, {Name: "admitted"}, {Name: "rejected"}}
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().CheckIDExist(gomock.Any(), gomock.Any()).
+		Return(false, nil).Times(3)
+	fakeRepo.EXPECT().CreateBatch(gomock.Any(), []*Task{tasks[1]}).
+		Return(nil)
+	fakeNormalizer := NewFakeNormalizer(ctrl)
+	fakeNormalizer.EXPECT().Normalize(tasks[0]).
+		Return(apperrors.NewInvalidError("resources"))
+	fakeNormalizer.EXPECT().Normalize(gomock.Any()).
+		Return(nil).Times(2)
+	fakeAdmitter := NewFakeAdmitter(ctrl)
+	fakeAdmitter.EXPECT().AdmitBatch(gomock.Any(), []*Task{tasks[1], tasks[2]}).
+		Return([]error{nil, apperrors.NewQuotaExceededError("cpu_cores")}, nil)
+	fakePriorityGetter := NewFakeExtraPriorityGetter(ctrl)
+	fakePriorityGetter.EXPECT().GetExtraPriorityValue(gomock.Any(), gomock.Any()).
+		Return(0, nil)
+
+	svc := NewService(fakeRepo, fakeNormalizer, fakeAdmitter, fakePriorityGetter, nil, 0)
+	res, err := svc.BatchCreate(context.TODO(), tasks, false)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(apperrors.IsCode(res[0].Err, apperrors.InvalidCode)).To(gomega.BeTrue())
+	g.Expect(res[0].ID).To(gomega.BeEmpty())
+	g.Expect(res[1].Err).NotTo(gomega.HaveOccurred())
+	g.Expect(res[1].ID).To(gomega.Equal(tasks[1].ID))
+	g.Expect(apperrors.IsCode(res[2].Err, apperrors.QuotaExceededCode)).To(gomega.BeTrue())
+	g.Expect(res[2].ID).To(gomega.BeEmpty())
+}
+
+func TestBatchCreateAllOrNothing(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tasks := []*Task{{Name: "invalid"}, {Name: "valid"}}
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().CheckIDExist(gomock.Any(), gomock.Any()).
+		Return(false, nil).Times(2)
+	fakeNormalizer := NewFakeNormalizer(ctrl)
+	fakeNormalizer.EXPECT().Normalize(tasks[0]).
+		Return(apperrors.NewInvalidError("resources"))
+	fakeNormalizer.EXPECT().Normalize(tasks[1]).
+		Return(nil)
+
+	// neither admitted nor created
+	svc := NewService(fakeRepo, fakeNormalizer, nil, nil, nil, 0)
+	res, err := svc.BatchCreate(context.TODO(), tasks, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(apperrors.IsCode(res[0].Err, apperrors.InvalidCode)).To(gomega.BeTrue())
+	g.Expect(res[1]).To(gomega.Equal(&CreateResult{}))
+}
+
+func TestResubmit(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().Get(gomock.Any(), id).
+		Return(&Task{
+			TaskStatus: TaskStatus{ID: id, State: consts.TaskSystemError, ClusterID: "cluster-01"},
+			Name:       "name",
+			Resources:  &Resources{CPUCores: 1, RamGB: 4},
+			Executors:  []*Executor{{Image: "image:tag"}},
+			Attempt:    1,
+		}, nil)
+	fakeRepo.EXPECT().CheckIDExist(gomock.Any(), gomock.Any()).
+		Return(false, nil)
+	var created *Task
+	fakeRepo.EXPECT().Create(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, task *Task, _ *IdempotencyKey) (string, error) {
+			created = task
+			return task.ID, nil
+		})
+	fakeNormalizer := NewFakeNormalizer(ctrl)
+	fakeNormalizer.EXPECT().Normalize(gomock.Any()).
+		Return(nil)
+	fakeAdmitter := NewFakeAdmitter(ctrl)
+	fakeAdmitter.EXPECT().Admit(gomock.Any(), gomock.Any()).
+		Return(nil)
+	fakePriorityGetter := NewFakeExtraPriorityGetter(ctrl)
+	fakePriorityGetter.EXPECT().GetExtraPriorityValue(gomock.Any(), gomock.Any()).
+		Return(0, nil)
+
+	svc := NewService(fakeRepo, fakeNormalizer, fakeAdmitter, fakePriorityGetter, nil, 0)
+	newID, err := svc.Resubmit(context.TODO(), id, &ResourcesOverride{RamGB: utils.Point(16.0)})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(newID).To(gomega.Equal(created.ID))
+	g.Expect(created.State).To(gomega.Equal(consts.TaskQueued))
+	g.Expect(created.ClusterID).To(gomega.BeEmpty())
+	g.Expect(created.RetryOf).To(gomega.Equal(id))
+	g.Expect(created.Attempt).To(gomega.Equal(2))
+	g.Expect(created.Resources).To(gomega.Equal(&Resources{CPUCores: 1, RamGB: 16}))
+}
+
+func TestResubmitNotFinished(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().Get(gomock.Any(), id).
+		Return(&Task{TaskStatus: TaskStatus{ID: id, State: consts.TaskRunning}, Attempt: 1}, nil)
+
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+	_, err := svc.Resubmit(context.TODO(), id, nil)
+	g.Expect(apperrors.IsCode(err, apperrors.CannotExecCode)).To(gomega.BeTrue())
 }
 
 func TestCancel(t *testing.T) {
@@ -52,9 +260,15 @@ func TestCancel(t *testing.T) {
 		State:        consts.TaskCanceling,
 		ClusterID:    "cluster-01",
 		CreationTime: now,
+		Events: []*TaskEvent{{
+			Time:          now,
+			PreviousState: consts.TaskRunning,
+			State:         consts.TaskCanceling,
+			ClusterID:     "cluster-01",
+		}},
 	}).Return(true, nil)
 
-	svc := NewService(fakeRepo, nil)
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
 	err := svc.Cancel(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
@@ -81,8 +295,316 @@ func TestUpdate(t *testing.T) {
 			StartTime: &now,
 		}},
 	}).Return(true, nil)
+	fakeNormalizer := NewFakeNormalizer(ctrl)
+	fakeNormalizer.EXPECT().NormalizeTaskLogs(gomock.Any())
+
+	svc := NewService(fakeRepo, fakeNormalizer, nil, nil, nil, 0)
+	err := svc.Update(context.TODO(), id, "", utils.Point(consts.TaskQueued), utils.Point("cluster-01"), []*TaskLog{{StartTime: &now}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestUpdateAssignedCluster(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().GetStatus(gomock.Any(), id).
+		Return(&TaskStatus{
+			ID:           id,
+			State:        consts.TaskRunning,
+			ClusterID:    "cluster-01",
+			CreationTime: now,
+		}, nil).Times(3)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskStatus *TaskStatus) (bool, error) {
+			g.Expect(taskStatus.State).To(gomega.Equal(consts.TaskComplete))
+			g.Expect(taskStatus.ClusterID).To(gomega.Equal("cluster-01"))
+			return true, nil
+		})
+
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+	err := svc.Update(context.TODO(), id, "cluster-02", utils.Point(consts.TaskComplete), nil, nil)
+	g.Expect(apperrors.IsCode(err, apperrors.PermissionDeniedCode)).To(gomega.BeTrue())
+	err = svc.Update(context.TODO(), id, "cluster-01", nil, utils.Point("cluster-02"), nil)
+	g.Expect(apperrors.IsCode(err, apperrors.PermissionDeniedCode)).To(gomega.BeTrue())
+	err = svc.Update(context.TODO(), id, "cluster-01", utils.Point(consts.TaskComplete), nil, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestRefreshPriority(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bioosInfo := &BioosInfo{AccountID: "account-01", UserID: "user-01"}
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListPriorities(gomock.Any(), &PriorityFilter{
+		AccountID: "account-01",
+		State:     []string{consts.TaskQueued, consts.TaskInitializing, consts.TaskRunning},
+	}).Return([]*TaskPriority{
+		{ID: "task-1111", BioosInfo: bioosInfo, PriorityValue: 100, EffectivePriority: 100},
+		{ID: "task-2222", BioosInfo: bioosInfo, PriorityValue: 100, EffectivePriority: 100},
+		{ID: "task-3333", BioosInfo: bioosInfo, PriorityValue: 50, EffectivePriority: 60},
+	}, nil)
+	fakeRepo.EXPECT().UpdateEffectivePriority(gomock.Any(), []string{"task-1111", "task-2222"}, 110).
+		Return(nil)
+	fakePriorityGetter := NewFakeExtraPriorityGetter(ctrl)
+	fakePriorityGetter.EXPECT().GetExtraPriorityValue(gomock.Any(), bioosInfo).
+		Return(10, nil)
+
+	svc := NewService(fakeRepo, nil, nil, fakePriorityGetter, nil, 0)
+	err := svc.RefreshPriority(context.TODO(), "account-01", "", "", "")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestClaim(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeClusterGetter := NewFakeClusterGetter(ctrl)
+	fakeClusterGetter.EXPECT().GetClusterResources(gomock.Any(), "cluster-01").
+		Return(&ClusterResources{
+			Remaining: &ResourceAmount{CPUCores: utils.Point(8)},
+			Limits:    &ResourceAmount{RamGB: utils.Point[float64](16)},
+		}, nil)
+	candidates := []*Task{
+		// exceed limits
+		{TaskStatus: TaskStatus{ID: "task-1111", State: consts.TaskQueued}, Resources: &Resources{CPUCores: 1, RamGB: 32}},
+		// claimed by others
+		{TaskStatus: TaskStatus{ID: "task-2222", State: consts.TaskQueued}, Resources: &Resources{CPUCores: 1, RamGB: 2}},
+		// still exceed quotas
+		{TaskStatus: TaskStatus{ID: "task-3333", State: consts.TaskQueued, QuotaHeld: true}, Resources: &Resources{CPUCores: 1, RamGB: 2}},
+		// released from quota held
+		{TaskStatus: TaskStatus{ID: "task-4444", State: consts.TaskQueued, QuotaHeld: true}, Resources: &Resources{CPUCores: 4, RamGB: 8}},
+		// exceed remaining capacity
+		{TaskStatus: TaskStatus{ID: "task-5555", State: consts.TaskQueued}, Resources: &Resources{CPUCores: 8, RamGB: 8}},
+		{TaskStatus: TaskStatus{ID: "task-6666", State: consts.TaskQueued}, Resources: &Resources{CPUCores: 2, RamGB: 8}},
+		{TaskStatus: TaskStatus{ID: "task-7777", State: consts.TaskQueued}, Resources: &Resources{CPUCores: 1, RamGB: 2}},
+	}
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListClaimCandidates(gomock.Any(), nil, claimBatchSize).
+		Return(candidates, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-2222", State: consts.TaskQueued, ClusterID: "cluster-01"}).
+		Return(false, nil)
+	fakeRepo.EXPECT().GetStatus(gomock.Any(), "task-2222").
+		Return(&TaskStatus{ID: "task-2222", State: consts.TaskQueued, ClusterID: "cluster-02", StatusResourceVersion: 1}, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-4444", State: consts.TaskQueued, ClusterID: "cluster-01"}).
+		Return(true, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-6666", State: consts.TaskQueued, ClusterID: "cluster-01"}).
+		Return(true, nil)
+	fakeAdmitter := NewFakeAdmitter(ctrl)
+	fakeAdmitter.EXPECT().Admissible(gomock.Any(), candidates[2]).
+		Return(false, nil)
+	fakeAdmitter.EXPECT().Admissible(gomock.Any(), candidates[3]).
+		Return(true, nil)
+
+	svc := NewService(fakeRepo, nil, fakeAdmitter, nil, fakeClusterGetter, 0)
+	ids, err := svc.Claim(context.TODO(), "cluster-01", 2)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(ids).To(gomega.Equal([]string{"task-4444", "task-6666"}))
+}
+
+func TestBulkCancel(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := &CancelFilter{AccountID: "account-01", SubmissionID: "submission-01"}
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().CountStatuses(gomock.Any(), &CancelFilter{AccountID: "account-01", SubmissionID: "submission-01",
+		State: []string{consts.TaskComplete, consts.TaskExecutorError, consts.TaskSystemError, consts.TaskCanceled}}).
+		Return(int64(2), nil)
+	fakeRepo.EXPECT().ListStatuses(gomock.Any(), &CancelFilter{AccountID: "account-01", SubmissionID: "submission-01",
+		State: []string{consts.TaskQueued, consts.TaskInitializing, consts.TaskRunning, consts.TaskCanceling, consts.TaskPreempted}},
+		"", bulkCancelBatchSize).
+		Return([]*TaskStatus{
+			{ID: "task-1111", State: consts.TaskQueued},
+			// finished by others
+			{ID: "task-2222", State: consts.TaskRunning, ClusterID: "cluster-01"},
+			{ID: "task-4444", State: consts.TaskCanceling, ClusterID: "cluster-01"},
+			{ID: "task-5555", State: consts.TaskRunning, ClusterID: "cluster-01"},
+		}, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-1111", State: consts.TaskCanceling,
+		Events: []*TaskEvent{{Time: now, PreviousState: consts.TaskQueued, State: consts.TaskCanceling}},
+	}).Return(true, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-2222", State: consts.TaskCanceling, ClusterID: "cluster-01",
+		Events: []*TaskEvent{{Time: now, PreviousState: consts.TaskRunning, State: consts.TaskCanceling, ClusterID: "cluster-01"}},
+	}).Return(false, nil)
+	fakeRepo.EXPECT().GetStatus(gomock.Any(), "task-2222").
+		Return(&TaskStatus{ID: "task-2222", State: consts.TaskComplete, ClusterID: "cluster-01", StatusResourceVersion: 1}, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-5555", State: consts.TaskCanceling, ClusterID: "cluster-01",
+		Events: []*TaskEvent{{Time: now, PreviousState: consts.TaskRunning, State: consts.TaskCanceling, ClusterID: "cluster-01"}},
+	}).Return(false, apperrors.NewInternalError(fmt.Errorf("db error")))
+
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+	res, err := svc.BulkCancel(context.TODO(), filter)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(res).To(gomega.Equal(&CancelResult{
+		Canceled:        2,
+		AlreadyFinished: 3,
+		Failed:          1,
+		FailedIDs:       []string{"task-5555"},
+	}))
+}
+
+func TestBulkCancelStates(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name            string
+		state           []string
+		countStates     []string
+		listStates      []string
+		alreadyFinished int
+	}{
+		{
+			name:            "finished only",
+			state:           []string{consts.TaskComplete, consts.TaskCanceled},
+			countStates:     []string{consts.TaskComplete, consts.TaskCanceled},
+			alreadyFinished: 3,
+		},
+		{
+			name:       "cancelable only",
+			state:      []string{consts.TaskRunning, consts.TaskPreempted},
+			listStates: []string{consts.TaskRunning, consts.TaskPreempted},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeRepo := NewFakeRepo(ctrl)
+			if test.countStates != nil {
+				fakeRepo.EXPECT().CountStatuses(gomock.Any(), &CancelFilter{RunID: "run-01", State: test.countStates}).
+					Return(int64(test.alreadyFinished), nil)
+			}
+			if test.listStates != nil {
+				fakeRepo.EXPECT().ListStatuses(gomock.Any(), &CancelFilter{RunID: "run-01", State: test.listStates}, "", bulkCancelBatchSize).
+					Return([]*TaskStatus{}, nil)
+			}
+
+			svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+			res, err := svc.BulkCancel(context.TODO(), &CancelFilter{RunID: "run-01", State: test.state})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(res).To(gomega.Equal(&CancelResult{AlreadyFinished: test.alreadyFinished}))
+		})
+	}
+}
+
+func TestReclaim(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListStatusesByCluster(gomock.Any(), "cluster-01", reclaimStates).
+		Return([]*TaskStatus{
+			{ID: "task-1111", State: consts.TaskQueued, ClusterID: "cluster-01"},
+			// updated by others
+			{ID: "task-2222", State: consts.TaskRunning, ClusterID: "cluster-01"},
+			// finished by others
+			{ID: "task-3333", State: consts.TaskRunning, ClusterID: "cluster-01"},
+		}, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-1111", State: consts.TaskQueued,
+		Logs: []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"cluster cluster-01 is unhealthy, task is unassigned"}}}}).
+		Return(true, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-2222", State: consts.TaskSystemError, ClusterID: "cluster-01", FinishTime: &now,
+		Logs:   []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"cluster cluster-01 is unhealthy, task is marked SYSTEM_ERROR"}}},
+		Events: []*TaskEvent{{Time: now, PreviousState: consts.TaskRunning, State: consts.TaskSystemError, ClusterID: "cluster-01"}},
+	}).Return(false, nil)
+	fakeRepo.EXPECT().GetStatus(gomock.Any(), "task-2222").
+		Return(&TaskStatus{ID: "task-2222", State: consts.TaskRunning, ClusterID: "cluster-01", StatusResourceVersion: 1}, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-2222", State: consts.TaskSystemError, ClusterID: "cluster-01", FinishTime: &now, StatusResourceVersion: 1,
+		Logs:   []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"cluster cluster-01 is unhealthy, task is marked SYSTEM_ERROR"}}},
+		Events: []*TaskEvent{{Time: now, PreviousState: consts.TaskRunning, State: consts.TaskSystemError, ClusterID: "cluster-01"}},
+	}).Return(true, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), &TaskStatus{ID: "task-3333", State: consts.TaskSystemError, ClusterID: "cluster-01", FinishTime: &now,
+		Logs:   []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"cluster cluster-01 is unhealthy, task is marked SYSTEM_ERROR"}}},
+		Events: []*TaskEvent{{Time: now, PreviousState: consts.TaskRunning, State: consts.TaskSystemError, ClusterID: "cluster-01"}},
+	}).Return(false, nil)
+	fakeRepo.EXPECT().GetStatus(gomock.Any(), "task-3333").
+		Return(&TaskStatus{ID: "task-3333", State: consts.TaskComplete, ClusterID: "cluster-01", StatusResourceVersion: 1}, nil)
+
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+	err := svc.Reclaim(context.TODO(), "cluster-01", false)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestReclaimFailed(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListStatusesByCluster(gomock.Any(), "cluster-01", reclaimStates).
+		Return([]*TaskStatus{
+			{ID: "task-1111", State: consts.TaskQueued, ClusterID: "cluster-01"},
+			{ID: "task-2222", State: consts.TaskQueued, ClusterID: "cluster-01"},
+			{ID: "task-3333", State: consts.TaskQueued, ClusterID: "cluster-01"},
+		}, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).
+		Return(false, apperrors.NewInternalError(fmt.Errorf("db error")))
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Return(true, nil)
+	fakeRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).
+		Return(false, apperrors.NewInternalError(fmt.Errorf("db error")))
+
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+	err := svc.Reclaim(context.TODO(), "cluster-01", false)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("task-1111"))
+	g.Expect(err.Error()).NotTo(gomega.ContainSubstring("task-2222"))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("task-3333"))
+	g.Expect(apperrors.IsCode(err, apperrors.InternalCode)).To(gomega.BeTrue())
+}
+
+func TestRetain(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := 24 * time.Hour
+	fakeRepo := NewFakeRepo(ctrl)
+	gomock.InOrder(
+		fakeRepo.EXPECT().ListFinishedIDs(gomock.Any(), &RetentionFilter{AccountID: "account-01", FinishedBefore: now.Add(-day)}, 2).
+			Return([]string{"task-1111", "task-2222"}, nil),
+		fakeRepo.EXPECT().ArchiveTasks(gomock.Any(), []string{"task-1111", "task-2222"}, now.Add(-day)).Return(nil),
+		fakeRepo.EXPECT().ListFinishedIDs(gomock.Any(), &RetentionFilter{AccountID: "account-01", FinishedBefore: now.Add(-day)}, 2).
+			Return([]string{"task-3333"}, nil),
+		fakeRepo.EXPECT().ArchiveTasks(gomock.Any(), []string{"task-3333"}, now.Add(-day)).Return(nil),
+		fakeRepo.EXPECT().ListFinishedIDs(gomock.Any(), &RetentionFilter{
+			ExcludedAccountIDs: []string{"account-01", "account-02"}, FinishedBefore: now.Add(-30 * day)}, 2).
+			Return([]string{}, nil),
+		fakeRepo.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any(), now).Return(int64(1), nil),
+	)
+
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+	count, err := svc.Retain(context.TODO(), &RetentionPolicy{
+		Age: 30 * day,
+		// account-02 is kept forever
+		AccountAges: map[string]time.Duration{"account-01": day, "account-02": 0},
+		BatchSize:   2,
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(count).To(gomega.Equal(3))
+}
+
+func TestRetainPurge(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListFinishedIDs(gomock.Any(), &RetentionFilter{ExcludedAccountIDs: []string{}, FinishedBefore: now.Add(-time.Hour)}, 10).
+		Return([]string{"task-1111"}, nil)
+	fakeRepo.EXPECT().PurgeTasks(gomock.Any(), []string{"task-1111"}, now.Add(-time.Hour)).Return(nil)
+	fakeRepo.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any(), now).Return(int64(0), nil)
 
-	svc := NewService(fakeRepo, nil)
-	err := svc.Update(context.TODO(), id, utils.Point(consts.TaskQueued), utils.Point("cluster-01"), []*TaskLog{{StartTime: &now}})
+	svc := NewService(fakeRepo, nil, nil, nil, nil, 0)
+	count, err := svc.Retain(context.TODO(), &RetentionPolicy{Age: time.Hour, Purge: true, BatchSize: 10})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(count).To(gomega.Equal(1))
 }
@@ -29,6 +29,18 @@ type Task struct {
 	Tags          map[string]string
 	BioosInfo     *BioosInfo
 	PriorityValue int
+	// EffectivePriority is PriorityValue plus extra priorities applied to the task
+	EffectivePriority int
+	// CallbackURL is notified when the task is finished
+	CallbackURL string
+	// RetryOf is id of the task resubmitted as this one
+	RetryOf string
+	// Attempt is 1 for the first submission, and increased by each resubmission
+	Attempt int
+	// RequestedMaxRetries is max_retries specified at creation, it is normalized into MaxRetries
+	RequestedMaxRetries *int
+	// IdempotencyKey is optional, creations with the same key of the same owner return the same task
+	IdempotencyKey string
 }
 
 // TaskStatus contains fields not specified by CreateTask
@@ -38,8 +50,20 @@ type TaskStatus struct {
 	Logs         []*TaskLog
 	CreationTime time.Time
 	ClusterID    string
+	// QuotaHeld marks the task is accepted but exceeds quotas of its owner,
+	// it should not be scheduled until it is released
+	QuotaHeld bool
+	// MaxRetries is how many times SYSTEM_ERROR is retried by requeueing the task,
+	// it is decided at creation and kept with the status
+	MaxRetries int
+	// Retries is how many times the task is requeued after SYSTEM_ERROR
+	Retries int
+	// FinishTime is when the task reaches a finished state, finished tasks are moved out by retention after it
+	FinishTime *time.Time
 
 	StatusResourceVersion int
+	// Events are state transitions not persisted yet, they are saved with the status
+	Events []*TaskEvent
 }
 
 // Input ...
@@ -63,11 +87,15 @@ type Output struct {
 
 // Resources ...
 type Resources struct {
-	CPUCores   int
-	RamGB      float64 // nolint
-	DiskGB     float64
-	BootDiskGB *int
-	GPU        *GPUResource
+	CPUCores                int
+	RamGB                   float64 // nolint
+	DiskGB                  float64
+	BootDiskGB              *int
+	GPU                     *GPUResource
+	Preemptible             bool
+	Zones                   []string
+	BackendParameters       map[string]string
+	BackendParametersStrict bool
 }
 
 // GPUResource ...
@@ -78,13 +106,14 @@ type GPUResource struct {
 
 // Executor ...
 type Executor struct {
-	Image   string
-	Command []string
-	Workdir string
-	Stdin   string
-	Stdout  string
-	Stderr  string
-	Env     map[string]string
+	Image       string
+	Command     []string
+	Workdir     string
+	Stdin       string
+	Stdout      string
+	Stderr      string
+	Env         map[string]string
+	IgnoreError bool
 }
 
 // TaskLog ...
@@ -94,6 +123,14 @@ type TaskLog struct {
 	StartTime  *time.Time
 	EndTime    *time.Time
 	SystemLogs []string
+	Outputs    []*OutputFileLog
+}
+
+// OutputFileLog ...
+type OutputFileLog struct {
+	URL       string
+	Path      string
+	SizeBytes int64
 }
 
 // ExecutorLog ...
@@ -101,6 +138,9 @@ type ExecutorLog struct {
 	ExecutorID string
 	StartTime  *time.Time
 	EndTime    *time.Time
+	ExitCode   *int32
+	Stdout     string
+	Stderr     string
 }
 
 // BioosInfo ...
@@ -139,11 +179,13 @@ var executingStates = map[string]struct{}{
 	consts.TaskRunning:      {},
 }
 
+// finishedStates include PREEMPTED, which is finished unless the task is requeued
 var finishedStates = map[string]struct{}{
 	consts.TaskCanceled:      {},
 	consts.TaskComplete:      {},
 	consts.TaskExecutorError: {},
 	consts.TaskSystemError:   {},
+	consts.TaskPreempted:     {},
 }
 
 // UpdateState ...
@@ -152,10 +194,43 @@ func (t *TaskStatus) UpdateState(newState string) error {
 		return nil
 	}
 
+	// PREEMPTED job is finished unless it is requeued, which clears cluster_id to be scheduled again,
+	// and logs of the next attempt are kept in a new task log, like retry. It is canceled directly,
+	// since no cluster holds it.
+	if t.State == consts.TaskPreempted {
+		if newState == consts.TaskCanceled {
+			t.State = newState
+			t.recordEvent(consts.TaskPreempted, t.ClusterID)
+			return nil
+		}
+		if newState != consts.TaskQueued {
+			return apperrors.NewCannotExecError("PREEMPTED job state can only be updated back to QUEUED or to CANCELED")
+		}
+		t.State = newState
+		t.FinishTime = nil
+		t.recordEvent(consts.TaskPreempted, t.ClusterID)
+		t.ClusterID = ""
+		t.Logs = append(t.Logs, &TaskLog{})
+		return nil
+	}
+
 	if _, ok := finishedStates[t.State]; ok {
 		return apperrors.NewCannotExecError("finished job state cannot be updated")
 	}
 
+	if newState == consts.TaskSystemError && t.Retries < t.MaxRetries {
+		if _, ok := executingStates[t.State]; ok {
+			t.retry()
+			return nil
+		}
+	}
+
+	if newState == consts.TaskPreempted {
+		if _, ok := executingStates[t.State]; !ok {
+			return apperrors.NewCannotExecError("only executing job state can be changed to PREEMPTED")
+		}
+	}
+
 	if t.State == consts.TaskCanceling {
 		if _, ok := executingStates[newState]; ok {
 			return apperrors.NewCannotExecError("CANCELING job state cannot be updated back to executing")
@@ -166,12 +241,28 @@ func (t *TaskStatus) UpdateState(newState string) error {
 		return apperrors.NewCannotExecError("only job state is CANCELING, it can be changed to CANCELED")
 	}
 
+	previousState := t.State
 	t.State = newState
+	t.recordEvent(previousState, t.ClusterID)
 	return nil
 }
 
+// retry requeues the task instead of SYSTEM_ERROR, logs of the next attempt are kept in a new task log
+func (t *TaskStatus) retry() {
+	previousState, clusterID := t.State, t.ClusterID
+	t.State = consts.TaskQueued
+	t.Retries++
+	t.recordEvent(previousState, clusterID)
+	t.AppendSystemLog(clusterID, fmt.Sprintf("task is requeued after SYSTEM_ERROR, retry %d of %d", t.Retries, t.MaxRetries))
+	t.ClusterID = ""
+	t.Logs = append(t.Logs, &TaskLog{})
+}
+
 // Cancel ...
 func (t *TaskStatus) Cancel() error {
+	if t.State == consts.TaskPreempted {
+		return t.UpdateState(consts.TaskCanceled)
+	}
 	return t.UpdateState(consts.TaskCanceling)
 }
 
@@ -184,9 +275,74 @@ func (t *TaskStatus) UpdateClusterID(clusterID string) error {
 		return apperrors.NewCannotExecError("only QUEUED job cluster_id may be changed")
 	}
 	t.ClusterID = clusterID
+	// the task log of a new attempt belongs to the cluster it is assigned to
+	if clusterID != "" && len(t.Logs) > 0 {
+		if lastLog := t.Logs[len(t.Logs)-1]; lastLog.ClusterID == "" {
+			lastLog.ClusterID = clusterID
+		}
+	}
 	return nil
 }
 
+// Reclaim takes the task back from its unhealthy cluster. QUEUED tasks are
+// always unassigned, INITIALIZING and RUNNING tasks are requeued if
+// requeueRunning, otherwise marked SYSTEM_ERROR, CANCELING tasks are CANCELED.
+func (t *TaskStatus) Reclaim(requeueRunning bool) error {
+	clusterID := t.ClusterID
+	var systemLog string
+	var requeued bool
+	switch t.State {
+	case consts.TaskQueued:
+		systemLog = fmt.Sprintf("cluster %s is unhealthy, task is unassigned", clusterID)
+	case consts.TaskInitializing, consts.TaskRunning:
+		if !requeueRunning {
+			if err := t.UpdateState(consts.TaskSystemError); err != nil {
+				return err
+			}
+			// system log of retry is appended by UpdateState
+			if t.State == consts.TaskQueued {
+				return nil
+			}
+			systemLog = fmt.Sprintf("cluster %s is unhealthy, task is marked SYSTEM_ERROR", clusterID)
+			break
+		}
+		systemLog = fmt.Sprintf("cluster %s is unhealthy, task is requeued", clusterID)
+		if err := t.UpdateState(consts.TaskQueued); err != nil {
+			return err
+		}
+		requeued = true
+	case consts.TaskCanceling:
+		systemLog = fmt.Sprintf("cluster %s is unhealthy, task is canceled", clusterID)
+		if err := t.UpdateState(consts.TaskCanceled); err != nil {
+			return err
+		}
+	default:
+		return apperrors.NewCannotExecError(fmt.Sprintf("%s job cannot be reclaimed", t.State))
+	}
+	if t.State == consts.TaskQueued {
+		if err := t.UpdateClusterID(""); err != nil {
+			return err
+		}
+	}
+	t.AppendSystemLog(clusterID, systemLog)
+	// logs of the next attempt are kept in a new task log, like retry
+	if requeued {
+		t.Logs = append(t.Logs, &TaskLog{})
+	}
+	return nil
+}
+
+// AppendSystemLog appends a system log to the latest task log of the cluster
+func (t *TaskStatus) AppendSystemLog(clusterID, systemLog string) {
+	for index := len(t.Logs) - 1; index >= 0; index-- {
+		if taskLog := t.Logs[index]; taskLog.ClusterID == clusterID {
+			taskLog.SystemLogs = append(taskLog.SystemLogs, systemLog)
+			return
+		}
+	}
+	t.Logs = append(t.Logs, &TaskLog{ClusterID: clusterID, SystemLogs: []string{systemLog}})
+}
+
 // UpdateLogs ...
 func (t *TaskStatus) UpdateLogs(newLogs []*TaskLog) error {
 	t.Logs = mergeTaskLogs(t.Logs, newLogs)
@@ -199,14 +355,16 @@ func (t *TaskStatus) UpdateLogs(newLogs []*TaskLog) error {
 	return nil
 }
 
+// mergeTaskLogs merges new logs into the latest task logs of their clusters,
+// task logs of previous attempts are left as they are
 func mergeTaskLogs(old, new []*TaskLog) []*TaskLog {
 	for _, newLog := range new {
 		if newLog == nil {
 			continue
 		}
 		existMatch := false
-		for index, oldLog := range old {
-			if newLog.ClusterID == oldLog.ClusterID {
+		for index := len(old) - 1; index >= 0; index-- {
+			if oldLog := old[index]; newLog.ClusterID == oldLog.ClusterID {
 				old[index] = mergeTaskLog(old[index], newLog)
 				existMatch = true
 				break
@@ -231,6 +389,7 @@ func mergeTaskLog(old, new *TaskLog) *TaskLog {
 		old.EndTime = new.EndTime
 	}
 	old.SystemLogs = mergeSystemLogs(old.SystemLogs, new.SystemLogs)
+	old.Outputs = mergeOutputFileLogs(old.Outputs, new.Outputs)
 	return old
 }
 
@@ -275,6 +434,15 @@ func mergeExecutorLog(old, new *ExecutorLog) *ExecutorLog {
 	if new.EndTime != nil {
 		old.EndTime = new.EndTime
 	}
+	if new.ExitCode != nil {
+		old.ExitCode = new.ExitCode
+	}
+	if new.Stdout != "" {
+		old.Stdout = new.Stdout
+	}
+	if new.Stderr != "" {
+		old.Stderr = new.Stderr
+	}
 	return old
 }
 
@@ -291,6 +459,26 @@ func mergeSystemLogs(old, new []string) []string {
 	return old
 }
 
+func mergeOutputFileLogs(old, new []*OutputFileLog) []*OutputFileLog {
+	for _, newLog := range new {
+		if newLog == nil {
+			continue
+		}
+		existMatch := false
+		for index, oldLog := range old {
+			if oldLog != nil && newLog.Path == oldLog.Path {
+				old[index] = newLog
+				existMatch = true
+				break
+			}
+		}
+		if !existMatch {
+			old = append(old, newLog)
+		}
+	}
+	return old
+}
+
 func min(a, b int) int {
 	if a > b {
 		return b
@@ -347,3 +535,9 @@ func validateTime(startTime, endTime *time.Time) error {
 	}
 	return nil
 }
+
+// CreateResult is the result of a task in batch creation, ID is set if the task is created
+type CreateResult struct {
+	ID  string
+	Err error
+}
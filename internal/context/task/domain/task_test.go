@@ -7,22 +7,25 @@ import (
 	"github.com/onsi/gomega"
 
 	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
 func TestUpdateState(t *testing.T) {
 	g := gomega.NewWithT(t)
 
 	tests := []struct {
-		name     string
-		oldState string
-		newState string
-		expErr   bool
+		name         string
+		oldState     string
+		newState     string
+		expErr       bool
+		expClusterID string
 	}{
 		{
-			name:     "finished -> self",
-			oldState: consts.TaskSystemError,
-			newState: consts.TaskSystemError,
-			expErr:   false,
+			name:         "finished -> self",
+			oldState:     consts.TaskSystemError,
+			newState:     consts.TaskSystemError,
+			expErr:       false,
+			expClusterID: "cluster-01",
 		},
 		{
 			name:     "finished -> other: invalid",
@@ -31,10 +34,11 @@ func TestUpdateState(t *testing.T) {
 			expErr:   true,
 		},
 		{
-			name:     "canceling -> self",
-			oldState: consts.TaskCanceling,
-			newState: consts.TaskCanceling,
-			expErr:   false,
+			name:         "canceling -> self",
+			oldState:     consts.TaskCanceling,
+			newState:     consts.TaskCanceling,
+			expErr:       false,
+			expClusterID: "cluster-01",
 		},
 		{
 			name:     "canceling -> executing: invalid",
@@ -43,10 +47,11 @@ func TestUpdateState(t *testing.T) {
 			expErr:   true,
 		},
 		{
-			name:     "canceling -> canceled",
-			oldState: consts.TaskCanceling,
-			newState: consts.TaskCanceled,
-			expErr:   false,
+			name:         "canceling -> canceled",
+			oldState:     consts.TaskCanceling,
+			newState:     consts.TaskCanceled,
+			expErr:       false,
+			expClusterID: "cluster-01",
 		},
 		{
 			name:     "other -> canceled: invalid",
@@ -54,20 +59,174 @@ func TestUpdateState(t *testing.T) {
 			newState: consts.TaskCanceled,
 			expErr:   true,
 		},
+		{
+			name:         "executing -> preempted",
+			oldState:     consts.TaskRunning,
+			newState:     consts.TaskPreempted,
+			expErr:       false,
+			expClusterID: "cluster-01",
+		},
+		{
+			name:     "canceling -> preempted: invalid",
+			oldState: consts.TaskCanceling,
+			newState: consts.TaskPreempted,
+			expErr:   true,
+		},
+		{
+			name:         "preempted -> self",
+			oldState:     consts.TaskPreempted,
+			newState:     consts.TaskPreempted,
+			expErr:       false,
+			expClusterID: "cluster-01",
+		},
+		{
+			name:         "preempted -> queued",
+			oldState:     consts.TaskPreempted,
+			newState:     consts.TaskQueued,
+			expErr:       false,
+			expClusterID: "",
+		},
+		{
+			name:     "preempted -> other: invalid",
+			oldState: consts.TaskPreempted,
+			newState: consts.TaskSystemError,
+			expErr:   true,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			task := &TaskStatus{State: test.oldState}
+			task := &TaskStatus{State: test.oldState, ClusterID: "cluster-01"}
 			err := task.UpdateState(test.newState)
 			g.Expect(err != nil).To(gomega.Equal(test.expErr))
 			if err == nil {
 				g.Expect(task.State).To(gomega.Equal(test.newState))
+				g.Expect(task.ClusterID).To(gomega.Equal(test.expClusterID))
 			}
 		})
 	}
 }
 
+func TestUpdateStatePreempted(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	task := &TaskStatus{
+		State:     consts.TaskRunning,
+		ClusterID: "cluster-01",
+		Logs:      []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"started"}}},
+	}
+	g.Expect(task.UpdateState(consts.TaskPreempted)).To(gomega.Succeed())
+	g.Expect(task.FinishTime).NotTo(gomega.BeNil())
+	g.Expect(task.Events[len(task.Events)-1].Notified()).To(gomega.BeFalse())
+	g.Expect(task.UpdateState(consts.TaskRunning)).NotTo(gomega.Succeed())
+
+	// the requeued task is executing again, and the next attempt starts a new task log
+	g.Expect(task.UpdateState(consts.TaskQueued)).To(gomega.Succeed())
+	g.Expect(task.FinishTime).To(gomega.BeNil())
+	g.Expect(task.ClusterID).To(gomega.BeEmpty())
+	g.Expect(task.Events[len(task.Events)-1].Reopened()).To(gomega.BeTrue())
+	g.Expect(task.UpdateClusterID("cluster-01")).To(gomega.Succeed())
+	g.Expect(task.Logs).To(gomega.Equal([]*TaskLog{
+		{ClusterID: "cluster-01", SystemLogs: []string{"started"}},
+		{ClusterID: "cluster-01"},
+	}))
+}
+
+func TestCancelPreempted(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	task := &TaskStatus{State: consts.TaskRunning, ClusterID: "cluster-01"}
+	g.Expect(task.UpdateState(consts.TaskPreempted)).To(gomega.Succeed())
+	// no cluster holds the preempted task, so it is canceled directly
+	g.Expect(task.Cancel()).To(gomega.Succeed())
+	g.Expect(task.State).To(gomega.Equal(consts.TaskCanceled))
+	g.Expect(task.FinishTime).NotTo(gomega.BeNil())
+	event := task.Events[len(task.Events)-1]
+	g.Expect(event.PreviousState).To(gomega.Equal(consts.TaskPreempted))
+	g.Expect(event.Notified()).To(gomega.BeTrue())
+	g.Expect(task.UpdateState(consts.TaskQueued)).NotTo(gomega.Succeed())
+}
+
+func TestUpdateStateRetry(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name         string
+		oldState     string
+		retries      int
+		expState     string
+		expClusterID string
+		expRetries   int
+		expLogs      []*TaskLog
+	}{
+		{
+			name:         "running: retry",
+			oldState:     consts.TaskRunning,
+			retries:      0,
+			expState:     consts.TaskQueued,
+			expClusterID: "",
+			expRetries:   1,
+			expLogs: []*TaskLog{
+				{ClusterID: "cluster-01", SystemLogs: []string{"started", "task is requeued after SYSTEM_ERROR, retry 1 of 2"}},
+				{},
+			},
+		},
+		{
+			name:         "running: retries exhausted",
+			oldState:     consts.TaskRunning,
+			retries:      2,
+			expState:     consts.TaskSystemError,
+			expClusterID: "cluster-01",
+			expRetries:   2,
+			expLogs:      []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"started"}}},
+		},
+		{
+			name:         "initializing: retry",
+			oldState:     consts.TaskInitializing,
+			retries:      1,
+			expState:     consts.TaskQueued,
+			expClusterID: "",
+			expRetries:   2,
+			expLogs: []*TaskLog{
+				{ClusterID: "cluster-01", SystemLogs: []string{"started", "task is requeued after SYSTEM_ERROR, retry 2 of 2"}},
+				{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := &TaskStatus{
+				State:      test.oldState,
+				ClusterID:  "cluster-01",
+				Logs:       []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"started"}}},
+				MaxRetries: 2,
+				Retries:    test.retries,
+			}
+			err := task.UpdateState(consts.TaskSystemError)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(task.State).To(gomega.Equal(test.expState))
+			g.Expect(task.ClusterID).To(gomega.Equal(test.expClusterID))
+			g.Expect(task.Retries).To(gomega.Equal(test.expRetries))
+			g.Expect(task.Logs).To(gomega.Equal(test.expLogs))
+		})
+	}
+
+	// the task log of the next attempt belongs to the cluster assigned again
+	task := &TaskStatus{
+		State:      consts.TaskRunning,
+		ClusterID:  "cluster-01",
+		Logs:       []*TaskLog{{ClusterID: "cluster-01"}},
+		MaxRetries: 1,
+	}
+	g.Expect(task.UpdateState(consts.TaskSystemError)).To(gomega.Succeed())
+	g.Expect(task.UpdateClusterID("cluster-01")).To(gomega.Succeed())
+	g.Expect(task.Logs).To(gomega.HaveLen(2))
+	g.Expect(task.Logs[1].ClusterID).To(gomega.Equal("cluster-01"))
+	task.AppendSystemLog("cluster-01", "second attempt")
+	g.Expect(task.Logs[1].SystemLogs).To(gomega.Equal([]string{"second attempt"}))
+}
+
 func TestUpdateClusterID(t *testing.T) {
 	g := gomega.NewWithT(t)
 
@@ -120,6 +279,70 @@ func TestUpdateClusterID(t *testing.T) {
 	}
 }
 
+func TestTaskStatusReclaim(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name           string
+		oldState       string
+		oldLogs        []*TaskLog
+		requeueRunning bool
+		expState       string
+		expClusterID   string
+		expLogs        []*TaskLog
+		expErr         bool
+	}{
+		{
+			name:         "queued",
+			oldState:     consts.TaskQueued,
+			expState:     consts.TaskQueued,
+			expClusterID: "",
+			expLogs:      []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"cluster cluster-01 is unhealthy, task is unassigned"}}},
+		},
+		{
+			name:         "running: fail",
+			oldState:     consts.TaskRunning,
+			oldLogs:      []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"started"}}},
+			expState:     consts.TaskSystemError,
+			expClusterID: "cluster-01",
+			expLogs:      []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"started", "cluster cluster-01 is unhealthy, task is marked SYSTEM_ERROR"}}},
+		},
+		{
+			name:           "initializing: requeue",
+			oldState:       consts.TaskInitializing,
+			requeueRunning: true,
+			expState:       consts.TaskQueued,
+			expClusterID:   "",
+			expLogs:        []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"cluster cluster-01 is unhealthy, task is requeued"}}, {}},
+		},
+		{
+			name:         "canceling",
+			oldState:     consts.TaskCanceling,
+			expState:     consts.TaskCanceled,
+			expClusterID: "cluster-01",
+			expLogs:      []*TaskLog{{ClusterID: "cluster-01", SystemLogs: []string{"cluster cluster-01 is unhealthy, task is canceled"}}},
+		},
+		{
+			name:     "finished: invalid",
+			oldState: consts.TaskComplete,
+			expErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := &TaskStatus{State: test.oldState, ClusterID: "cluster-01", Logs: test.oldLogs}
+			err := task.Reclaim(test.requeueRunning)
+			g.Expect(err != nil).To(gomega.Equal(test.expErr))
+			if err == nil {
+				g.Expect(task.State).To(gomega.Equal(test.expState))
+				g.Expect(task.ClusterID).To(gomega.Equal(test.expClusterID))
+				g.Expect(task.Logs).To(gomega.Equal(test.expLogs))
+			}
+		})
+	}
+}
+
 func TestUpdateLogs(t *testing.T) {
 	g := gomega.NewWithT(t)
 
@@ -208,6 +431,79 @@ func TestUpdateLogs(t *testing.T) {
 				}}},
 			}},
 		},
+		{
+			name:         "normal3.1: end ExecutorLog with exit code and outputs",
+			creationTime: now,
+			oldLogs: []*TaskLog{{
+				ClusterID: "cluster-01",
+				StartTime: &now,
+				Logs: [][]*ExecutorLog{{{
+					ExecutorID: "ex-01-01",
+					StartTime:  &now,
+					Stdout:     "running",
+				}}},
+			}},
+			newLogs: []*TaskLog{{
+				ClusterID: "cluster-01",
+				Logs: [][]*ExecutorLog{{{
+					ExecutorID: "ex-01-01",
+					EndTime:    &now,
+					ExitCode:   utils.Point[int32](0),
+					Stdout:     "done",
+					Stderr:     "warning",
+				}}},
+			}},
+			expLogs: []*TaskLog{{
+				ClusterID: "cluster-01",
+				StartTime: &now,
+				Logs: [][]*ExecutorLog{{{
+					ExecutorID: "ex-01-01",
+					StartTime:  &now,
+					EndTime:    &now,
+					ExitCode:   utils.Point[int32](0),
+					Stdout:     "done",
+					Stderr:     "warning",
+				}}},
+			}},
+		},
+		{
+			name:         "normal3.2: merge outputs by path",
+			creationTime: now,
+			oldLogs: []*TaskLog{{
+				ClusterID: "cluster-01",
+				StartTime: &now,
+				Outputs: []*OutputFileLog{{
+					URL:       "s3://bucket/a.txt",
+					Path:      "/cromwell-executions/a.txt",
+					SizeBytes: 1,
+				}},
+			}},
+			newLogs: []*TaskLog{{
+				ClusterID: "cluster-01",
+				Outputs: []*OutputFileLog{{
+					URL:       "s3://bucket/a.txt",
+					Path:      "/cromwell-executions/a.txt",
+					SizeBytes: 10,
+				}, {
+					URL:       "s3://bucket/b.txt",
+					Path:      "/cromwell-executions/b.txt",
+					SizeBytes: 20,
+				}},
+			}},
+			expLogs: []*TaskLog{{
+				ClusterID: "cluster-01",
+				StartTime: &now,
+				Outputs: []*OutputFileLog{{
+					URL:       "s3://bucket/a.txt",
+					Path:      "/cromwell-executions/a.txt",
+					SizeBytes: 10,
+				}, {
+					URL:       "s3://bucket/b.txt",
+					Path:      "/cromwell-executions/b.txt",
+					SizeBytes: 20,
+				}},
+			}},
+		},
 		{
 			name:         "normal4: end ExecutorLogs, append another",
 			creationTime: now,
@@ -0,0 +1,198 @@
+package admission
+
+import (
+	"context"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+
+	quotadomain "github.com/GBA-BI/tes-api/internal/context/quota/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+// activeStates are the states whose tasks occupy quotas
+var activeStates = []string{consts.TaskQueued, consts.TaskInitializing, consts.TaskRunning}
+
+// admitter ...
+type admitter struct {
+	opts         *Options
+	quotaService quotadomain.Service
+	readModel    query.ReadModel
+}
+
+// NewAdmitter ...
+func NewAdmitter(opts *Options, quotaService quotadomain.Service, readModel query.ReadModel) (domain.Admitter, error) {
+	return &admitter{
+		opts:         opts,
+		quotaService: quotaService,
+		readModel:    readModel,
+	}, nil
+}
+
+var _ domain.Admitter = (*admitter)(nil)
+
+// Admit ...
+func (a *admitter) Admit(ctx context.Context, task *domain.Task) error {
+	if !a.opts.Enable {
+		return nil
+	}
+	exceeded, err := a.exceededQuotas(ctx, task, make(map[quotaScope]*query.TasksResources))
+	if err != nil {
+		return err
+	}
+	return a.overflow(task, exceeded)
+}
+
+// AdmitBatch ...
+func (a *admitter) AdmitBatch(ctx context.Context, tasks []*domain.Task) ([]error, error) {
+	res := make([]error, len(tasks))
+	if !a.opts.Enable {
+		return res, nil
+	}
+	// usages are gathered once for the batch, and accumulated with admitted tasks
+	usages := make(map[quotaScope]*query.TasksResources)
+	for index, task := range tasks {
+		exceeded, err := a.exceededQuotas(ctx, task, usages)
+		if err != nil {
+			return nil, err
+		}
+		if res[index] = a.overflow(task, exceeded); res[index] != nil || task.QuotaHeld {
+			continue
+		}
+		for scope, usage := range usages {
+			if scope.contains(task) {
+				addResources(usage, task.Resources)
+			}
+		}
+	}
+	return res, nil
+}
+
+// overflow applies the overflow policy if any quota is exceeded
+func (a *admitter) overflow(task *domain.Task, exceeded []string) error {
+	if len(exceeded) == 0 {
+		return nil
+	}
+	if a.opts.OverflowPolicy == OverflowPolicyHold {
+		applog.Warnw("task quota held", "task", task.ID, "exceeded", exceeded)
+		task.QuotaHeld = true
+		return nil
+	}
+	return apperrors.NewQuotaExceededError(exceeded...)
+}
+
+// Admissible ...
+func (a *admitter) Admissible(ctx context.Context, task *domain.Task) (bool, error) {
+	if !a.opts.Enable {
+		return true, nil
+	}
+	exceeded, err := a.exceededQuotas(ctx, task, make(map[quotaScope]*query.TasksResources))
+	if err != nil {
+		return false, err
+	}
+	return len(exceeded) == 0, nil
+}
+
+type quotaScope struct {
+	global    bool
+	accountID string
+	userID    string
+}
+
+// contains reports whether the task is owned by the scope
+func (s quotaScope) contains(task *domain.Task) bool {
+	if s.global {
+		return true
+	}
+	if task.BioosInfo == nil || task.BioosInfo.AccountID != s.accountID {
+		return false
+	}
+	return s.userID == "" || task.BioosInfo.UserID == s.userID
+}
+
+// exceededQuotas checks global, account and user quotas of the task owner,
+// and returns the exceeded items as {quotaID} {resource}, usages of scopes are cached in usages
+func (a *admitter) exceededQuotas(ctx context.Context, task *domain.Task, usages map[quotaScope]*query.TasksResources) ([]string, error) {
+	scopes := []quotaScope{{global: true}}
+	if task.BioosInfo != nil && task.BioosInfo.AccountID != "" {
+		scopes = append(scopes, quotaScope{accountID: task.BioosInfo.AccountID})
+		if task.BioosInfo.UserID != "" {
+			scopes = append(scopes, quotaScope{accountID: task.BioosInfo.AccountID, userID: task.BioosInfo.UserID})
+		}
+	}
+
+	var res []string
+	for _, scope := range scopes {
+		quota, err := a.quotaService.GetOrDefault(ctx, scope.global, scope.accountID, scope.userID)
+		if err != nil {
+			if apperrors.IsCode(err, apperrors.NotFoundCode) {
+				continue
+			}
+			return nil, err
+		}
+		if quota.ResourceQuota == nil {
+			continue
+		}
+		usage, ok := usages[scope]
+		if !ok {
+			if usage, err = a.readModel.GatherResources(ctx, &query.GatherFilter{
+				State:     activeStates,
+				AccountID: scope.accountID,
+				UserID:    scope.userID,
+				QuotaHeld: utils.Point(false),
+			}); err != nil {
+				return nil, err
+			}
+			usages[scope] = usage
+		}
+		for _, resource := range exceededResources(quota.ResourceQuota, usage, task.Resources) {
+			res = append(res, quota.ID+" "+resource)
+		}
+	}
+	return res, nil
+}
+
+func exceededResources(quota *quotadomain.ResourceQuota, usage *query.TasksResources, resources *domain.Resources) []string {
+	if resources == nil {
+		resources = &domain.Resources{}
+	}
+	var res []string
+	if quota.Count != nil && usage.Count+1 > *quota.Count {
+		res = append(res, "count")
+	}
+	if quota.CPUCores != nil && usage.CPUCores+resources.CPUCores > *quota.CPUCores {
+		res = append(res, "cpu_cores")
+	}
+	if quota.RamGB != nil && usage.RamGB+resources.RamGB > *quota.RamGB {
+		res = append(res, "ram_gb")
+	}
+	if quota.DiskGB != nil && usage.DiskGB+resources.DiskGB > *quota.DiskGB {
+		res = append(res, "disk_gb")
+	}
+	if quota.GPUQuota != nil && resources.GPU != nil {
+		// gpu types not in quota are not limited
+		if limit, ok := quota.GPUQuota.GPU[resources.GPU.Type]; ok && usage.GPU[resources.GPU.Type]+resources.GPU.Count > limit {
+			res = append(res, "gpu "+resources.GPU.Type)
+		}
+	}
+	return res
+}
+
+func addResources(usage *query.TasksResources, resources *domain.Resources) {
+	usage.Count++
+	if resources == nil {
+		return
+	}
+	usage.CPUCores += resources.CPUCores
+	usage.RamGB += resources.RamGB
+	usage.DiskGB += resources.DiskGB
+	if resources.GPU != nil {
+		if usage.GPU == nil {
+			usage.GPU = make(map[string]float64)
+		}
+		usage.GPU[resources.GPU.Type] += resources.GPU.Count
+	}
+}
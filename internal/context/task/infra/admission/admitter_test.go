@@ -0,0 +1,168 @@
+package admission
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	quotadomain "github.com/GBA-BI/tes-api/internal/context/quota/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func TestAdmit(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name         string
+		policy       string
+		resources    *domain.Resources
+		expErrCode   int
+		expQuotaHeld bool
+	}{
+		{
+			name:      "normal",
+			policy:    OverflowPolicyReject,
+			resources: &domain.Resources{CPUCores: 2, RamGB: 4, DiskGB: 20},
+		},
+		{
+			name:       "reject: account cpu exceeded",
+			policy:     OverflowPolicyReject,
+			resources:  &domain.Resources{CPUCores: 8, RamGB: 4, DiskGB: 20},
+			expErrCode: apperrors.QuotaExceededCode,
+		},
+		{
+			name:       "reject: user gpu exceeded",
+			policy:     OverflowPolicyReject,
+			resources:  &domain.Resources{CPUCores: 2, RamGB: 4, DiskGB: 20, GPU: &domain.GPUResource{Count: 2, Type: "gpu-01"}},
+			expErrCode: apperrors.QuotaExceededCode,
+		},
+		{
+			name:         "hold: account cpu exceeded",
+			policy:       OverflowPolicyHold,
+			resources:    &domain.Resources{CPUCores: 8, RamGB: 4, DiskGB: 20},
+			expQuotaHeld: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeQuotaService := quotadomain.NewFakeService(ctrl)
+			fakeQuotaService.EXPECT().GetOrDefault(gomock.Any(), true, "", "").
+				Return(nil, apperrors.NewNotFoundError("quota", consts.GlobalQuotaID))
+			fakeQuotaService.EXPECT().GetOrDefault(gomock.Any(), false, "account-01", "").
+				Return(&quotadomain.Quota{
+					ID:            consts.DefaultQuotaAccountID,
+					AccountID:     consts.DefaultQuotaAccountID,
+					ResourceQuota: &quotadomain.ResourceQuota{CPUCores: utils.Point(10)},
+				}, nil)
+			fakeQuotaService.EXPECT().GetOrDefault(gomock.Any(), false, "account-01", "user-01").
+				Return(&quotadomain.Quota{
+					ID:            "account-01/user-01",
+					AccountID:     "account-01",
+					UserID:        "user-01",
+					ResourceQuota: &quotadomain.ResourceQuota{GPUQuota: &quotadomain.GPUQuota{GPU: map[string]float64{"gpu-01": 2}}},
+				}, nil)
+			fakeReadModel := query.NewFakeReadModel(ctrl)
+			fakeReadModel.EXPECT().GatherResources(gomock.Any(), &query.GatherFilter{
+				State:     activeStates,
+				AccountID: "account-01",
+				QuotaHeld: utils.Point(false),
+			}).Return(&query.TasksResources{Count: 2, CPUCores: 4}, nil)
+			fakeReadModel.EXPECT().GatherResources(gomock.Any(), &query.GatherFilter{
+				State:     activeStates,
+				AccountID: "account-01",
+				UserID:    "user-01",
+				QuotaHeld: utils.Point(false),
+			}).Return(&query.TasksResources{Count: 1, CPUCores: 2, GPU: map[string]float64{"gpu-01": 1}}, nil)
+
+			a, err := NewAdmitter(&Options{Enable: true, OverflowPolicy: test.policy}, fakeQuotaService, fakeReadModel)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			task := &domain.Task{
+				Resources: test.resources,
+				BioosInfo: &domain.BioosInfo{AccountID: "account-01", UserID: "user-01"},
+			}
+			err = a.Admit(context.TODO(), task)
+			if test.expErrCode != 0 {
+				g.Expect(apperrors.IsCode(err, test.expErrCode)).To(gomega.BeTrue())
+			} else {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+			g.Expect(task.QuotaHeld).To(gomega.Equal(test.expQuotaHeld))
+		})
+	}
+}
+
+func TestAdmitDisabled(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	a, err := NewAdmitter(&Options{Enable: false}, nil, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(a.Admit(context.TODO(), &domain.Task{})).To(gomega.Succeed())
+}
+
+func TestAdmissible(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeQuotaService := quotadomain.NewFakeService(ctrl)
+	fakeQuotaService.EXPECT().GetOrDefault(gomock.Any(), true, "", "").
+		Return(&quotadomain.Quota{
+			ID:            consts.GlobalQuotaID,
+			ResourceQuota: &quotadomain.ResourceQuota{Count: utils.Point(10)},
+		}, nil)
+	fakeQuotaService.EXPECT().GetOrDefault(gomock.Any(), false, "account-01", "").
+		Return(nil, apperrors.NewNotFoundError("quota", "account-01"))
+	fakeReadModel := query.NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GatherResources(gomock.Any(), &query.GatherFilter{
+		State:     activeStates,
+		QuotaHeld: utils.Point(false),
+	}).Return(&query.TasksResources{Count: 9}, nil)
+
+	a, err := NewAdmitter(&Options{Enable: true, OverflowPolicy: OverflowPolicyHold}, fakeQuotaService, fakeReadModel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	admissible, err := a.Admissible(context.TODO(), &domain.Task{
+		TaskStatus: domain.TaskStatus{QuotaHeld: true},
+		Resources:  &domain.Resources{CPUCores: 1},
+		BioosInfo:  &domain.BioosInfo{AccountID: "account-01"},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(admissible).To(gomega.BeTrue())
+}
+
+func TestAdmitBatch(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeQuotaService := quotadomain.NewFakeService(ctrl)
+	fakeQuotaService.EXPECT().GetOrDefault(gomock.Any(), true, "", "").
+		Return(&quotadomain.Quota{
+			ID:            consts.GlobalQuotaID,
+			ResourceQuota: &quotadomain.ResourceQuota{Count: utils.Point(10)},
+		}, nil).Times(3)
+	fakeReadModel := query.NewFakeReadModel(ctrl)
+	// usage is gathered only once
+	fakeReadModel.EXPECT().GatherResources(gomock.Any(), &query.GatherFilter{
+		State:     activeStates,
+		QuotaHeld: utils.Point(false),
+	}).Return(&query.TasksResources{Count: 8}, nil)
+
+	a, err := NewAdmitter(&Options{Enable: true, OverflowPolicy: OverflowPolicyReject}, fakeQuotaService, fakeReadModel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	errs, err := a.AdmitBatch(context.TODO(), []*domain.Task{{}, {}, {}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(errs[0]).NotTo(gomega.HaveOccurred())
+	g.Expect(errs[1]).NotTo(gomega.HaveOccurred())
+	g.Expect(apperrors.IsCode(errs[2], apperrors.QuotaExceededCode)).To(gomega.BeTrue())
+}
@@ -0,0 +1,42 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// OverflowPolicyReject rejects tasks exceeding quotas
+	OverflowPolicyReject = "reject"
+	// OverflowPolicyHold accepts tasks exceeding quotas but marks them as quota held
+	OverflowPolicyHold = "hold"
+)
+
+// Options ...
+type Options struct {
+	Enable         bool   `mapstructure:"enable"`
+	OverflowPolicy string `mapstructure:"overflowPolicy"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Enable:         false,
+		OverflowPolicy: OverflowPolicyReject,
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if o.OverflowPolicy != OverflowPolicyReject && o.OverflowPolicy != OverflowPolicyHold {
+		return fmt.Errorf("admission overflowPolicy should be %s or %s", OverflowPolicyReject, OverflowPolicyHold)
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enable, "admission-enable", o.Enable, "enable quota admission when creating tasks")
+	fs.StringVar(&o.OverflowPolicy, "admission-overflow-policy", o.OverflowPolicy, "action when quota exceeded, reject or hold")
+}
@@ -0,0 +1,92 @@
+package capacity
+
+import (
+	"context"
+
+	clusterdomain "github.com/GBA-BI/tes-api/internal/context/cluster/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+// occupyingStates are the states whose tasks occupy capacity of their clusters
+var occupyingStates = []string{consts.TaskQueued, consts.TaskInitializing, consts.TaskRunning, consts.TaskCanceling}
+
+// getter ...
+type getter struct {
+	clusterRepo clusterdomain.Repo
+	readModel   query.ReadModel
+}
+
+// NewClusterGetter ...
+func NewClusterGetter(clusterRepo clusterdomain.Repo, readModel query.ReadModel) domain.ClusterGetter {
+	return &getter{
+		clusterRepo: clusterRepo,
+		readModel:   readModel,
+	}
+}
+
+var _ domain.ClusterGetter = (*getter)(nil)
+
+// GetClusterResources returns capacity of the cluster minus resources of its tasks, and its limits
+func (g *getter) GetClusterResources(ctx context.Context, clusterID string) (*domain.ClusterResources, error) {
+	cluster, err := g.clusterRepo.Get(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	res := &domain.ClusterResources{Limits: limitsToAmount(cluster.Limits)}
+	if cluster.Capacity == nil {
+		return res, nil
+	}
+
+	used, err := g.readModel.GatherResources(ctx, &query.GatherFilter{
+		State:     occupyingStates,
+		ClusterID: clusterID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Remaining = remainingAmount(cluster.Capacity, used)
+	return res, nil
+}
+
+func remainingAmount(capacity *clusterdomain.Capacity, used *query.TasksResources) *domain.ResourceAmount {
+	res := &domain.ResourceAmount{}
+	if capacity.Count != nil {
+		res.Count = utils.Point(*capacity.Count - used.Count)
+	}
+	if capacity.CPUCores != nil {
+		res.CPUCores = utils.Point(*capacity.CPUCores - used.CPUCores)
+	}
+	if capacity.RamGB != nil {
+		res.RamGB = utils.Point(*capacity.RamGB - used.RamGB)
+	}
+	if capacity.DiskGB != nil {
+		res.DiskGB = utils.Point(*capacity.DiskGB - used.DiskGB)
+	}
+	if capacity.GPUCapacity != nil {
+		res.GPU = make(map[string]float64, len(capacity.GPUCapacity.GPU))
+		for gpuType, count := range capacity.GPUCapacity.GPU {
+			res.GPU[gpuType] = count - used.GPU[gpuType]
+		}
+	}
+	return res
+}
+
+func limitsToAmount(limits *clusterdomain.Limits) *domain.ResourceAmount {
+	if limits == nil {
+		return nil
+	}
+	res := &domain.ResourceAmount{
+		CPUCores: limits.CPUCores,
+		RamGB:    limits.RamGB,
+	}
+	if limits.GPULimit != nil {
+		res.GPU = make(map[string]float64, len(limits.GPULimit.GPU))
+		for gpuType, count := range limits.GPULimit.GPU {
+			res.GPU[gpuType] = count
+		}
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package capacity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	clusterdomain "github.com/GBA-BI/tes-api/internal/context/cluster/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func TestGetClusterResources(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeClusterRepo := clusterdomain.NewFakeRepo(ctrl)
+	fakeClusterRepo.EXPECT().Get(gomock.Any(), "cluster-01").
+		Return(&clusterdomain.Cluster{
+			ID: "cluster-01",
+			Capacity: &clusterdomain.Capacity{
+				Count:       utils.Point(10),
+				CPUCores:    utils.Point(64),
+				GPUCapacity: &clusterdomain.GPUCapacity{GPU: map[string]float64{"gpu-01": 8}},
+			},
+			Limits: &clusterdomain.Limits{
+				CPUCores: utils.Point(16),
+				RamGB:    utils.Point[float64](64),
+			},
+		}, nil)
+	fakeReadModel := query.NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GatherResources(gomock.Any(), &query.GatherFilter{
+		State:     occupyingStates,
+		ClusterID: "cluster-01",
+	}).Return(&query.TasksResources{Count: 4, CPUCores: 16, RamGB: 32, GPU: map[string]float64{"gpu-01": 2}}, nil)
+
+	getter := NewClusterGetter(fakeClusterRepo, fakeReadModel)
+	resp, err := getter.GetClusterResources(context.TODO(), "cluster-01")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(&domain.ClusterResources{
+		Remaining: &domain.ResourceAmount{
+			Count:    utils.Point(6),
+			CPUCores: utils.Point(48),
+			GPU:      map[string]float64{"gpu-01": 6},
+		},
+		Limits: &domain.ResourceAmount{
+			CPUCores: utils.Point(16),
+			RamGB:    utils.Point[float64](64),
+		},
+	}))
+}
+
+func TestGetClusterResourcesNotFound(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeClusterRepo := clusterdomain.NewFakeRepo(ctrl)
+	fakeClusterRepo.EXPECT().Get(gomock.Any(), "cluster-01").
+		Return(nil, apperrors.NewNotFoundError("cluster", "cluster-01"))
+
+	getter := NewClusterGetter(fakeClusterRepo, query.NewFakeReadModel(ctrl))
+	_, err := getter.GetClusterResources(context.TODO(), "cluster-01")
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
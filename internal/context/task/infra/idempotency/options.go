@@ -0,0 +1,34 @@
+package idempotency
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Options ...
+type Options struct {
+	// Window is how long an idempotency key is kept after the task is created
+	Window time.Duration `mapstructure:"window"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Window: 24 * time.Hour,
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if o.Window <= 0 {
+		return fmt.Errorf("idempotency window should be positive")
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.Window, "idempotency-window", o.Window, "how long an idempotency key of task creation is kept")
+}
@@ -3,6 +3,7 @@ package normalize
 import (
 	"math"
 	"strings"
+	"unicode/utf8"
 
 	applog "github.com/GBA-BI/tes-api/pkg/log"
 
@@ -31,13 +32,51 @@ func (n *normalizer) Normalize(task *domain.Task) error {
 
 	setDefaultResources(task)
 
+	if err := checkBackendParameters(task, n.opts.BackendParameters); err != nil {
+		return err
+	}
+
 	normalizeDiskGB(task, n.opts.DiskGB)
 	normalizeBootDiskGB(task, n.opts.BootDiskGB)
 	normalizeGPU(task, n.opts.GPU)
+	normalizeMaxRetries(task, n.opts.Retry)
 
 	return nil
 }
 
+// NormalizeTaskLogs ...
+func (n *normalizer) NormalizeTaskLogs(logs []*domain.TaskLog) {
+	if n.opts.ExecutorLog.OutputTailBytes <= 0 {
+		return
+	}
+	for _, taskLog := range logs {
+		if taskLog == nil {
+			continue
+		}
+		for _, executorLogs := range taskLog.Logs {
+			for _, executorLog := range executorLogs {
+				if executorLog == nil {
+					continue
+				}
+				executorLog.Stdout = tailString(executorLog.Stdout, n.opts.ExecutorLog.OutputTailBytes)
+				executorLog.Stderr = tailString(executorLog.Stderr, n.opts.ExecutorLog.OutputTailBytes)
+			}
+		}
+	}
+}
+
+// tailString keeps the last maxBytes bytes of s without splitting a utf8 rune
+func tailString(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	start := len(s) - maxBytes
+	for start < len(s) && !utf8.RuneStart(s[start]) {
+		start++
+	}
+	return s[start:]
+}
+
 func checkPath(task *domain.Task, executorBasePath string) error {
 	for _, input := range task.Inputs {
 		if input == nil {
@@ -58,6 +97,22 @@ func checkPath(task *domain.Task, executorBasePath string) error {
 	return nil
 }
 
+func checkBackendParameters(task *domain.Task, supportedKeys []string) error {
+	if !task.Resources.BackendParametersStrict {
+		return nil
+	}
+	supported := make(map[string]struct{}, len(supportedKeys))
+	for _, key := range supportedKeys {
+		supported[key] = struct{}{}
+	}
+	for key := range task.Resources.BackendParameters {
+		if _, ok := supported[key]; !ok {
+			return apperrors.NewInvalidError("resources.backend_parameters " + key + " is not supported")
+		}
+	}
+	return nil
+}
+
 func setDefaultResources(task *domain.Task) {
 	if task.Resources == nil {
 		task.Resources = &domain.Resources{}
@@ -136,3 +191,18 @@ func normalizeGPU(task *domain.Task, options GPUOptions) {
 	}
 	task.Resources.GPU.Count = newGPUCount
 }
+
+// normalizeMaxRetries applies max retries of the account or server if the task does not specify it
+func normalizeMaxRetries(task *domain.Task, options RetryOptions) {
+	if task.RequestedMaxRetries != nil {
+		task.MaxRetries = *task.RequestedMaxRetries
+		return
+	}
+	task.MaxRetries = options.MaxRetries
+	if task.BioosInfo == nil {
+		return
+	}
+	if maxRetries, ok := options.AccountMaxRetries[task.BioosInfo.AccountID]; ok {
+		task.MaxRetries = maxRetries
+	}
+}
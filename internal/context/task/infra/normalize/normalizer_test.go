@@ -6,6 +6,7 @@ import (
 	"github.com/onsi/gomega"
 
 	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
@@ -59,6 +60,56 @@ func TestNormalizeCheckPath(t *testing.T) {
 	}
 }
 
+func TestNormalizeBackendParameters(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name   string
+		task   *domain.Task
+		expErr bool
+	}{
+		{
+			name: "not strict",
+			task: &domain.Task{Resources: &domain.Resources{
+				BackendParameters: map[string]string{"unknown": "abc"},
+			}},
+			expErr: false,
+		},
+		{
+			name: "strict with supported keys",
+			task: &domain.Task{Resources: &domain.Resources{
+				BackendParameters:       map[string]string{"VmSize": "Standard_D64_v3"},
+				BackendParametersStrict: true,
+			}},
+			expErr: false,
+		},
+		{
+			name: "strict with unsupported keys",
+			task: &domain.Task{Resources: &domain.Resources{
+				BackendParameters:       map[string]string{"VmSize": "Standard_D64_v3", "unknown": "abc"},
+				BackendParametersStrict: true,
+			}},
+			expErr: true,
+		},
+	}
+
+	n, err := NewNormalizer(&Options{
+		ExecutorBasePath:  "/base/",
+		BackendParameters: []string{"VmSize"},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err = n.Normalize(test.task)
+			g.Expect(err != nil).To(gomega.Equal(test.expErr))
+			if test.expErr {
+				g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+			}
+		})
+	}
+}
+
 func TestNormalizeSetDefault(t *testing.T) {
 	g := gomega.NewWithT(t)
 
@@ -250,3 +301,85 @@ func TestNormalizeGPU(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeMaxRetries(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name          string
+		task          *domain.Task
+		maxRetriesExp int
+	}{
+		{
+			name:          "default",
+			task:          &domain.Task{Resources: &domain.Resources{CPUCores: 1, RamGB: 2, DiskGB: 30}},
+			maxRetriesExp: 1,
+		},
+		{
+			name:          "account",
+			task:          &domain.Task{Resources: &domain.Resources{CPUCores: 1, RamGB: 2, DiskGB: 30}, BioosInfo: &domain.BioosInfo{AccountID: "account-01"}},
+			maxRetriesExp: 3,
+		},
+		{
+			name:          "requested",
+			task:          &domain.Task{Resources: &domain.Resources{CPUCores: 1, RamGB: 2, DiskGB: 30}, BioosInfo: &domain.BioosInfo{AccountID: "account-01"}, RequestedMaxRetries: utils.Point(0)},
+			maxRetriesExp: 0,
+		},
+	}
+
+	n, err := NewNormalizer(&Options{
+		ExecutorBasePath: "/base/",
+		Retry: RetryOptions{
+			MaxRetries:        1,
+			AccountMaxRetries: map[string]int{"account-01": 3},
+		},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err = n.Normalize(test.task)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(test.task.MaxRetries).To(gomega.Equal(test.maxRetriesExp))
+		})
+	}
+}
+
+func TestNormalizeTaskLogs(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name    string
+		logs    []*domain.TaskLog
+		logsExp []*domain.TaskLog
+	}{
+		{
+			name:    "short outputs",
+			logs:    []*domain.TaskLog{{Logs: [][]*domain.ExecutorLog{{{ExecutorID: "ex-01", Stdout: "abc", Stderr: "def"}}}}},
+			logsExp: []*domain.TaskLog{{Logs: [][]*domain.ExecutorLog{{{ExecutorID: "ex-01", Stdout: "abc", Stderr: "def"}}}}},
+		},
+		{
+			name:    "keep tail",
+			logs:    []*domain.TaskLog{{Logs: [][]*domain.ExecutorLog{{{ExecutorID: "ex-01", Stdout: "abcdefgh", Stderr: "12345"}}}}},
+			logsExp: []*domain.TaskLog{{Logs: [][]*domain.ExecutorLog{{{ExecutorID: "ex-01", Stdout: "efgh", Stderr: "2345"}}}}},
+		},
+		{
+			name:    "not split rune",
+			logs:    []*domain.TaskLog{{Logs: [][]*domain.ExecutorLog{{{ExecutorID: "ex-01", Stdout: "a中文"}}}}},
+			logsExp: []*domain.TaskLog{{Logs: [][]*domain.ExecutorLog{{{ExecutorID: "ex-01", Stdout: "文"}}}}},
+		},
+	}
+
+	n, err := NewNormalizer(&Options{
+		ExecutorBasePath: "/base/",
+		ExecutorLog:      ExecutorLogOptions{OutputTailBytes: 4},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n.NormalizeTaskLogs(test.logs)
+			g.Expect(test.logs).To(gomega.BeEquivalentTo(test.logsExp))
+		})
+	}
+}
@@ -10,10 +10,14 @@ import (
 
 // Options ...
 type Options struct {
-	ExecutorBasePath string            `mapstructure:"executorBasePath"`
-	DiskGB           DiskGBOptions     `mapstructure:"diskGB"`
-	BootDiskGB       BootDiskGBOptions `mapstructure:"bootDiskGB"`
-	GPU              GPUOptions        `mapstructure:"gpu"`
+	ExecutorBasePath string             `mapstructure:"executorBasePath"`
+	DiskGB           DiskGBOptions      `mapstructure:"diskGB"`
+	BootDiskGB       BootDiskGBOptions  `mapstructure:"bootDiskGB"`
+	GPU              GPUOptions         `mapstructure:"gpu"`
+	ExecutorLog      ExecutorLogOptions `mapstructure:"executorLog"`
+	// BackendParameters are the keys of resources.backend_parameters supported by this server
+	BackendParameters []string     `mapstructure:"backendParameters"`
+	Retry             RetryOptions `mapstructure:"retry"`
 }
 
 // DiskGBOptions ...
@@ -37,6 +41,19 @@ type GPUOptions struct {
 	IsInteger bool `mapstrucutre:"isInteger"`
 }
 
+// ExecutorLogOptions ...
+type ExecutorLogOptions struct {
+	// OutputTailBytes is the max bytes of stdout/stderr kept in executor logs, 0 means unlimited
+	OutputTailBytes int `mapstructure:"outputTailBytes"`
+}
+
+// RetryOptions decides max retries of SYSTEM_ERROR tasks without max_retries
+type RetryOptions struct {
+	MaxRetries int `mapstructure:"maxRetries"`
+	// AccountMaxRetries overrides MaxRetries for tasks of the accounts
+	AccountMaxRetries map[string]int `mapstructure:"accountMaxRetries"`
+}
+
 // NewOptions ...
 func NewOptions() *Options {
 	return &Options{
@@ -56,6 +73,9 @@ func NewOptions() *Options {
 			Enable:    true,
 			IsInteger: true,
 		},
+		ExecutorLog: ExecutorLogOptions{
+			OutputTailBytes: 16 * 1024,
+		},
 	}
 }
 
@@ -89,6 +109,17 @@ func (o *Options) Validate() error {
 			return fmt.Errorf("normalize bootDiskGB max should be positive")
 		}
 	}
+	if o.ExecutorLog.OutputTailBytes < 0 {
+		return fmt.Errorf("normalize executorLog outputTailBytes should not be negative")
+	}
+	if o.Retry.MaxRetries < 0 {
+		return fmt.Errorf("normalize retry maxRetries should not be negative")
+	}
+	for accountID, maxRetries := range o.Retry.AccountMaxRetries {
+		if maxRetries < 0 {
+			return fmt.Errorf("normalize retry accountMaxRetries of %s should not be negative", accountID)
+		}
+	}
 	return nil
 }
 
@@ -104,4 +135,7 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&o.BootDiskGB.Max, "normalize-bootdiskgb-max", o.BootDiskGB.Max, "normalize bootDisk max in gb")
 	fs.BoolVar(&o.GPU.Enable, "normalize-gpu-enable", o.GPU.Enable, "enable normalize gpu")
 	fs.BoolVar(&o.GPU.IsInteger, "normalize-gpu-integer", o.GPU.IsInteger, "normalize gpu count as integer")
+	fs.StringSliceVar(&o.BackendParameters, "normalize-backend-parameters", o.BackendParameters, "supported keys of resources backend_parameters")
+	fs.IntVar(&o.Retry.MaxRetries, "normalize-retry-max-retries", o.Retry.MaxRetries, "max retries of SYSTEM_ERROR tasks without max_retries")
+	fs.IntVar(&o.ExecutorLog.OutputTailBytes, "normalize-executor-log-output-tail-bytes", o.ExecutorLog.OutputTailBytes, "max bytes of executor stdout/stderr kept, 0 means unlimited")
 }
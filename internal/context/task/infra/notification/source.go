@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	webhookdomain "github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+// source ...
+type source struct {
+	repo      domain.Repo
+	readModel query.ReadModel
+}
+
+// NewNotificationSource ...
+func NewNotificationSource(repo domain.Repo, readModel query.ReadModel) webhookdomain.NotificationSource {
+	return &source{repo: repo, readModel: readModel}
+}
+
+var _ webhookdomain.NotificationSource = (*source)(nil)
+
+// List lists notifications with the callback URL and bioos info of their tasks
+func (s *source) List(ctx context.Context, limit int) ([]*webhookdomain.Notification, error) {
+	notifications, err := s.repo.ListNotifications(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*webhookdomain.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		item := &webhookdomain.Notification{
+			ID:       notification.ID,
+			Attempts: notification.Attempts,
+			Event: &webhookdomain.Event{
+				TaskID: notification.TaskID,
+				State:  notification.State,
+				Time:   notification.Time,
+			},
+		}
+		task, err := s.readModel.GetBasic(ctx, notification.TaskID)
+		// the notification of a removed task is still returned to be acked,
+		// and the notification failed to be read is returned to fail alone
+		if err != nil && !apperrors.IsCode(err, apperrors.NotFoundCode) {
+			item.Err = err
+		}
+		if task != nil {
+			item.CallbackURL = task.CallbackURL
+			item.Event.Name = task.Name
+			if task.BioosInfo != nil {
+				item.Event.AccountID = task.BioosInfo.AccountID
+				item.Event.UserID = task.BioosInfo.UserID
+				item.Event.SubmissionID = task.BioosInfo.SubmissionID
+				item.Event.RunID = task.BioosInfo.RunID
+			}
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// Ack ...
+func (s *source) Ack(ctx context.Context, ids []int64) error {
+	return s.repo.DeleteNotifications(ctx, ids)
+}
+
+// Fail ...
+func (s *source) Fail(ctx context.Context, ids []int64) error {
+	return s.repo.IncreaseNotificationAttempts(ctx, ids)
+}
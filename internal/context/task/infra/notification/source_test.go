@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	webhookdomain "github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestList(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	fakeRepo := domain.NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListNotifications(gomock.Any(), 10).Return([]*domain.Notification{
+		{ID: 1, TaskID: "task-01", State: consts.TaskComplete, Time: now},
+		{ID: 2, TaskID: "task-02", State: consts.TaskCanceled, Time: now},
+		{ID: 3, TaskID: "task-03", State: consts.TaskComplete, Time: now, Attempts: 1},
+	}, nil)
+	fakeReadModel := query.NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().GetBasic(gomock.Any(), "task-01").Return(&query.TaskBasic{
+		TaskMinimal: query.TaskMinimal{ID: "task-01", State: consts.TaskComplete},
+		Name:        "task",
+		BioosInfo:   &query.BioosInfo{AccountID: "account-01", UserID: "user-01", RunID: "run-01"},
+		CallbackURL: "https://example.com/callback",
+	}, nil)
+	fakeReadModel.EXPECT().GetBasic(gomock.Any(), "task-02").Return(nil, apperrors.NewNotFoundError("task", "task-02"))
+	readErr := apperrors.NewInternalError(fmt.Errorf("db error"))
+	fakeReadModel.EXPECT().GetBasic(gomock.Any(), "task-03").Return(nil, readErr)
+
+	source := NewNotificationSource(fakeRepo, fakeReadModel)
+	resp, err := source.List(context.TODO(), 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal([]*webhookdomain.Notification{
+		{
+			ID: 1,
+			Event: &webhookdomain.Event{
+				TaskID:    "task-01",
+				State:     consts.TaskComplete,
+				Time:      now,
+				Name:      "task",
+				AccountID: "account-01",
+				UserID:    "user-01",
+				RunID:     "run-01",
+			},
+			CallbackURL: "https://example.com/callback",
+		},
+		{
+			ID:    2,
+			Event: &webhookdomain.Event{TaskID: "task-02", State: consts.TaskCanceled, Time: now},
+		},
+		// the notification failed to be read fails alone
+		{
+			ID:       3,
+			Event:    &webhookdomain.Event{TaskID: "task-03", State: consts.TaskComplete, Time: now},
+			Attempts: 1,
+			Err:      readErr,
+		},
+	}))
+}
+
+func TestFail(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := domain.NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().IncreaseNotificationAttempts(gomock.Any(), []int64{1, 2}).Return(nil)
+
+	source := NewNotificationSource(fakeRepo, nil)
+	g.Expect(source.Fail(context.TODO(), []int64{1, 2})).To(gomega.Succeed())
+}
@@ -0,0 +1,261 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/internal/migrations"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func newIntegrationDB(t *testing.T) (domain.Repo, query.ReadModel, *gorm.DB) {
+	g := gomega.NewWithT(t)
+	gormDB := testutil.NewSQLiteDB()
+	migrator, err := migrations.NewMigrator(gormDB, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(migrator.Up(context.TODO(), 0)).To(gomega.Succeed())
+	r, err := NewRepo(context.TODO(), gormDB, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	rm, err := NewReadModel(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	return r, rm, gormDB
+}
+
+// integrationTask is a copy of taskDO with its own id, state, name and tags
+func integrationTask(id, state, name string) *domain.Task {
+	task := *taskDO
+	task.ID = id
+	task.State = state
+	task.Name = name
+	task.Tags = map[string]string{"kkk": id}
+	return &task
+}
+
+func createIntegrationTasks(g *gomega.WithT, r domain.Repo, tasks ...*domain.Task) {
+	for _, task := range tasks {
+		_, err := r.Create(context.TODO(), task, nil)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+	}
+}
+
+func TestIntegrationGet(t *testing.T) {
+	g := gomega.NewWithT(t)
+	r, rm, _ := newIntegrationDB(t)
+	createIntegrationTasks(g, r, taskDO)
+
+	task, err := r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(task).To(gomega.BeEquivalentTo(taskDO))
+
+	full, err := rm.GetFull(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(full).To(gomega.BeEquivalentTo(taskDTO))
+
+	minimal, err := rm.GetMinimal(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(minimal).To(gomega.BeEquivalentTo(&taskDTO.TaskMinimal))
+
+	_, err = rm.GetBasic(context.TODO(), "task-0000")
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
+
+func TestIntegrationList(t *testing.T) {
+	g := gomega.NewWithT(t)
+	r, rm, _ := newIntegrationDB(t)
+	createIntegrationTasks(g, r,
+		integrationTask("task-1111", consts.TaskQueued, "task-1"),
+		integrationTask("task-2222", consts.TaskRunning, "task-2"),
+		integrationTask("task-3333", consts.TaskComplete, "task_3"),
+	)
+
+	tests := []struct {
+		name      string
+		pageSize  int
+		pageToken *utils.PageToken
+		filter    *query.ListFilter
+		expIDs    []string
+		expToken  *utils.PageToken
+	}{
+		{
+			name:     "first page",
+			pageSize: 2,
+			expIDs:   []string{"task-1111", "task-2222"},
+			expToken: &utils.PageToken{LastID: "task-2222"},
+		},
+		{
+			name:      "last page",
+			pageSize:  2,
+			pageToken: &utils.PageToken{LastID: "task-2222"},
+			expIDs:    []string{"task-3333"},
+		},
+		{
+			name:     "state",
+			pageSize: 10,
+			filter:   &query.ListFilter{State: []string{consts.TaskQueued, consts.TaskRunning}},
+			expIDs:   []string{"task-1111", "task-2222"},
+		},
+		{
+			name:     "escaped name prefix",
+			pageSize: 10,
+			filter:   &query.ListFilter{NamePrefix: "task_"},
+			expIDs:   []string{"task-3333"},
+		},
+		{
+			name:     "tags",
+			pageSize: 10,
+			filter:   &query.ListFilter{Tags: map[string]string{"kkk": "task-2222"}},
+			expIDs:   []string{"task-2222"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			resp, nextPageToken, err := rm.ListMinimal(context.TODO(), test.pageSize, test.pageToken, query.SortByID, test.filter)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			ids := make([]string, 0, len(resp))
+			for _, task := range resp {
+				ids = append(ids, task.ID)
+			}
+			g.Expect(ids).To(gomega.Equal(test.expIDs))
+			g.Expect(nextPageToken).To(gomega.Equal(test.expToken))
+		})
+	}
+}
+
+func TestIntegrationGatherResources(t *testing.T) {
+	g := gomega.NewWithT(t)
+	r, rm, _ := newIntegrationDB(t)
+	createIntegrationTasks(g, r,
+		integrationTask("task-1111", consts.TaskQueued, "task-1"),
+		integrationTask("task-2222", consts.TaskRunning, "task-2"),
+		integrationTask("task-3333", consts.TaskComplete, "task-3"),
+	)
+
+	resp, err := rm.GatherResources(context.TODO(), &query.GatherFilter{
+		State:     []string{consts.TaskQueued, consts.TaskRunning},
+		AccountID: "account-01",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(&query.TasksResources{
+		Count:    2,
+		CPUCores: 2,
+		RamGB:    4,
+		DiskGB:   20,
+		GPU:      map[string]float64{"gpu-01": 4},
+	}))
+
+	accounts, err := rm.ListAccounts(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(accounts).To(gomega.Equal([]*query.AccountInfo{{AccountID: "account-01", UserIDs: []string{"user-01"}}}))
+}
+
+func TestIntegrationEvents(t *testing.T) {
+	g := gomega.NewWithT(t)
+	r, rm, _ := newIntegrationDB(t)
+	createIntegrationTasks(g, r, integrationTask(id, consts.TaskRunning, "name"))
+
+	taskStatus, err := r.GetStatus(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	taskStatus.State = consts.TaskComplete
+	taskStatus.Events = []*domain.TaskEvent{{
+		Time:          now,
+		PreviousState: consts.TaskRunning,
+		State:         consts.TaskComplete,
+		ClusterID:     "cluster-01",
+	}}
+	updated, err := r.UpdateStatus(utils.WithRequestID(context.TODO(), "request-01"), taskStatus)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeTrue())
+
+	// the status resource version is outdated
+	taskStatus.StatusResourceVersion = 0
+	updated, err = r.UpdateStatus(context.TODO(), taskStatus)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeFalse())
+
+	event := query.TaskEvent{Time: now, PreviousState: consts.TaskRunning, State: consts.TaskComplete,
+		ClusterID: "cluster-01", RequestID: "request-01"}
+	events, err := rm.ListEvents(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(events).To(gomega.Equal([]*query.TaskEvent{&event}))
+
+	watchEvents, err := rm.ListEventsAfter(context.TODO(), 0, 1, 10, &query.WatchFilter{AccountID: "account-01"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(watchEvents).To(gomega.Equal([]*query.WatchEvent{{TaskEvent: event, ResourceVersion: 1, TaskID: id}}))
+
+	resourceVersion, err := rm.GetLatestResourceVersion(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resourceVersion).To(gomega.Equal(int64(1)))
+
+	notifications, err := r.ListNotifications(context.TODO(), 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(notifications).To(gomega.HaveLen(1))
+}
+
+// TestIntegrationEventsOutOfOrder inserts events out of order of their ids, like concurrent transactions
+// committed in a different order from their inserts
+func TestIntegrationEventsOutOfOrder(t *testing.T) {
+	g := gomega.NewWithT(t)
+	_, rm, gormDB := newIntegrationDB(t)
+	recent := time.Now().UTC().Truncate(time.Second)
+	settledBefore := recent.Add(-time.Minute)
+	insertEvent := func(id int64, eventTime time.Time) {
+		g.Expect(gormDB.Create(&TaskEvent{ID: id, TaskID: "task-1111", EventTime: eventTime, State: consts.TaskQueued}).Error).
+			To(gomega.Succeed())
+	}
+
+	insertEvent(3, recent)
+	resourceVersion, err := rm.GetSettledResourceVersion(context.TODO(), 0, 10, settledBefore)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resourceVersion).To(gomega.Equal(int64(0)))
+
+	insertEvent(2, recent)
+	insertEvent(1, recent)
+	resourceVersion, err = rm.GetSettledResourceVersion(context.TODO(), 0, 10, settledBefore)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resourceVersion).To(gomega.Equal(int64(3)))
+	watchEvents, err := rm.ListEventsAfter(context.TODO(), 0, resourceVersion, 10, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(watchEvents).To(gomega.HaveLen(3))
+	for index, watchEvent := range watchEvents {
+		g.Expect(watchEvent.ResourceVersion).To(gomega.Equal(int64(index + 1)))
+	}
+
+	// 4 is rolled back, and 5 is older than the settle window
+	insertEvent(5, settledBefore.Add(-time.Second))
+	insertEvent(6, recent)
+	resourceVersion, err = rm.GetSettledResourceVersion(context.TODO(), 3, 10, settledBefore)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resourceVersion).To(gomega.Equal(int64(6)))
+}
+
+func TestIntegrationArchive(t *testing.T) {
+	g := gomega.NewWithT(t)
+	r, rm, _ := newIntegrationDB(t)
+	createIntegrationTasks(g, r, taskDO)
+
+	g.Expect(r.ArchiveTasks(context.TODO(), []string{id}, now.Add(time.Second))).To(gomega.Succeed())
+
+	resp, _, err := rm.ListMinimal(context.TODO(), 10, nil, query.SortByID, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEmpty())
+
+	full, err := rm.GetFull(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(full).To(gomega.BeEquivalentTo(taskDTO))
+
+	exist, err := r.CheckIDExist(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(exist).To(gomega.BeTrue())
+}
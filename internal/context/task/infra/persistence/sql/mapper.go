@@ -1,6 +1,8 @@
 package sql
 
 import (
+	"sort"
+
 	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
 	"github.com/GBA-BI/tes-api/internal/context/task/domain"
 )
@@ -17,15 +19,21 @@ func (t *TaskBasic) toDTO() *query.TaskBasic {
 		return nil
 	}
 	res := &query.TaskBasic{
-		TaskMinimal:   *t.TaskState.toDTO(),
-		Name:          t.Name,
-		Description:   t.Description,
-		Resources:     t.Resources.toDTO(),
-		Volumes:       t.Volumes,
-		Tags:          t.Tags,
-		CreationTime:  t.CreationTime,
-		BioosInfo:     t.BioosInfo.toDTO(),
-		PriorityValue: t.PriorityValue,
+		TaskMinimal:       *t.TaskState.toDTO(),
+		Name:              t.Name,
+		Description:       t.Description,
+		Resources:         t.Resources.toDTO(),
+		Volumes:           t.Volumes,
+		Tags:              t.Tags,
+		CreationTime:      t.CreationTime,
+		BioosInfo:         t.BioosInfo.toDTO(),
+		PriorityValue:     t.PriorityValue,
+		EffectivePriority: t.EffectivePriority,
+		CallbackURL:       t.CallbackURL,
+		RetryOf:           t.RetryOf,
+		Attempt:           t.Attempt,
+		MaxRetries:        t.MaxRetries,
+		Retries:           t.Retries,
 	}
 	if len(t.Executors) > 0 {
 		res.Executors = make([]*query.Executor, len(t.Executors))
@@ -44,6 +52,9 @@ func (t *TaskBasic) toDTO() *query.TaskBasic {
 	} else {
 		res.ClusterID = *t.ClusterID
 	}
+	if t.QuotaHeld != nil {
+		res.QuotaHeld = *t.QuotaHeld
+	}
 	return res
 }
 
@@ -101,10 +112,14 @@ func (r *Resources) toDTO() *query.Resources {
 		return nil
 	}
 	res := &query.Resources{
-		CPUCores:   r.CPUCores,
-		RamGB:      r.RamGB,
-		DiskGB:     r.DiskGB,
-		BootDiskGB: r.BootDiskGB,
+		CPUCores:                r.CPUCores,
+		RamGB:                   r.RamGB,
+		DiskGB:                  r.DiskGB,
+		BootDiskGB:              r.BootDiskGB,
+		Preemptible:             r.Preemptible,
+		Zones:                   r.Zones,
+		BackendParameters:       r.BackendParameters,
+		BackendParametersStrict: r.BackendParametersStrict,
 	}
 	if r.GPUType != nil && r.GPUCount != nil {
 		res.GPU = &query.GPUResource{Count: *r.GPUCount, Type: *r.GPUType}
@@ -117,13 +132,14 @@ func (e *Executor) toDTO() *query.Executor {
 		return nil
 	}
 	return &query.Executor{
-		Image:   e.Image,
-		Command: e.Command,
-		Workdir: e.Workdir,
-		Stdin:   e.Stdin,
-		Stdout:  e.Stdout,
-		Stderr:  e.Stderr,
-		Env:     e.Env,
+		Image:       e.Image,
+		Command:     e.Command,
+		Workdir:     e.Workdir,
+		Stdin:       e.Stdin,
+		Stdout:      e.Stdout,
+		Stderr:      e.Stderr,
+		Env:         e.Env,
+		IgnoreError: e.IgnoreError,
 	}
 }
 
@@ -148,9 +164,26 @@ func (t *TaskLog) toDTO() *query.TaskLog {
 			}
 		}
 	}
+	if len(t.Outputs) > 0 {
+		res.Outputs = make([]*query.OutputFileLog, len(t.Outputs))
+		for index, output := range t.Outputs {
+			res.Outputs[index] = output.toDTO()
+		}
+	}
 	return res
 }
 
+func (o *OutputFileLog) toDTO() *query.OutputFileLog {
+	if o == nil {
+		return nil
+	}
+	return &query.OutputFileLog{
+		URL:       o.URL,
+		Path:      o.Path,
+		SizeBytes: o.SizeBytes,
+	}
+}
+
 func (e *ExecutorLog) toDTO() *query.ExecutorLog {
 	if e == nil {
 		return nil
@@ -159,6 +192,9 @@ func (e *ExecutorLog) toDTO() *query.ExecutorLog {
 		ExecutorID: e.ExecutorID,
 		StartTime:  e.StartTime,
 		EndTime:    e.EndTime,
+		ExitCode:   e.ExitCode,
+		Stdout:     e.Stdout,
+		Stderr:     e.Stderr,
 	}
 }
 
@@ -204,6 +240,53 @@ func (b *BucketsAuthInfo) toDTO() *query.BucketsAuthInfo {
 	return res
 }
 
+func (t *TaskBasic) toDO() *domain.Task {
+	if t == nil {
+		return nil
+	}
+	res := &domain.Task{
+		TaskStatus:        *t.TaskStatus.toDO(),
+		Name:              t.Name,
+		Description:       t.Description,
+		Resources:         t.Resources.toDO(),
+		Volumes:           t.Volumes,
+		Tags:              t.Tags,
+		BioosInfo:         t.BioosInfo.toDO(),
+		PriorityValue:     t.PriorityValue,
+		EffectivePriority: t.EffectivePriority,
+		CallbackURL:       t.CallbackURL,
+		RetryOf:           t.RetryOf,
+		Attempt:           t.Attempt,
+	}
+	if len(t.Executors) > 0 {
+		res.Executors = make([]*domain.Executor, len(t.Executors))
+		for index, executor := range t.Executors {
+			res.Executors[index] = executor.toDO()
+		}
+	}
+	return res
+}
+
+func (t *Task) toDO() *domain.Task {
+	if t == nil {
+		return nil
+	}
+	res := t.TaskBasic.toDO()
+	if len(t.Inputs) > 0 {
+		res.Inputs = make([]*domain.Input, len(t.Inputs))
+		for index, input := range t.Inputs {
+			res.Inputs[index] = input.toDO()
+		}
+	}
+	if len(t.Outputs) > 0 {
+		res.Outputs = make([]*domain.Output, len(t.Outputs))
+		for index, output := range t.Outputs {
+			res.Outputs[index] = output.toDO()
+		}
+	}
+	return res
+}
+
 func (t *TaskStatus) toDO() *domain.TaskStatus {
 	if t == nil {
 		return nil
@@ -212,6 +295,9 @@ func (t *TaskStatus) toDO() *domain.TaskStatus {
 		ID:           t.ID,
 		State:        t.State,
 		CreationTime: t.CreationTime,
+		MaxRetries:   t.MaxRetries,
+		Retries:      t.Retries,
+		FinishTime:   t.FinishTime,
 	}
 	if len(t.Logs) > 0 {
 		res.Logs = make([]*domain.TaskLog, len(t.Logs))
@@ -224,6 +310,9 @@ func (t *TaskStatus) toDO() *domain.TaskStatus {
 	} else {
 		res.ClusterID = *t.ClusterID
 	}
+	if t.QuotaHeld != nil {
+		res.QuotaHeld = *t.QuotaHeld
+	}
 	res.StatusResourceVersion = t.StatusResourceVersion
 	return res
 }
@@ -249,9 +338,26 @@ func (t *TaskLog) toDO() *domain.TaskLog {
 			}
 		}
 	}
+	if len(t.Outputs) > 0 {
+		res.Outputs = make([]*domain.OutputFileLog, len(t.Outputs))
+		for index, output := range t.Outputs {
+			res.Outputs[index] = output.toDO()
+		}
+	}
 	return res
 }
 
+func (o *OutputFileLog) toDO() *domain.OutputFileLog {
+	if o == nil {
+		return nil
+	}
+	return &domain.OutputFileLog{
+		URL:       o.URL,
+		Path:      o.Path,
+		SizeBytes: o.SizeBytes,
+	}
+}
+
 func (e *ExecutorLog) toDO() *domain.ExecutorLog {
 	if e == nil {
 		return nil
@@ -260,9 +366,117 @@ func (e *ExecutorLog) toDO() *domain.ExecutorLog {
 		ExecutorID: e.ExecutorID,
 		StartTime:  e.StartTime,
 		EndTime:    e.EndTime,
+		ExitCode:   e.ExitCode,
+		Stdout:     e.Stdout,
+		Stderr:     e.Stderr,
+	}
+}
+
+func (i *Input) toDO() *domain.Input {
+	if i == nil {
+		return nil
+	}
+	return &domain.Input{
+		Name:        i.Name,
+		Description: i.Description,
+		Path:        i.Path,
+		Type:        i.Type,
+		URL:         i.URL,
+		Content:     i.Content,
+	}
+}
+
+func (o *Output) toDO() *domain.Output {
+	if o == nil {
+		return nil
+	}
+	return &domain.Output{
+		Name:        o.Name,
+		Description: o.Description,
+		Path:        o.Path,
+		Type:        o.Type,
+		URL:         o.URL,
+	}
+}
+
+func (r *Resources) toDO() *domain.Resources {
+	if r == nil {
+		return nil
+	}
+	res := &domain.Resources{
+		CPUCores:                r.CPUCores,
+		RamGB:                   r.RamGB,
+		DiskGB:                  r.DiskGB,
+		BootDiskGB:              r.BootDiskGB,
+		Preemptible:             r.Preemptible,
+		Zones:                   r.Zones,
+		BackendParameters:       r.BackendParameters,
+		BackendParametersStrict: r.BackendParametersStrict,
+	}
+	if r.GPUType != nil && r.GPUCount != nil {
+		res.GPU = &domain.GPUResource{Count: *r.GPUCount, Type: *r.GPUType}
+	}
+	return res
+}
+
+func (e *Executor) toDO() *domain.Executor {
+	if e == nil {
+		return nil
+	}
+	return &domain.Executor{
+		Image:       e.Image,
+		Command:     e.Command,
+		Workdir:     e.Workdir,
+		Stdin:       e.Stdin,
+		Stdout:      e.Stdout,
+		Stderr:      e.Stderr,
+		Env:         e.Env,
+		IgnoreError: e.IgnoreError,
+	}
+}
+
+func (b *BioosInfo) toDO() *domain.BioosInfo {
+	if b == nil {
+		return nil
+	}
+	var meta *domain.BioosInfoMeta
+	if b.Meta != nil {
+		meta = &domain.BioosInfoMeta{
+			AAIPassport:     b.Meta.AAIPassport,
+			MountTOS:        b.Meta.MountTOS,
+			BucketsAuthInfo: b.Meta.BucketsAuthInfo.toDO(),
+		}
+	}
+	return &domain.BioosInfo{
+		AccountID:    b.AccountID,
+		UserID:       b.UserID,
+		SubmissionID: b.SubmissionID,
+		RunID:        b.RunID,
+		Meta:         meta,
 	}
 }
 
+func (b *BucketsAuthInfo) toDO() *domain.BucketsAuthInfo {
+	if b == nil {
+		return nil
+	}
+	res := &domain.BucketsAuthInfo{
+		ReadOnly:  b.ReadOnly,
+		ReadWrite: b.ReadWrite,
+	}
+	if len(b.External) > 0 {
+		res.External = make([]*domain.ExternalBucketAuthInfo, len(b.External))
+		for index, external := range b.External {
+			res.External[index] = &domain.ExternalBucketAuthInfo{
+				Bucket: external.Bucket,
+				AK:     external.AK,
+				SK:     external.SK,
+			}
+		}
+	}
+	return res
+}
+
 func taskStatusDOToPO(taskStatus *domain.TaskStatus) *TaskStatus {
 	if taskStatus == nil {
 		return nil
@@ -274,6 +488,10 @@ func taskStatusDOToPO(taskStatus *domain.TaskStatus) *TaskStatus {
 		},
 		CreationTime: taskStatus.CreationTime,
 		ClusterID:    &taskStatus.ClusterID,
+		QuotaHeld:    &taskStatus.QuotaHeld,
+		MaxRetries:   taskStatus.MaxRetries,
+		Retries:      taskStatus.Retries,
+		FinishTime:   taskStatus.FinishTime,
 	}
 	if len(taskStatus.Logs) > 0 {
 		res.Logs = make([]*TaskLog, len(taskStatus.Logs))
@@ -291,14 +509,18 @@ func taskDOToPO(task *domain.Task) *Task {
 	}
 	res := &Task{
 		TaskBasic: TaskBasic{
-			TaskStatus:    *taskStatusDOToPO(&task.TaskStatus),
-			Name:          task.Name,
-			Description:   task.Description,
-			Resources:     resourcesDOToPO(task.Resources),
-			Volumes:       task.Volumes,
-			Tags:          task.Tags,
-			BioosInfo:     bioosInfoDOToPO(task.BioosInfo),
-			PriorityValue: task.PriorityValue,
+			TaskStatus:        *taskStatusDOToPO(&task.TaskStatus),
+			Name:              task.Name,
+			Description:       task.Description,
+			Resources:         resourcesDOToPO(task.Resources),
+			Volumes:           task.Volumes,
+			Tags:              task.Tags,
+			BioosInfo:         bioosInfoDOToPO(task.BioosInfo),
+			PriorityValue:     task.PriorityValue,
+			EffectivePriority: task.EffectivePriority,
+			CallbackURL:       task.CallbackURL,
+			RetryOf:           task.RetryOf,
+			Attempt:           task.Attempt,
 		},
 	}
 
@@ -323,6 +545,26 @@ func taskDOToPO(task *domain.Task) *Task {
 	return res
 }
 
+func taskTagsToPO(taskID string, tags map[string]string) []*TaskTag {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	res := make([]*TaskTag, 0, len(tags))
+	for _, key := range keys {
+		res = append(res, &TaskTag{
+			TaskID:   taskID,
+			TagKey:   key,
+			TagValue: tags[key],
+		})
+	}
+	return res
+}
+
 func taskLogDOToPO(log *domain.TaskLog) *TaskLog {
 	if log == nil {
 		return nil
@@ -344,9 +586,26 @@ func taskLogDOToPO(log *domain.TaskLog) *TaskLog {
 			}
 		}
 	}
+	if len(log.Outputs) > 0 {
+		res.Outputs = make([]*OutputFileLog, len(log.Outputs))
+		for index, output := range log.Outputs {
+			res.Outputs[index] = outputFileLogDOToPO(output)
+		}
+	}
 	return res
 }
 
+func outputFileLogDOToPO(log *domain.OutputFileLog) *OutputFileLog {
+	if log == nil {
+		return nil
+	}
+	return &OutputFileLog{
+		URL:       log.URL,
+		Path:      log.Path,
+		SizeBytes: log.SizeBytes,
+	}
+}
+
 func executorLogDOToPO(log *domain.ExecutorLog) *ExecutorLog {
 	if log == nil {
 		return nil
@@ -355,6 +614,9 @@ func executorLogDOToPO(log *domain.ExecutorLog) *ExecutorLog {
 		ExecutorID: log.ExecutorID,
 		StartTime:  log.StartTime,
 		EndTime:    log.EndTime,
+		ExitCode:   log.ExitCode,
+		Stdout:     log.Stdout,
+		Stderr:     log.Stderr,
 	}
 }
 
@@ -390,10 +652,14 @@ func resourcesDOToPO(resources *domain.Resources) *Resources {
 		return nil
 	}
 	res := &Resources{
-		CPUCores:   resources.CPUCores,
-		RamGB:      resources.RamGB,
-		DiskGB:     resources.DiskGB,
-		BootDiskGB: resources.BootDiskGB,
+		CPUCores:                resources.CPUCores,
+		RamGB:                   resources.RamGB,
+		DiskGB:                  resources.DiskGB,
+		BootDiskGB:              resources.BootDiskGB,
+		Preemptible:             resources.Preemptible,
+		Zones:                   resources.Zones,
+		BackendParameters:       resources.BackendParameters,
+		BackendParametersStrict: resources.BackendParametersStrict,
 	}
 	if resources.GPU != nil {
 		res.GPUCount = &resources.GPU.Count
@@ -407,13 +673,14 @@ func executorDOToPO(executor *domain.Executor) *Executor {
 		return nil
 	}
 	return &Executor{
-		Image:   executor.Image,
-		Command: executor.Command,
-		Workdir: executor.Workdir,
-		Stdin:   executor.Stdin,
-		Stdout:  executor.Stdout,
-		Stderr:  executor.Stderr,
-		Env:     executor.Env,
+		Image:       executor.Image,
+		Command:     executor.Command,
+		Workdir:     executor.Workdir,
+		Stdin:       executor.Stdin,
+		Stdout:      executor.Stdout,
+		Stderr:      executor.Stderr,
+		Env:         executor.Env,
+		IgnoreError: executor.IgnoreError,
 	}
 }
 
@@ -458,3 +725,107 @@ func bucketsAuthInfoDOToPO(info *domain.BucketsAuthInfo) *BucketsAuthInfo {
 	}
 	return res
 }
+
+func (t *TaskPriority) toDO() *domain.TaskPriority {
+	if t == nil {
+		return nil
+	}
+	return &domain.TaskPriority{
+		ID: t.ID,
+		BioosInfo: &domain.BioosInfo{
+			AccountID:    t.AccountID,
+			UserID:       t.UserID,
+			SubmissionID: t.SubmissionID,
+			RunID:        t.RunID,
+		},
+		PriorityValue:     t.PriorityValue,
+		EffectivePriority: t.EffectivePriority,
+	}
+}
+
+func taskEventsToPO(taskID, requestID string, events []*domain.TaskEvent) []*TaskEvent {
+	if len(events) == 0 {
+		return nil
+	}
+	res := make([]*TaskEvent, 0, len(events))
+	for _, event := range events {
+		taskEvent := &TaskEvent{
+			TaskID:        taskID,
+			EventTime:     event.Time,
+			PreviousState: event.PreviousState,
+			State:         event.State,
+			ClusterID:     event.ClusterID,
+			RequestID:     event.RequestID,
+		}
+		if taskEvent.RequestID == "" {
+			taskEvent.RequestID = requestID
+		}
+		res = append(res, taskEvent)
+	}
+	return res
+}
+
+func (t *TaskEvent) toDTO() *query.TaskEvent {
+	if t == nil {
+		return nil
+	}
+	return &query.TaskEvent{
+		Time:          t.EventTime,
+		PreviousState: t.PreviousState,
+		State:         t.State,
+		ClusterID:     t.ClusterID,
+		RequestID:     t.RequestID,
+	}
+}
+
+func (t *TaskEvent) toWatchDTO() *query.WatchEvent {
+	if t == nil {
+		return nil
+	}
+	return &query.WatchEvent{
+		TaskEvent:       *t.toDTO(),
+		ResourceVersion: t.ID,
+		TaskID:          t.TaskID,
+	}
+}
+
+func taskNotificationsToPO(taskID string, events []*domain.TaskEvent) []*TaskNotification {
+	res := make([]*TaskNotification, 0)
+	for _, event := range events {
+		if !event.Notified() {
+			continue
+		}
+		res = append(res, &TaskNotification{
+			TaskID:    taskID,
+			State:     event.State,
+			EventTime: event.Time,
+		})
+	}
+	return res
+}
+
+func (t *TaskNotification) toDO() *domain.Notification {
+	if t == nil {
+		return nil
+	}
+	return &domain.Notification{
+		ID:       t.ID,
+		TaskID:   t.TaskID,
+		State:    t.State,
+		Time:     t.EventTime,
+		Attempts: t.Attempts,
+	}
+}
+
+func taskIdempotencyKeyDOToPO(taskID string, key *domain.IdempotencyKey) *TaskIdempotencyKey {
+	if key == nil {
+		return nil
+	}
+	return &TaskIdempotencyKey{
+		AccountID:      key.AccountID,
+		UserID:         key.UserID,
+		IdempotencyKey: key.Key,
+		TaskID:         taskID,
+		ExpireTime:     key.ExpireTime,
+	}
+}
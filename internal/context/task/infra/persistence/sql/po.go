@@ -20,6 +20,14 @@ type TaskBasic struct {
 	Tags          map[string]string `gorm:"column:tags;type:LONGTEXT;serializer:json"`
 	BioosInfo     *BioosInfo        `gorm:"embedded"`
 	PriorityValue int               `gorm:"column:priority_value;type:BIGINT;not null;default:0"`
+	// EffectivePriority is PriorityValue plus extra priorities applied to the task
+	EffectivePriority int `gorm:"column:effective_priority;type:BIGINT;not null;default:0;index:state_priority,priority:2"`
+	// CallbackURL is notified when the task is finished
+	CallbackURL string `gorm:"column:callback_url;type:VARCHAR(1024);not null;default:''"`
+	// RetryOf is id of the task resubmitted as this one
+	RetryOf string `gorm:"column:retry_of;type:VARCHAR(16);not null;default:'';index:retry_of"`
+	// Attempt is 1 for the first submission, and increased by each resubmission
+	Attempt int `gorm:"column:attempt;type:BIGINT;not null;default:1"`
 }
 
 // TaskStatus ...
@@ -30,6 +38,13 @@ type TaskStatus struct {
 	// ClusterID may be updated to empty string, we have to mark it as pointer because
 	// gorm do not update default value
 	ClusterID *string `gorm:"column:cluster_id;type:VARCHAR(32);not null;default:'';index:state_cluster,priority:2"`
+	// QuotaHeld may be updated to false, mark it as pointer for the same reason as ClusterID
+	QuotaHeld *bool `gorm:"column:quota_held;type:BOOLEAN;not null;default:false"`
+	// MaxRetries is decided at creation, Retries only increases, so neither is updated to zero
+	MaxRetries int `gorm:"column:max_retries;type:BIGINT;not null;default:0"`
+	Retries    int `gorm:"column:retries;type:BIGINT;not null;default:0"`
+	// FinishTime is null until the task is finished
+	FinishTime *time.Time `gorm:"column:finish_time;type:DATETIME;index:finish_time"`
 
 	StatusResourceVersion int `gorm:"column:status_resource_version;type:BIGINT;not null;default:0"`
 }
@@ -37,7 +52,25 @@ type TaskStatus struct {
 // TaskState ...
 type TaskState struct {
 	ID    string `gorm:"column:id;type:VARCHAR(16);not null;primaryKey"`
-	State string `gorm:"column:state;type:VARCHAR(16);not null;index:state_cluster,priority:1;index:state_account_user,priority:1"`
+	State string `gorm:"column:state;type:VARCHAR(16);not null;index:state_cluster,priority:1;index:state_account_user,priority:1;index:state_priority,priority:1"`
+}
+
+// TaskPriority ...
+type TaskPriority struct {
+	ID                string `gorm:"column:id"`
+	AccountID         string `gorm:"column:account_id"`
+	UserID            string `gorm:"column:user_id"`
+	SubmissionID      string `gorm:"column:submission_id"`
+	RunID             string `gorm:"column:run_id"`
+	PriorityValue     int    `gorm:"column:priority_value"`
+	EffectivePriority int    `gorm:"column:effective_priority"`
+}
+
+// PriorityCursor locates a task when tasks are sorted by priority
+type PriorityCursor struct {
+	ID                string    `gorm:"column:id"`
+	EffectivePriority int       `gorm:"column:effective_priority"`
+	CreationTime      time.Time `gorm:"column:creation_time"`
 }
 
 // Input ...
@@ -61,23 +94,28 @@ type Output struct {
 
 // Resources ...
 type Resources struct {
-	CPUCores   int      `gorm:"column:cpu_cores;type:SMALLINT;not null"`
-	RamGB      float64  `gorm:"column:ram_gb;type:DOUBLE;not null"` // nolint
-	DiskGB     float64  `gorm:"column:disk_gb;type:DOUBLE;not null"`
-	BootDiskGB *int     `gorm:"column:boot_disk_gb;type:SMALLINT"`
-	GPUCount   *float64 `gorm:"column:gpu_count;type:DOUBLE"`
-	GPUType    *string  `gorm:"column:gpu_type;type:VARCHAR(32)"`
+	CPUCores                int               `gorm:"column:cpu_cores;type:SMALLINT;not null"`
+	RamGB                   float64           `gorm:"column:ram_gb;type:DOUBLE;not null"` // nolint
+	DiskGB                  float64           `gorm:"column:disk_gb;type:DOUBLE;not null"`
+	BootDiskGB              *int              `gorm:"column:boot_disk_gb;type:SMALLINT"`
+	GPUCount                *float64          `gorm:"column:gpu_count;type:DOUBLE"`
+	GPUType                 *string           `gorm:"column:gpu_type;type:VARCHAR(32)"`
+	Preemptible             bool              `gorm:"column:preemptible;type:BOOLEAN;not null;default:false"`
+	Zones                   []string          `gorm:"column:zones;type:LONGTEXT;serializer:json"`
+	BackendParameters       map[string]string `gorm:"column:backend_parameters;type:LONGTEXT;serializer:json"`
+	BackendParametersStrict bool              `gorm:"column:backend_parameters_strict;type:BOOLEAN;not null;default:false"`
 }
 
 // Executor ...
 type Executor struct {
-	Image   string            `json:"image"`
-	Command []string          `json:"command"`
-	Workdir string            `json:"workdir,omitempty"`
-	Stdin   string            `json:"stdin,omitempty"`
-	Stdout  string            `json:"stdout,omitempty"`
-	Stderr  string            `json:"stderr,omitempty"`
-	Env     map[string]string `json:"env,omitempty"`
+	Image       string            `json:"image"`
+	Command     []string          `json:"command"`
+	Workdir     string            `json:"workdir,omitempty"`
+	Stdin       string            `json:"stdin,omitempty"`
+	Stdout      string            `json:"stdout,omitempty"`
+	Stderr      string            `json:"stderr,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	IgnoreError bool              `json:"ignore_error,omitempty"`
 }
 
 // TaskLog ...
@@ -87,6 +125,14 @@ type TaskLog struct {
 	StartTime  *time.Time       `json:"start_time,omitempty"`
 	EndTime    *time.Time       `json:"end_time,omitempty"`
 	SystemLogs []string         `json:"system_logs,omitempty"`
+	Outputs    []*OutputFileLog `json:"outputs,omitempty"`
+}
+
+// OutputFileLog ...
+type OutputFileLog struct {
+	URL       string `json:"url"`
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
 }
 
 // ExecutorLog ...
@@ -94,6 +140,9 @@ type ExecutorLog struct {
 	ExecutorID string     `json:"executor_id"`
 	StartTime  *time.Time `json:"start_time,omitempty"`
 	EndTime    *time.Time `json:"end_time,omitempty"`
+	ExitCode   *int32     `json:"exit_code,omitempty"`
+	Stdout     string     `json:"stdout,omitempty"`
+	Stderr     string     `json:"stderr,omitempty"`
 }
 
 // BioosInfo ...
@@ -130,3 +179,69 @@ type ExternalBucketAuthInfo struct {
 func (t *Task) TableName() string {
 	return "task"
 }
+
+// TaskArchive keeps tasks moved out of task by retention, it has the same columns as Task
+type TaskArchive struct {
+	Task
+}
+
+// TableName ...
+func (t *TaskArchive) TableName() string {
+	return "task_archive"
+}
+
+// TaskTag is a side table of Task.Tags, which makes tags queryable
+type TaskTag struct {
+	TaskID   string `gorm:"column:task_id;type:VARCHAR(16);not null;primaryKey"`
+	TagKey   string `gorm:"column:tag_key;type:VARCHAR(128);not null;primaryKey;index:key_value,priority:1"`
+	TagValue string `gorm:"column:tag_value;type:VARCHAR(512);not null;default:'';index:key_value,priority:2"`
+}
+
+// TableName ...
+func (t *TaskTag) TableName() string {
+	return "task_tag"
+}
+
+// TaskEvent is a state transition history record of Task
+type TaskEvent struct {
+	ID            int64     `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement"`
+	TaskID        string    `gorm:"column:task_id;type:VARCHAR(16);not null;index:task_id"`
+	EventTime     time.Time `gorm:"column:event_time;type:DATETIME;not null"`
+	PreviousState string    `gorm:"column:previous_state;type:VARCHAR(16);not null;default:''"`
+	State         string    `gorm:"column:state;type:VARCHAR(16);not null"`
+	ClusterID     string    `gorm:"column:cluster_id;type:VARCHAR(32);not null;default:''"`
+	RequestID     string    `gorm:"column:request_id;type:VARCHAR(64);not null;default:''"`
+}
+
+// TableName ...
+func (t *TaskEvent) TableName() string {
+	return "task_event"
+}
+
+// TaskNotification is an outbox record of Task reaching a finished state
+type TaskNotification struct {
+	ID        int64     `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement"`
+	TaskID    string    `gorm:"column:task_id;type:VARCHAR(16);not null"`
+	State     string    `gorm:"column:state;type:VARCHAR(16);not null"`
+	EventTime time.Time `gorm:"column:event_time;type:DATETIME;not null"`
+	Attempts  int       `gorm:"column:attempts;type:INT;not null;default:0"`
+}
+
+// TableName ...
+func (t *TaskNotification) TableName() string {
+	return "task_notification"
+}
+
+// TaskIdempotencyKey is an idempotency key of the owner held by the Task created with it
+type TaskIdempotencyKey struct {
+	AccountID      string    `gorm:"column:account_id;type:VARCHAR(32);not null;default:'';primaryKey"`
+	UserID         string    `gorm:"column:user_id;type:VARCHAR(32);not null;default:'';primaryKey"`
+	IdempotencyKey string    `gorm:"column:idempotency_key;type:VARCHAR(128);not null;primaryKey"`
+	TaskID         string    `gorm:"column:task_id;type:VARCHAR(16);not null;index:idempotency_task_id"`
+	ExpireTime     time.Time `gorm:"column:expire_time;type:DATETIME;not null;index:idempotency_expire_time"`
+}
+
+// TableName ...
+func (t *TaskIdempotencyKey) TableName() string {
+	return "task_idempotency_key"
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	applog "github.com/GBA-BI/tes-api/pkg/log"
 	"gorm.io/gorm"
@@ -19,33 +21,30 @@ type readModel struct {
 
 // NewReadModel ...
 func NewReadModel(ctx context.Context, db *gorm.DB) (query.ReadModel, error) {
-	if err := db.WithContext(ctx).AutoMigrate(&Task{}); err != nil {
-		return nil, err
-	}
 	return &readModel{db: db}, nil
 }
 
 var _ query.ReadModel = (*readModel)(nil)
 
 // ListMinimal ...
-func (r *readModel) ListMinimal(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *query.ListFilter) ([]*query.TaskMinimal, *utils.PageToken, error) {
-	db := r.db.WithContext(ctx).Model(&Task{}).Order("`id`")
+func (r *readModel) ListMinimal(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *query.ListFilter) ([]*query.TaskMinimal, *utils.PageToken, error) {
+	db := r.db.WithContext(ctx).Model(&Task{})
 	db = listFilter(db, filter)
-
+	db = listOrder(db, sortBy, pageToken)
 	db = db.Limit(pageSize)
-	if pageToken != nil {
-		db = db.Where("`id` > ?", pageToken.LastID)
-	}
 
 	taskStates := make([]*TaskState, 0)
-	if err := db.Find(&taskStates).Error; err != nil {
+	var err error
+	if err = db.Find(&taskStates).Error; err != nil {
 		applog.Errorw("failed to list taskStates", "err", err)
 		return nil, nil, apperrors.NewInternalError(err)
 	}
 	// maybe remains more tasks
 	var nextPageToken *utils.PageToken
 	if len(taskStates) == pageSize {
-		nextPageToken = &utils.PageToken{LastID: taskStates[len(taskStates)-1].ID}
+		if nextPageToken, err = r.nextPageToken(ctx, sortBy, taskStates[len(taskStates)-1].ID); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	res := make([]*query.TaskMinimal, 0, len(taskStates))
@@ -56,24 +55,24 @@ func (r *readModel) ListMinimal(ctx context.Context, pageSize int, pageToken *ut
 }
 
 // ListBasic ...
-func (r *readModel) ListBasic(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *query.ListFilter) ([]*query.TaskBasic, *utils.PageToken, error) {
-	db := r.db.WithContext(ctx).Model(&Task{}).Order("`id`")
+func (r *readModel) ListBasic(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *query.ListFilter) ([]*query.TaskBasic, *utils.PageToken, error) {
+	db := r.db.WithContext(ctx).Model(&Task{})
 	db = listFilter(db, filter)
-
+	db = listOrder(db, sortBy, pageToken)
 	db = db.Limit(pageSize)
-	if pageToken != nil {
-		db = db.Where("`id` > ?", pageToken.LastID)
-	}
 
 	taskBasics := make([]*TaskBasic, 0)
-	if err := db.Find(&taskBasics).Error; err != nil {
+	var err error
+	if err = db.Find(&taskBasics).Error; err != nil {
 		applog.Errorw("failed to list taskBasics", "err", err)
 		return nil, nil, apperrors.NewInternalError(err)
 	}
 	// maybe remains more tasks
 	var nextPageToken *utils.PageToken
 	if len(taskBasics) == pageSize {
-		nextPageToken = &utils.PageToken{LastID: taskBasics[len(taskBasics)-1].ID}
+		if nextPageToken, err = r.nextPageToken(ctx, sortBy, taskBasics[len(taskBasics)-1].ID); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	res := make([]*query.TaskBasic, 0, len(taskBasics))
@@ -84,24 +83,24 @@ func (r *readModel) ListBasic(ctx context.Context, pageSize int, pageToken *util
 }
 
 // ListFull ...
-func (r *readModel) ListFull(ctx context.Context, pageSize int, pageToken *utils.PageToken, filter *query.ListFilter) ([]*query.Task, *utils.PageToken, error) {
-	db := r.db.WithContext(ctx).Model(&Task{}).Order("`id`")
+func (r *readModel) ListFull(ctx context.Context, pageSize int, pageToken *utils.PageToken, sortBy string, filter *query.ListFilter) ([]*query.Task, *utils.PageToken, error) {
+	db := r.db.WithContext(ctx).Model(&Task{})
 	db = listFilter(db, filter)
-
+	db = listOrder(db, sortBy, pageToken)
 	db = db.Limit(pageSize)
-	if pageToken != nil {
-		db = db.Where("`id` > ?", pageToken.LastID)
-	}
 
 	tasks := make([]*Task, 0)
-	if err := db.Find(&tasks).Error; err != nil {
+	var err error
+	if err = db.Find(&tasks).Error; err != nil {
 		applog.Errorw("failed to list tasks", "err", err)
 		return nil, nil, apperrors.NewInternalError(err)
 	}
 	// maybe remains more tasks
 	var nextPageToken *utils.PageToken
 	if len(tasks) == pageSize {
-		nextPageToken = &utils.PageToken{LastID: tasks[len(tasks)-1].ID}
+		if nextPageToken, err = r.nextPageToken(ctx, sortBy, tasks[len(tasks)-1].ID); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	res := make([]*query.Task, 0, len(tasks))
@@ -111,10 +110,27 @@ func (r *readModel) ListFull(ctx context.Context, pageSize int, pageToken *utils
 	return res, nextPageToken, nil
 }
 
+// nextPageToken locates the last task of current page in the order of sortBy
+func (r *readModel) nextPageToken(ctx context.Context, sortBy, lastID string) (*utils.PageToken, error) {
+	if sortBy != query.SortByPriority {
+		return &utils.PageToken{LastID: lastID}, nil
+	}
+	var cursor PriorityCursor
+	if err := r.db.WithContext(ctx).Model(&Task{}).Where("id = ?", lastID).First(&cursor).Error; err != nil {
+		applog.Errorw("failed to get priority cursor", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	return &utils.PageToken{
+		LastID:                cursor.ID,
+		LastEffectivePriority: &cursor.EffectivePriority,
+		LastCreationTime:      &cursor.CreationTime,
+	}, nil
+}
+
 // GetMinimal ...
 func (r *readModel) GetMinimal(ctx context.Context, id string) (*query.TaskMinimal, error) {
 	var taskState TaskState
-	if err := r.db.WithContext(ctx).Model(&Task{}).Where("`id` = ?", id).First(&taskState).Error; err != nil {
+	if err := firstTask(ctx, r.db, id, &taskState); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewNotFoundError("task", id)
 		}
@@ -127,7 +143,7 @@ func (r *readModel) GetMinimal(ctx context.Context, id string) (*query.TaskMinim
 // GetBasic ...
 func (r *readModel) GetBasic(ctx context.Context, id string) (*query.TaskBasic, error) {
 	var taskBasic TaskBasic
-	if err := r.db.WithContext(ctx).Model(&Task{}).Where("`id` = ?", id).First(&taskBasic).Error; err != nil {
+	if err := firstTask(ctx, r.db, id, &taskBasic); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewNotFoundError("task", id)
 		}
@@ -140,7 +156,7 @@ func (r *readModel) GetBasic(ctx context.Context, id string) (*query.TaskBasic,
 // GetFull ...
 func (r *readModel) GetFull(ctx context.Context, id string) (*query.Task, error) {
 	var task Task
-	if err := r.db.WithContext(ctx).Model(&Task{}).Where("`id` = ?", id).First(&task).Error; err != nil {
+	if err := firstTask(ctx, r.db, id, &task); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewNotFoundError("task", id)
 		}
@@ -164,7 +180,7 @@ func (r *readModel) GatherResources(ctx context.Context, filter *query.GatherFil
 		RamGB    float64 `gorm:"column:ram_gb"` // nolint
 		DiskGB   float64 `gorm:"column:disk_gb"`
 	}
-	if err := db.Select("COUNT(*) AS `count`, SUM(`cpu_cores`) AS `cpu_cores`, SUM(`ram_gb`) AS `ram_gb`, SUM(`disk_gb`) AS `disk_gb`").Find(&normalResources).Error; err != nil {
+	if err := db.Select("COUNT(*) AS count, SUM(cpu_cores) AS cpu_cores, SUM(ram_gb) AS ram_gb, SUM(disk_gb) AS disk_gb").Find(&normalResources).Error; err != nil {
 		applog.Errorw("failed to gather tasks normal resources", "err", err)
 		return nil, apperrors.NewInternalError(err)
 	}
@@ -177,8 +193,8 @@ func (r *readModel) GatherResources(ctx context.Context, filter *query.GatherFil
 		Type  string  `gorm:"column:gpu_type"`
 		Count float64 `gorm:"column:gpu_count"`
 	}
-	if err := db.Select("`gpu_type`, SUM(`gpu_count`) AS `gpu_count`").Group("gpu_type").
-		Where("`gpu_type` IS NOT NULL AND `gpu_count` IS NOT NULL").Find(&gpuResources).Error; err != nil {
+	if err := db.Select("gpu_type, SUM(gpu_count) AS gpu_count").Group("gpu_type").
+		Where("gpu_type IS NOT NULL AND gpu_count IS NOT NULL").Find(&gpuResources).Error; err != nil {
 		applog.Errorw("failed to gather tasks gpu resources", "err", err)
 		return nil, apperrors.NewInternalError(err)
 	}
@@ -192,6 +208,91 @@ func (r *readModel) GatherResources(ctx context.Context, filter *query.GatherFil
 	return res, nil
 }
 
+// ListEvents ...
+func (r *readModel) ListEvents(ctx context.Context, id string) ([]*query.TaskEvent, error) {
+	taskEvents := make([]*TaskEvent, 0)
+	if err := r.db.WithContext(ctx).Model(&TaskEvent{}).Where("task_id = ?", id).
+		Order("id").Find(&taskEvents).Error; err != nil {
+		applog.Errorw("failed to list task events", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*query.TaskEvent, 0, len(taskEvents))
+	for _, taskEvent := range taskEvents {
+		res = append(res, taskEvent.toDTO())
+	}
+	return res, nil
+}
+
+// ListEventsAfter ...
+func (r *readModel) ListEventsAfter(ctx context.Context, resourceVersion, until int64, limit int, filter *query.WatchFilter) ([]*query.WatchEvent, error) {
+	db := r.db.WithContext(ctx).Model(&TaskEvent{}).Select("task_event.*").
+		Where("task_event.id > ? AND task_event.id <= ?", resourceVersion, until)
+	db = watchFilter(db, filter)
+
+	taskEvents := make([]*TaskEvent, 0)
+	if err := db.Order("task_event.id").Limit(limit).Find(&taskEvents).Error; err != nil {
+		applog.Errorw("failed to list task events after resource version", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*query.WatchEvent, 0, len(taskEvents))
+	for _, taskEvent := range taskEvents {
+		res = append(res, taskEvent.toWatchDTO())
+	}
+	return res, nil
+}
+
+// GetSettledResourceVersion ...
+func (r *readModel) GetSettledResourceVersion(ctx context.Context, resourceVersion int64, limit int, settledBefore time.Time) (int64, error) {
+	taskEvents := make([]*TaskEvent, 0)
+	if err := r.db.WithContext(ctx).Model(&TaskEvent{}).Select("id", "event_time").Where("id > ?", resourceVersion).
+		Order("id").Limit(limit).Find(&taskEvents).Error; err != nil {
+		applog.Errorw("failed to scan task events after resource version", "err", err)
+		return 0, apperrors.NewInternalError(err)
+	}
+	res := resourceVersion
+	for _, taskEvent := range taskEvents {
+		if taskEvent.ID != res+1 && taskEvent.EventTime.After(settledBefore) {
+			break
+		}
+		res = taskEvent.ID
+	}
+	return res, nil
+}
+
+func watchFilter(db *gorm.DB, filter *query.WatchFilter) *gorm.DB {
+	if filter == nil {
+		return db
+	}
+	if len(filter.IDs) > 0 {
+		db = db.Where("task_event.task_id IN ?", filter.IDs)
+	}
+	if filter.AccountID == "" && filter.RunID == "" {
+		return db
+	}
+	db = db.Joins("JOIN task ON task.id = task_event.task_id")
+	if filter.AccountID != "" {
+		db = db.Where("task.account_id = ?", filter.AccountID)
+	}
+	if filter.UserID != "" {
+		db = db.Where("task.user_id = ?", filter.UserID)
+	}
+	if filter.RunID != "" {
+		db = db.Where("task.run_id = ?", filter.RunID)
+	}
+	return db
+}
+
+// GetLatestResourceVersion ...
+func (r *readModel) GetLatestResourceVersion(ctx context.Context) (int64, error) {
+	var resourceVersion int64
+	if err := r.db.WithContext(ctx).Model(&TaskEvent{}).Select("COALESCE(MAX(id), 0)").
+		Scan(&resourceVersion).Error; err != nil {
+		applog.Errorw("failed to get latest resource version", "err", err)
+		return 0, apperrors.NewInternalError(err)
+	}
+	return resourceVersion, nil
+}
+
 // ListAccounts ...
 func (r *readModel) ListAccounts(ctx context.Context) ([]*query.AccountInfo, error) {
 	db := r.db.WithContext(ctx).Model(&Task{})
@@ -200,7 +301,7 @@ func (r *readModel) ListAccounts(ctx context.Context) ([]*query.AccountInfo, err
 		AccountID string `gorm:"column:account_id"`
 		UserID    string `gorm:"column:user_id"`
 	}
-	if err := db.Distinct("`account_id`", "`user_id`").Find(&accountWithUsers).Error; err != nil {
+	if err := db.Distinct("account_id", "user_id").Find(&accountWithUsers).Error; err != nil {
 		applog.Errorw("failed to list task accounts", "err", err)
 		return nil, apperrors.NewInternalError(err)
 	}
@@ -231,16 +332,70 @@ func listFilter(db *gorm.DB, filter *query.ListFilter) *gorm.DB {
 		return db
 	}
 	if filter.NamePrefix != "" {
-		db = db.Where("`name` LIKE ?", fmt.Sprintf("%s%%", utils.EscapeLikeSpecialChars(filter.NamePrefix)))
+		db = db.Where("name LIKE ? ESCAPE '"+utils.LikeEscapeChar+"'", fmt.Sprintf("%s%%", utils.EscapeLikeSpecialChars(filter.NamePrefix)))
 	}
 	if len(filter.State) > 0 {
-		db = db.Where("`state` IN ?", filter.State)
+		db = db.Where("state IN ?", filter.State)
 	}
 	if filter.ClusterID != "" {
-		db = db.Where("`cluster_id` = ?", filter.ClusterID)
+		db = db.Where("cluster_id = ?", filter.ClusterID)
 	}
 	if filter.WithoutCluster {
-		db = db.Where("`cluster_id` = ''")
+		db = db.Where("cluster_id = ''")
+	}
+	if filter.QuotaHeld != nil {
+		db = db.Where("quota_held = ?", *filter.QuotaHeld)
+	}
+	if filter.AccountID != "" {
+		db = db.Where("account_id = ?", filter.AccountID)
+	}
+	if filter.RetryOf != "" {
+		db = db.Where("retry_of = ?", filter.RetryOf)
+	}
+	// every tag shall be matched, empty value matches any value of the key
+	tagKeys := make([]string, 0, len(filter.Tags))
+	for key := range filter.Tags {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+	for _, key := range tagKeys {
+		tagDB := db.Session(&gorm.Session{NewDB: true}).Model(&TaskTag{}).Select("task_id").
+			Where("tag_key = ?", key)
+		if value := filter.Tags[key]; value != "" {
+			tagDB = tagDB.Where("tag_value = ?", value)
+		}
+		db = db.Where("id IN (?)", tagDB)
+	}
+	return db
+}
+
+func listOrder(db *gorm.DB, sortBy string, pageToken *utils.PageToken) *gorm.DB {
+	if sortBy != query.SortByPriority {
+		db = db.Order("id")
+		if pageToken != nil {
+			db = db.Where("id > ?", pageToken.LastID)
+		}
+		return db
+	}
+
+	var after *PriorityCursor
+	if pageToken != nil {
+		after = &PriorityCursor{
+			ID:                pageToken.LastID,
+			EffectivePriority: *pageToken.LastEffectivePriority,
+			CreationTime:      *pageToken.LastCreationTime,
+		}
+	}
+	return orderByPriority(db, after)
+}
+
+// orderByPriority orders tasks by effective priority descending, then by creation time,
+// and only tasks after the cursor are selected if it is not nil
+func orderByPriority(db *gorm.DB, after *PriorityCursor) *gorm.DB {
+	db = db.Order("effective_priority DESC").Order("creation_time").Order("id")
+	if after != nil {
+		db = db.Where("effective_priority < ? OR (effective_priority = ? AND (creation_time > ? OR (creation_time = ? AND id > ?)))",
+			after.EffectivePriority, after.EffectivePriority, after.CreationTime, after.CreationTime, after.ID)
 	}
 	return db
 }
@@ -250,18 +405,21 @@ func gatherFilter(db *gorm.DB, filter *query.GatherFilter) *gorm.DB {
 		return db
 	}
 	if len(filter.State) > 0 {
-		db = db.Where("`state` IN ?", filter.State)
+		db = db.Where("state IN ?", filter.State)
 	}
 	if filter.ClusterID != "" {
-		db = db.Where("`cluster_id` = ?", filter.ClusterID)
+		db = db.Where("cluster_id = ?", filter.ClusterID)
 	} else if filter.WithCluster {
-		db = db.Where("`cluster_id` <> ''")
+		db = db.Where("cluster_id <> ''")
 	}
 	if filter.AccountID != "" {
-		db = db.Where("`account_id` = ?", filter.AccountID)
+		db = db.Where("account_id = ?", filter.AccountID)
 	}
 	if filter.UserID != "" {
-		db = db.Where("`user_id` = ?", filter.UserID)
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.QuotaHeld != nil {
+		db = db.Where("quota_held = ?", *filter.QuotaHeld)
 	}
 	return db
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/onsi/gomega"
@@ -31,15 +32,19 @@ var taskDTO = &query.Task{
 				Count: 2,
 				Type:  "gpu-01",
 			},
+			Preemptible:       true,
+			Zones:             []string{"zone-a"},
+			BackendParameters: map[string]string{"VmSize": "Standard_D64_v3"},
 		},
 		Executors: []*query.Executor{{
-			Image:   "image:tag",
-			Command: []string{"command"},
-			Workdir: "/base/workdir",
-			Stdin:   "/base/stdin",
-			Stdout:  "/base/stdout",
-			Stderr:  "/base/stderr",
-			Env:     map[string]string{"abc": "def"},
+			Image:       "image:tag",
+			Command:     []string{"command"},
+			Workdir:     "/base/workdir",
+			Stdin:       "/base/stdin",
+			Stdout:      "/base/stdout",
+			Stderr:      "/base/stderr",
+			Env:         map[string]string{"abc": "def"},
+			IgnoreError: true,
 		}},
 		Volumes: []string{"volume"},
 		Tags:    map[string]string{"kkk": "vvv"},
@@ -73,8 +78,14 @@ var taskDTO = &query.Task{
 				},
 			},
 		},
-		PriorityValue: 100,
-		ClusterID:     "cluster-01",
+		PriorityValue:     100,
+		EffectivePriority: 120,
+		CallbackURL:       "https://example.com/callback",
+		RetryOf:           "task-0000",
+		Attempt:           2,
+		MaxRetries:        3,
+		Retries:           1,
+		ClusterID:         "cluster-01",
 	},
 	Inputs: []*query.Input{{
 		Name:        "filein",
@@ -97,10 +108,10 @@ func TestListMinimal(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` ORDER BY `id` LIMIT 10",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` ORDER BY id LIMIT 10",
 		testutil.GenSelectFieldsSql("task", taskStateRows))).
 		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
-	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 10, nil, nil)
+	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 10, nil, query.SortByID, nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(nextPageToken).To(gomega.BeNil())
 	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.TaskMinimal{&taskDTO.TaskMinimal}))
@@ -110,11 +121,11 @@ func TestListMinimalWithPageToken(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `id` > ? ORDER BY `id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id > ? ORDER BY id LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskStateRows))).
 		WithArgs("task-1111").
 		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
-	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 1, &utils.PageToken{LastID: "task-1111"}, nil)
+	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 1, &utils.PageToken{LastID: "task-1111"}, query.SortByID, nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(nextPageToken).To(gomega.BeEquivalentTo(&utils.PageToken{LastID: id}))
 	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.TaskMinimal{&taskDTO.TaskMinimal}))
@@ -124,11 +135,11 @@ func TestListMinimalWithFilter(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `name` LIKE ? AND `state` IN (?,?) AND `cluster_id` = ? AND `id` > ? ORDER BY `id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE name LIKE ? ESCAPE '!' AND state IN (?,?) AND cluster_id = ? AND id > ? ORDER BY id LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskStateRows))).
-		WithArgs("task\\%1\\_1%", consts.TaskRunning, consts.TaskQueued, "cluster-01", "task-1111").
+		WithArgs("task!%1!_1%", consts.TaskRunning, consts.TaskQueued, "cluster-01", "task-1111").
 		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
-	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 1, &utils.PageToken{LastID: "task-1111"}, &query.ListFilter{
+	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 1, &utils.PageToken{LastID: "task-1111"}, query.SortByID, &query.ListFilter{
 		NamePrefix: "task%1_1",
 		State:      []string{consts.TaskRunning, consts.TaskQueued},
 		ClusterID:  "cluster-01",
@@ -142,11 +153,11 @@ func TestListMinimalWithFilterWithoutCluster(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `cluster_id` = '' AND `id` > ? ORDER BY `id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE cluster_id = '' AND id > ? ORDER BY id LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskStateRows))).
 		WithArgs("task-1111").
 		WillReturnRows(sqlmock.NewRows(taskStateRows))
-	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 1, &utils.PageToken{LastID: "task-1111"}, &query.ListFilter{
+	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 1, &utils.PageToken{LastID: "task-1111"}, query.SortByID, &query.ListFilter{
 		WithoutCluster: true,
 	})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -154,23 +165,100 @@ func TestListMinimalWithFilterWithoutCluster(t *testing.T) {
 	g.Expect(resp).To(gomega.BeEmpty())
 }
 
+func TestListMinimalWithFilterRetryOf(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE retry_of = ? ORDER BY id LIMIT 1",
+		testutil.GenSelectFieldsSql("task", taskStateRows))).
+		WithArgs("task-0000").
+		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
+	resp, _, err := r.ListMinimal(context.TODO(), 1, nil, query.SortByID, &query.ListFilter{
+		RetryOf: "task-0000",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.TaskMinimal{&taskDTO.TaskMinimal}))
+}
+
+func TestListMinimalWithFilterAccount(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE state IN (?) AND account_id = ? ORDER BY id LIMIT 1",
+		testutil.GenSelectFieldsSql("task", taskStateRows))).
+		WithArgs(consts.TaskQueued, "account-01").
+		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
+	resp, _, err := r.ListMinimal(context.TODO(), 1, nil, query.SortByID, &query.ListFilter{
+		State:     []string{consts.TaskQueued},
+		AccountID: "account-01",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.TaskMinimal{&taskDTO.TaskMinimal}))
+}
+
+func TestListMinimalWithFilterTags(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id IN (SELECT `task_id` FROM `task_tag` WHERE tag_key = ?) "+
+		"AND id IN (SELECT `task_id` FROM `task_tag` WHERE tag_key = ? AND tag_value = ?) ORDER BY id LIMIT 10",
+		testutil.GenSelectFieldsSql("task", taskStateRows))).
+		WithArgs("kk1", "kkk", "vvv").
+		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
+	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 10, nil, query.SortByID, &query.ListFilter{
+		Tags: map[string]string{"kkk": "vvv", "kk1": ""},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(nextPageToken).To(gomega.BeNil())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.TaskMinimal{&taskDTO.TaskMinimal}))
+}
+
+func TestListMinimalSortByPriority(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE state IN (?) AND "+
+		"(effective_priority < ? OR (effective_priority = ? AND (creation_time > ? OR (creation_time = ? AND id > ?)))) "+
+		"ORDER BY effective_priority DESC,creation_time,id LIMIT 1",
+		testutil.GenSelectFieldsSql("task", taskStateRows))).
+		WithArgs(consts.TaskQueued, 200, 200, now, now, "task-1111").
+		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
+	mock.ExpectQuery("SELECT `task`.`id`,`task`.`effective_priority`,`task`.`creation_time` FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "effective_priority", "creation_time"}).AddRow(taskPO.ID, taskPO.EffectivePriority, taskPO.CreationTime))
+	resp, nextPageToken, err := r.ListMinimal(context.TODO(), 1, &utils.PageToken{
+		LastID:                "task-1111",
+		LastEffectivePriority: utils.Point(200),
+		LastCreationTime:      &now,
+	}, query.SortByPriority, &query.ListFilter{State: []string{consts.TaskQueued}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(nextPageToken).To(gomega.BeEquivalentTo(&utils.PageToken{
+		LastID:                id,
+		LastEffectivePriority: utils.Point(120),
+		LastCreationTime:      &now,
+	}))
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.TaskMinimal{&taskDTO.TaskMinimal}))
+}
+
 func TestListBasic(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` ORDER BY `id` LIMIT 10",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` ORDER BY id LIMIT 10",
 		testutil.GenSelectFieldsSql("task", taskBasicRow))).
 		WillReturnRows(sqlmock.NewRows(taskBasicRow).AddRow(taskPO.ID, taskPO.State,
-			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
 			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
 			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
-			taskPO.Resources.GPUCount, taskPO.Resources.GPUType,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
 			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
 			testutil.MustJSONMarshal(taskPO.Tags),
 			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
 			taskPO.BioosInfo.RunID,
-			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue))
-	resp, nextPageToken, err := r.ListBasic(context.TODO(), 10, nil, nil)
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt))
+	resp, nextPageToken, err := r.ListBasic(context.TODO(), 10, nil, query.SortByID, nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(nextPageToken).To(gomega.BeNil())
 	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.TaskBasic{&taskDTO.TaskBasic}))
@@ -180,19 +268,21 @@ func TestListFull(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `task` ORDER BY `id` LIMIT 10").
+	mock.ExpectQuery("SELECT * FROM `task` ORDER BY id LIMIT 10").
 		WillReturnRows(sqlmock.NewRows(taskRows).AddRow(taskPO.ID, taskPO.State,
-			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
 			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
 			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
-			taskPO.Resources.GPUCount, taskPO.Resources.GPUType,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
 			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
 			testutil.MustJSONMarshal(taskPO.Tags),
 			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
 			taskPO.BioosInfo.RunID,
-			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt,
 			testutil.MustJSONMarshal(taskPO.Inputs), testutil.MustJSONMarshal(taskPO.Outputs)))
-	resp, nextPageToken, err := r.ListFull(context.TODO(), 10, nil, nil)
+	resp, nextPageToken, err := r.ListFull(context.TODO(), 10, nil, query.SortByID, nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(nextPageToken).To(gomega.BeNil())
 	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.Task{taskDTO}))
@@ -202,7 +292,7 @@ func TestGetMinimal(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskStateRows))).WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(taskStateRows).AddRow(taskPO.ID, taskPO.State))
 	resp, err := r.GetMinimal(context.TODO(), id)
@@ -214,9 +304,12 @@ func TestGetMinimalNotFound(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskStateRows))).WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(taskStateRows))
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task_archive` WHERE id = ? ORDER BY `task_archive`.`id` LIMIT 1",
+		testutil.GenSelectFieldsSql("task_archive", taskStateRows))).WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(taskStateRows))
 	_, err := r.GetMinimal(context.TODO(), id)
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
 }
@@ -225,18 +318,20 @@ func TestGetBasic(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskBasicRow))).WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(taskBasicRow).AddRow(taskPO.ID, taskPO.State,
-			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
 			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
 			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
-			taskPO.Resources.GPUCount, taskPO.Resources.GPUType,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
 			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
 			testutil.MustJSONMarshal(taskPO.Tags),
 			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
 			taskPO.BioosInfo.RunID,
-			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue))
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt))
 	resp, err := r.GetBasic(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(resp).To(gomega.BeEquivalentTo(&taskDTO.TaskBasic))
@@ -246,9 +341,12 @@ func TestGetBasicNotFound(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskBasicRow))).WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(taskBasicRow))
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task_archive` WHERE id = ? ORDER BY `task_archive`.`id` LIMIT 1",
+		testutil.GenSelectFieldsSql("task_archive", taskBasicRow))).WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(taskBasicRow))
 	_, err := r.GetBasic(context.TODO(), id)
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
 }
@@ -257,17 +355,19 @@ func TestGetFull(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1").WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(taskRows).AddRow(taskPO.ID, taskPO.State,
-			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
 			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
 			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
-			taskPO.Resources.GPUCount, taskPO.Resources.GPUType,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
 			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
 			testutil.MustJSONMarshal(taskPO.Tags),
 			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
 			taskPO.BioosInfo.RunID,
-			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt,
 			testutil.MustJSONMarshal(taskPO.Inputs), testutil.MustJSONMarshal(taskPO.Outputs)))
 	resp, err := r.GetFull(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -278,19 +378,48 @@ func TestGetFullNotFound(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT * FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1").WithArgs(id).
+	mock.ExpectQuery("SELECT * FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(taskRows))
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task_archive` WHERE id = ? ORDER BY `task_archive`.`id` LIMIT 1",
+		testutil.GenSelectFieldsSql("task_archive", taskRows))).WithArgs(id).
 		WillReturnRows(sqlmock.NewRows(taskRows))
 	_, err := r.GetFull(context.TODO(), id)
 	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
 }
 
+func TestGetFullArchived(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(taskRows))
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task_archive` WHERE id = ? ORDER BY `task_archive`.`id` LIMIT 1",
+		testutil.GenSelectFieldsSql("task_archive", taskRows))).WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(taskRows).AddRow(taskPO.ID, taskPO.State,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
+			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
+			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
+			testutil.MustJSONMarshal(taskPO.Tags),
+			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
+			taskPO.BioosInfo.RunID,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt,
+			testutil.MustJSONMarshal(taskPO.Inputs), testutil.MustJSONMarshal(taskPO.Outputs)))
+	resp, err := r.GetFull(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo(taskDTO))
+}
+
 func TestGatherResources(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT COUNT(*) AS `count`, SUM(`cpu_cores`) AS `cpu_cores`, SUM(`ram_gb`) AS `ram_gb`, SUM(`disk_gb`) AS `disk_gb` FROM `task`").
+	mock.ExpectQuery("SELECT COUNT(*) AS count, SUM(cpu_cores) AS cpu_cores, SUM(ram_gb) AS ram_gb, SUM(disk_gb) AS disk_gb FROM `task`").
 		WillReturnRows(sqlmock.NewRows([]string{"count", "cpu_cores", "ram_gb", "disk_gb"}).AddRow(5, 10, 20, 30))
-	mock.ExpectQuery("SELECT `gpu_type`, SUM(`gpu_count`) AS `gpu_count` FROM `task` WHERE `gpu_type` IS NOT NULL AND `gpu_count` IS NOT NULL GROUP BY `gpu_type`").
+	mock.ExpectQuery("SELECT gpu_type, SUM(gpu_count) AS gpu_count FROM `task` WHERE gpu_type IS NOT NULL AND gpu_count IS NOT NULL GROUP BY `gpu_type`").
 		WillReturnRows(sqlmock.NewRows([]string{"gpu_type", "gpu_count"}).AddRow("gpu-01", 2).AddRow("gpu-02", 3))
 	resp, err := r.GatherResources(context.TODO(), nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -310,9 +439,9 @@ func TestGatherResourcesEmptyGPU(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT COUNT(*) AS `count`, SUM(`cpu_cores`) AS `cpu_cores`, SUM(`ram_gb`) AS `ram_gb`, SUM(`disk_gb`) AS `disk_gb` FROM `task`").
+	mock.ExpectQuery("SELECT COUNT(*) AS count, SUM(cpu_cores) AS cpu_cores, SUM(ram_gb) AS ram_gb, SUM(disk_gb) AS disk_gb FROM `task`").
 		WillReturnRows(sqlmock.NewRows([]string{"count", "cpu_cores", "ram_gb", "disk_gb"}).AddRow(5, 10, 20, 30))
-	mock.ExpectQuery("SELECT `gpu_type`, SUM(`gpu_count`) AS `gpu_count` FROM `task` WHERE `gpu_type` IS NOT NULL AND `gpu_count` IS NOT NULL GROUP BY `gpu_type`").
+	mock.ExpectQuery("SELECT gpu_type, SUM(gpu_count) AS gpu_count FROM `task` WHERE gpu_type IS NOT NULL AND gpu_count IS NOT NULL GROUP BY `gpu_type`").
 		WillReturnRows(sqlmock.NewRows([]string{"gpu_type", "gpu_count"}))
 	resp, err := r.GatherResources(context.TODO(), nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -329,9 +458,9 @@ func TestGatherResourcesEmpty(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT COUNT(*) AS `count`, SUM(`cpu_cores`) AS `cpu_cores`, SUM(`ram_gb`) AS `ram_gb`, SUM(`disk_gb`) AS `disk_gb` FROM `task`").
+	mock.ExpectQuery("SELECT COUNT(*) AS count, SUM(cpu_cores) AS cpu_cores, SUM(ram_gb) AS ram_gb, SUM(disk_gb) AS disk_gb FROM `task`").
 		WillReturnRows(sqlmock.NewRows([]string{"count", "cpu_cores", "ram_gb", "disk_gb"}))
-	mock.ExpectQuery("SELECT `gpu_type`, SUM(`gpu_count`) AS `gpu_count` FROM `task` WHERE `gpu_type` IS NOT NULL AND `gpu_count` IS NOT NULL GROUP BY `gpu_type`").
+	mock.ExpectQuery("SELECT gpu_type, SUM(gpu_count) AS gpu_count FROM `task` WHERE gpu_type IS NOT NULL AND gpu_count IS NOT NULL GROUP BY `gpu_type`").
 		WillReturnRows(sqlmock.NewRows([]string{"gpu_type", "gpu_count"}))
 	resp, err := r.GatherResources(context.TODO(), nil)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
@@ -348,13 +477,13 @@ func TestGatherResourcesWithFilterOfCluster(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT COUNT(*) AS `count`, SUM(`cpu_cores`) AS `cpu_cores`, SUM(`ram_gb`) AS `ram_gb`, SUM(`disk_gb`) AS `disk_gb` FROM `task` "+
-		"WHERE `state` IN (?,?,?) AND `cluster_id` = ?").
+	mock.ExpectQuery("SELECT COUNT(*) AS count, SUM(cpu_cores) AS cpu_cores, SUM(ram_gb) AS ram_gb, SUM(disk_gb) AS disk_gb FROM `task` "+
+		"WHERE state IN (?,?,?) AND cluster_id = ?").
 		WithArgs(consts.TaskQueued, consts.TaskRunning, consts.TaskCanceling, "cluster-01").
 		WillReturnRows(sqlmock.NewRows([]string{"count", "cpu_cores", "ram_gb", "disk_gb"}).AddRow(5, 10, 20, 30))
-	mock.ExpectQuery("SELECT `gpu_type`, SUM(`gpu_count`) AS `gpu_count` FROM `task` "+
-		"WHERE `state` IN (?,?,?) AND `cluster_id` = ? "+
-		"AND (`gpu_type` IS NOT NULL AND `gpu_count` IS NOT NULL) "+
+	mock.ExpectQuery("SELECT gpu_type, SUM(gpu_count) AS gpu_count FROM `task` "+
+		"WHERE state IN (?,?,?) AND cluster_id = ? "+
+		"AND (gpu_type IS NOT NULL AND gpu_count IS NOT NULL) "+
 		"GROUP BY `gpu_type`").
 		WithArgs(consts.TaskQueued, consts.TaskRunning, consts.TaskCanceling, "cluster-01").
 		WillReturnRows(sqlmock.NewRows([]string{"gpu_type", "gpu_count"}).AddRow("gpu-01", 2).AddRow("gpu-02", 3))
@@ -379,13 +508,13 @@ func TestGatherResourcesWithFilterOfAccount(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &readModel{db: gormDB}
-	mock.ExpectQuery("SELECT COUNT(*) AS `count`, SUM(`cpu_cores`) AS `cpu_cores`, SUM(`ram_gb`) AS `ram_gb`, SUM(`disk_gb`) AS `disk_gb` FROM `task` "+
-		"WHERE `state` IN (?,?,?) AND `cluster_id` <> '' AND `account_id` = ? AND `user_id` = ?").
+	mock.ExpectQuery("SELECT COUNT(*) AS count, SUM(cpu_cores) AS cpu_cores, SUM(ram_gb) AS ram_gb, SUM(disk_gb) AS disk_gb FROM `task` "+
+		"WHERE state IN (?,?,?) AND cluster_id <> '' AND account_id = ? AND user_id = ?").
 		WithArgs(consts.TaskQueued, consts.TaskRunning, consts.TaskCanceling, "account-01", "user-01").
 		WillReturnRows(sqlmock.NewRows([]string{"count", "cpu_cores", "ram_gb", "disk_gb"}).AddRow(5, 10, 20, 30))
-	mock.ExpectQuery("SELECT `gpu_type`, SUM(`gpu_count`) AS `gpu_count` FROM `task` "+
-		"WHERE `state` IN (?,?,?) AND `cluster_id` <> '' AND `account_id` = ? AND `user_id` = ? "+
-		"AND (`gpu_type` IS NOT NULL AND `gpu_count` IS NOT NULL) "+
+	mock.ExpectQuery("SELECT gpu_type, SUM(gpu_count) AS gpu_count FROM `task` "+
+		"WHERE state IN (?,?,?) AND cluster_id <> '' AND account_id = ? AND user_id = ? "+
+		"AND (gpu_type IS NOT NULL AND gpu_count IS NOT NULL) "+
 		"GROUP BY `gpu_type`").
 		WithArgs(consts.TaskQueued, consts.TaskRunning, consts.TaskCanceling, "account-01", "user-01").
 		WillReturnRows(sqlmock.NewRows([]string{"gpu_type", "gpu_count"}).AddRow("gpu-01", 2).AddRow("gpu-02", 3))
@@ -425,3 +554,83 @@ func TestListAccounts(t *testing.T) {
 		{AccountID: "account-02", UserIDs: []string{"user-02"}},
 	}))
 }
+
+func TestListEvents(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `task_event` WHERE task_id = ? ORDER BY id").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(append([]string{"id"}, taskEventRows...)).
+			AddRow(1, id, now, "", consts.TaskQueued, "", "request-01").
+			AddRow(2, id, now, consts.TaskQueued, consts.TaskInitializing, "cluster-01", "request-02"))
+	resp, err := r.ListEvents(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal([]*query.TaskEvent{
+		{Time: now, PreviousState: "", State: consts.TaskQueued, ClusterID: "", RequestID: "request-01"},
+		{Time: now, PreviousState: consts.TaskQueued, State: consts.TaskInitializing, ClusterID: "cluster-01", RequestID: "request-02"},
+	}))
+}
+
+func TestListEventsAfter(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery("SELECT task_event.* FROM `task_event` JOIN task ON task.id = task_event.task_id "+
+		"WHERE (task_event.id > ? AND task_event.id <= ?) AND task_event.task_id IN (?,?) AND task.account_id = ? AND task.user_id = ? "+
+		"ORDER BY task_event.id LIMIT 10").
+		WithArgs(5, 8, "task-1111", "task-2222", "account-01", "user-01").
+		WillReturnRows(sqlmock.NewRows(append([]string{"id"}, taskEventRows...)).
+			AddRow(6, "task-1111", now, consts.TaskQueued, consts.TaskInitializing, "cluster-01", "request-01"))
+	resp, err := r.ListEventsAfter(context.TODO(), 5, 8, 10, &query.WatchFilter{
+		IDs:       []string{"task-1111", "task-2222"},
+		AccountID: "account-01",
+		UserID:    "user-01",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal([]*query.WatchEvent{{
+		ResourceVersion: 6,
+		TaskID:          "task-1111",
+		TaskEvent: query.TaskEvent{Time: now, PreviousState: consts.TaskQueued, State: consts.TaskInitializing,
+			ClusterID: "cluster-01", RequestID: "request-01"},
+	}}))
+}
+
+func TestGetSettledResourceVersion(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	settledBefore := now.Add(-time.Minute)
+	expectScan := func(rows *sqlmock.Rows) {
+		mock.ExpectQuery("SELECT `id`,`event_time` FROM `task_event` WHERE id > ? ORDER BY id LIMIT 10").
+			WithArgs(5).WillReturnRows(rows)
+	}
+
+	// 7 is not committed yet, so 8 is held back
+	expectScan(sqlmock.NewRows([]string{"id", "event_time"}).AddRow(6, now).AddRow(8, now).AddRow(9, now))
+	resp, err := r.GetSettledResourceVersion(context.TODO(), 5, 10, settledBefore)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(int64(6)))
+
+	// 7 is committed out of order
+	expectScan(sqlmock.NewRows([]string{"id", "event_time"}).AddRow(6, now).AddRow(7, now).AddRow(8, now).AddRow(9, now))
+	resp, err = r.GetSettledResourceVersion(context.TODO(), 5, 10, settledBefore)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(int64(9)))
+
+	// 7 is never committed, and 8 is older than the settle window
+	expectScan(sqlmock.NewRows([]string{"id", "event_time"}).AddRow(6, now.Add(-time.Hour)).AddRow(8, now.Add(-time.Hour)).AddRow(10, now))
+	resp, err = r.GetSettledResourceVersion(context.TODO(), 5, 10, settledBefore)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(int64(8)))
+}
+
+func TestGetLatestResourceVersion(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery("SELECT COALESCE(MAX(id), 0) FROM `task_event`").
+		WillReturnRows(sqlmock.NewRows([]string{"COALESCE(MAX(`id`), 0)"}).AddRow(42))
+	resp, err := r.GetLatestResourceVersion(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(int64(42)))
+}
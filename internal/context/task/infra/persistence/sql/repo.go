@@ -3,42 +3,165 @@ package sql
 import (
 	"context"
 	"errors"
+	"sort"
+	"time"
 
 	applog "github.com/GBA-BI/tes-api/pkg/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
 	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
 )
 
 type repo struct {
 	db *gorm.DB
+	// notify records notifications of finished tasks in the outbox, which is only consumed if webhooks are enabled
+	notify bool
 }
 
 // NewRepo ...
-func NewRepo(ctx context.Context, db *gorm.DB) (domain.Repo, error) {
-	if err := db.WithContext(ctx).AutoMigrate(&Task{}); err != nil {
-		return nil, err
+func NewRepo(ctx context.Context, db *gorm.DB, notify bool) (domain.Repo, error) {
+	return &repo{db: db, notify: notify}, nil
+}
+
+// firstTask finds the task by id into dest, tasks archived by retention are found in task_archive
+func firstTask(ctx context.Context, db *gorm.DB, id string, dest interface{}) error {
+	err := db.WithContext(ctx).Model(&Task{}).Where("id = ?", id).First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.WithContext(ctx).Model(&TaskArchive{}).Where("id = ?", id).First(dest).Error
 	}
-	return &repo{db: db}, nil
+	return err
 }
 
 var _ domain.Repo = (*repo)(nil)
 
+// errIdempotencyKeyHeld rolls back the creation when the idempotency key is held by another task
+var errIdempotencyKeyHeld = errors.New("idempotency key is held by another task")
+
 // Create ...
-func (r *repo) Create(ctx context.Context, task *domain.Task) error {
+func (r *repo) Create(ctx context.Context, task *domain.Task, idempotencyKey *domain.IdempotencyKey) (string, error) {
 	taskPO := taskDOToPO(task)
-	if err := r.db.WithContext(ctx).Model(&Task{}).Create(taskPO).Error; err != nil {
+	taskTagPOs := taskTagsToPO(task.ID, task.Tags)
+	taskEventPOs := taskEventsToPO(task.ID, utils.GetRequestID(ctx), task.Events)
+	taskIdempotencyKeyPO := taskIdempotencyKeyDOToPO(task.ID, idempotencyKey)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if taskIdempotencyKeyPO != nil {
+			if err := createIdempotencyKey(tx, taskIdempotencyKeyPO, task.CreationTime); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&Task{}).Create(taskPO).Error; err != nil {
+			return err
+		}
+		if len(taskTagPOs) > 0 {
+			if err := tx.Model(&TaskTag{}).Create(&taskTagPOs).Error; err != nil {
+				return err
+			}
+		}
+		if len(taskEventPOs) == 0 {
+			return nil
+		}
+		return tx.Model(&TaskEvent{}).Create(&taskEventPOs).Error
+	})
+	if errors.Is(err, errIdempotencyKeyHeld) {
+		// the key may be released between the failed insert and this query, retry to create with it
+		existID, err := r.GetIdempotentTaskID(ctx, idempotencyKey, task.CreationTime)
+		if err != nil || existID != "" {
+			return existID, err
+		}
+		return r.Create(ctx, task, idempotencyKey)
+	}
+	if err != nil {
 		applog.Errorw("failed to create task", "err", err)
+		return "", apperrors.NewInternalError(err)
+	}
+	return task.ID, nil
+}
+
+// CreateBatch inserts rows in batches of CreateBatchSize of the db
+func (r *repo) CreateBatch(ctx context.Context, tasks []*domain.Task) error {
+	taskPOs := make([]*Task, 0, len(tasks))
+	taskTagPOs := make([]*TaskTag, 0)
+	taskEventPOs := make([]*TaskEvent, 0, len(tasks))
+	for _, task := range tasks {
+		taskPOs = append(taskPOs, taskDOToPO(task))
+		taskTagPOs = append(taskTagPOs, taskTagsToPO(task.ID, task.Tags)...)
+		taskEventPOs = append(taskEventPOs, taskEventsToPO(task.ID, utils.GetRequestID(ctx), task.Events)...)
+	}
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Task{}).Create(&taskPOs).Error; err != nil {
+			return err
+		}
+		if len(taskTagPOs) > 0 {
+			if err := tx.Model(&TaskTag{}).Create(&taskTagPOs).Error; err != nil {
+				return err
+			}
+		}
+		if len(taskEventPOs) == 0 {
+			return nil
+		}
+		return tx.Model(&TaskEvent{}).Create(&taskEventPOs).Error
+	}); err != nil {
+		applog.Errorw("failed to create tasks", "err", err)
 		return apperrors.NewInternalError(err)
 	}
 	return nil
 }
 
+// createIdempotencyKey releases the key if it is expired at now, then takes it by inserting,
+// the primary key makes sure only one of concurrent creations takes it
+func createIdempotencyKey(tx *gorm.DB, taskIdempotencyKeyPO *TaskIdempotencyKey, now time.Time) error {
+	if err := tx.Where("account_id = ? AND user_id = ? AND idempotency_key = ?",
+		taskIdempotencyKeyPO.AccountID, taskIdempotencyKeyPO.UserID, taskIdempotencyKeyPO.IdempotencyKey).
+		Where("expire_time <= ?", now).Delete(&TaskIdempotencyKey{}).Error; err != nil {
+		return err
+	}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(taskIdempotencyKeyPO)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errIdempotencyKeyHeld
+	}
+	return nil
+}
+
+// GetIdempotentTaskID ...
+func (r *repo) GetIdempotentTaskID(ctx context.Context, idempotencyKey *domain.IdempotencyKey, now time.Time) (string, error) {
+	var taskIdempotencyKey TaskIdempotencyKey
+	if err := r.db.WithContext(ctx).Model(&TaskIdempotencyKey{}).
+		Where("account_id = ? AND user_id = ? AND idempotency_key = ?",
+			idempotencyKey.AccountID, idempotencyKey.UserID, idempotencyKey.Key).
+		Where("expire_time > ?", now).First(&taskIdempotencyKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		applog.Errorw("failed to get idempotent task id", "err", err)
+		return "", apperrors.NewInternalError(err)
+	}
+	return taskIdempotencyKey.TaskID, nil
+}
+
+// Get ...
+func (r *repo) Get(ctx context.Context, id string) (*domain.Task, error) {
+	var task Task
+	if err := firstTask(ctx, r.db, id, &task); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("task", id)
+		}
+		applog.Errorw("failed to get task", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	return task.toDO(), nil
+}
+
 // GetStatus ...
 func (r *repo) GetStatus(ctx context.Context, id string) (*domain.TaskStatus, error) {
 	var taskStatus TaskStatus
-	if err := r.db.WithContext(ctx).Model(&Task{}).Where("`id` = ?", id).First(&taskStatus).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&Task{}).Where("id = ?", id).First(&taskStatus).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewNotFoundError("task", id)
 		}
@@ -53,22 +176,350 @@ func (r *repo) UpdateStatus(ctx context.Context, taskStatus *domain.TaskStatus)
 	taskStatusPO := taskStatusDOToPO(taskStatus)
 	oldStatusResourceVersion := taskStatusPO.StatusResourceVersion
 	taskStatusPO.StatusResourceVersion = oldStatusResourceVersion + 1
-	res := r.db.WithContext(ctx).Model(&Task{}).Where("`id` = ?", taskStatusPO.ID).
-		Where("`status_resource_version` = ?", oldStatusResourceVersion).
-		Updates(taskStatusPO)
-	if err := res.Error; err != nil {
+	taskEventPOs := taskEventsToPO(taskStatus.ID, utils.GetRequestID(ctx), taskStatus.Events)
+	var taskNotificationPOs []*TaskNotification
+	if r.notify {
+		taskNotificationPOs = taskNotificationsToPO(taskStatus.ID, taskStatus.Events)
+	}
+	var updated bool
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Task{}).Where("id = ?", taskStatusPO.ID).
+			Where("status_resource_version = ?", oldStatusResourceVersion).
+			Updates(taskStatusPO)
+		if res.Error != nil {
+			return res.Error
+		}
+		// events and notifications are saved only if the status is updated
+		if updated = res.RowsAffected > 0; !updated || len(taskEventPOs) == 0 {
+			return nil
+		}
+		// nil finish_time of a requeued task is skipped by Updates, so it is cleared explicitly
+		if taskStatus.FinishTime == nil && reopened(taskStatus.Events) {
+			if err := tx.Model(&Task{}).Where("id = ?", taskStatusPO.ID).Update("finish_time", nil).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&TaskEvent{}).Create(&taskEventPOs).Error; err != nil {
+			return err
+		}
+		if len(taskNotificationPOs) == 0 {
+			return nil
+		}
+		return tx.Model(&TaskNotification{}).Create(&taskNotificationPOs).Error
+	}); err != nil {
 		applog.Errorw("failed to update taskStatus", "err", err)
 		return false, apperrors.NewInternalError(err)
 	}
-	return res.RowsAffected > 0, nil
+	if updated {
+		taskStatus.Events = nil
+	}
+	return updated, nil
+}
+
+func reopened(events []*domain.TaskEvent) bool {
+	for _, event := range events {
+		if event.Reopened() {
+			return true
+		}
+	}
+	return false
 }
 
 // CheckIDExist ...
 func (r *repo) CheckIDExist(ctx context.Context, id string) (bool, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&Task{}).Where("`id` = ?", id).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&Task{}).Where("id = ?", id).Count(&count).Error; err != nil {
 		applog.Errorw("failed to count tasks", "err", err)
 		return false, apperrors.NewInternalError(err)
 	}
+	if count > 0 {
+		return true, nil
+	}
+	// ids of archived tasks are not reused, so that they can still be got
+	if err := r.db.WithContext(ctx).Model(&TaskArchive{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		applog.Errorw("failed to count archived tasks", "err", err)
+		return false, apperrors.NewInternalError(err)
+	}
 	return count > 0, nil
 }
+
+// ListPriorities ...
+func (r *repo) ListPriorities(ctx context.Context, filter *domain.PriorityFilter) ([]*domain.TaskPriority, error) {
+	db := r.db.WithContext(ctx).Model(&Task{})
+	if filter.AccountID != "" {
+		db = db.Where("account_id = ?", filter.AccountID)
+	}
+	if filter.UserID != "" {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.SubmissionID != "" {
+		db = db.Where("submission_id = ?", filter.SubmissionID)
+	}
+	if filter.RunID != "" {
+		db = db.Where("run_id = ?", filter.RunID)
+	}
+	if len(filter.State) > 0 {
+		db = db.Where("state IN ?", filter.State)
+	}
+
+	taskPriorities := make([]*TaskPriority, 0)
+	if err := db.Find(&taskPriorities).Error; err != nil {
+		applog.Errorw("failed to list taskPriorities", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.TaskPriority, 0, len(taskPriorities))
+	for _, taskPriority := range taskPriorities {
+		res = append(res, taskPriority.toDO())
+	}
+	return res, nil
+}
+
+// UpdateEffectivePriority ...
+func (r *repo) UpdateEffectivePriority(ctx context.Context, ids []string, effectivePriority int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Model(&Task{}).Where("id IN ?", ids).
+		Update("effective_priority", effectivePriority).Error; err != nil {
+		applog.Errorw("failed to update effective priority", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// ListClaimCandidates ...
+func (r *repo) ListClaimCandidates(ctx context.Context, after *domain.Task, limit int) ([]*domain.Task, error) {
+	var cursor *PriorityCursor
+	if after != nil {
+		cursor = &PriorityCursor{ID: after.ID, EffectivePriority: after.EffectivePriority, CreationTime: after.CreationTime}
+	}
+	db := r.db.WithContext(ctx).Model(&Task{}).
+		Where("state = ?", consts.TaskQueued).
+		Where("cluster_id = ''")
+	db = orderByPriority(db, cursor).Limit(limit)
+
+	taskBasics := make([]*TaskBasic, 0)
+	if err := db.Find(&taskBasics).Error; err != nil {
+		applog.Errorw("failed to list claim candidates", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.Task, 0, len(taskBasics))
+	for _, taskBasic := range taskBasics {
+		res = append(res, taskBasic.toDO())
+	}
+	return res, nil
+}
+
+// ListStatusesByCluster ...
+func (r *repo) ListStatusesByCluster(ctx context.Context, clusterID string, states []string) ([]*domain.TaskStatus, error) {
+	taskStatuses := make([]*TaskStatus, 0)
+	if err := r.db.WithContext(ctx).Model(&Task{}).
+		Where("cluster_id = ?", clusterID).
+		Where("state IN ?", states).
+		Find(&taskStatuses).Error; err != nil {
+		applog.Errorw("failed to list taskStatuses by cluster", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.TaskStatus, 0, len(taskStatuses))
+	for _, taskStatus := range taskStatuses {
+		res = append(res, taskStatus.toDO())
+	}
+	return res, nil
+}
+
+// ListStatuses ...
+func (r *repo) ListStatuses(ctx context.Context, filter *domain.CancelFilter, afterID string, limit int) ([]*domain.TaskStatus, error) {
+	db := cancelFilterQuery(r.db.WithContext(ctx).Model(&Task{}), filter)
+	if afterID != "" {
+		db = db.Where("id > ?", afterID)
+	}
+
+	taskStatuses := make([]*TaskStatus, 0)
+	if err := db.Order("id").Limit(limit).Find(&taskStatuses).Error; err != nil {
+		applog.Errorw("failed to list taskStatuses", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.TaskStatus, 0, len(taskStatuses))
+	for _, taskStatus := range taskStatuses {
+		res = append(res, taskStatus.toDO())
+	}
+	return res, nil
+}
+
+// CountStatuses ...
+func (r *repo) CountStatuses(ctx context.Context, filter *domain.CancelFilter) (int64, error) {
+	var count int64
+	if err := cancelFilterQuery(r.db.WithContext(ctx).Model(&Task{}), filter).Count(&count).Error; err != nil {
+		applog.Errorw("failed to count taskStatuses", "err", err)
+		return 0, apperrors.NewInternalError(err)
+	}
+	return count, nil
+}
+
+// cancelFilterQuery selects tasks by the filter, tags are matched by subqueries
+func cancelFilterQuery(db *gorm.DB, filter *domain.CancelFilter) *gorm.DB {
+	if filter.AccountID != "" {
+		db = db.Where("account_id = ?", filter.AccountID)
+	}
+	if filter.UserID != "" {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.SubmissionID != "" {
+		db = db.Where("submission_id = ?", filter.SubmissionID)
+	}
+	if filter.RunID != "" {
+		db = db.Where("run_id = ?", filter.RunID)
+	}
+	if len(filter.State) > 0 {
+		db = db.Where("state IN ?", filter.State)
+	}
+	tagKeys := make([]string, 0, len(filter.Tags))
+	for key := range filter.Tags {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+	for _, key := range tagKeys {
+		tagDB := db.Session(&gorm.Session{NewDB: true}).Model(&TaskTag{}).Select("task_id").
+			Where("tag_key = ?", key)
+		if value := filter.Tags[key]; value != "" {
+			tagDB = tagDB.Where("tag_value = ?", value)
+		}
+		db = db.Where("id IN (?)", tagDB)
+	}
+	return db
+}
+
+// ListNotifications ...
+func (r *repo) ListNotifications(ctx context.Context, limit int) ([]*domain.Notification, error) {
+	var notifications []*TaskNotification
+	if err := r.db.WithContext(ctx).Model(&TaskNotification{}).Order("id").Limit(limit).
+		Find(&notifications).Error; err != nil {
+		applog.Errorw("failed to list task notifications", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		res = append(res, notification.toDO())
+	}
+	return res, nil
+}
+
+// DeleteNotifications ...
+func (r *repo) DeleteNotifications(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&TaskNotification{}).Error; err != nil {
+		applog.Errorw("failed to delete task notifications", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// IncreaseNotificationAttempts ...
+func (r *repo) IncreaseNotificationAttempts(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Model(&TaskNotification{}).Where("id IN ?", ids).
+		Update("attempts", gorm.Expr("attempts + ?", 1)).Error; err != nil {
+		applog.Errorw("failed to increase attempts of task notifications", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// ListFinishedIDs ...
+func (r *repo) ListFinishedIDs(ctx context.Context, filter *domain.RetentionFilter, limit int) ([]string, error) {
+	db := r.db.WithContext(ctx).Model(&Task{}).Where("finish_time < ?", filter.FinishedBefore)
+	if filter.AccountID != "" {
+		db = db.Where("account_id = ?", filter.AccountID)
+	}
+	if len(filter.ExcludedAccountIDs) > 0 {
+		db = db.Where("account_id NOT IN ?", filter.ExcludedAccountIDs)
+	}
+	ids := make([]string, 0)
+	if err := db.Limit(limit).Pluck("id", &ids).Error; err != nil {
+		applog.Errorw("failed to list finished task ids", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	return ids, nil
+}
+
+// stillFinishedBefore re-checks tasks listed by ListFinishedIDs in the transaction moving them out,
+// since a PREEMPTED task may be requeued meanwhile
+const stillFinishedBefore = "finish_time IS NOT NULL AND finish_time < ?"
+
+// ArchiveTasks copies the tasks into task_archive, then deletes them and their tags,
+// events are kept for the archived tasks. Tasks archived by others meanwhile are skipped.
+func (r *repo) ArchiveTasks(ctx context.Context, ids []string, finishedBefore time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tasks := make([]*TaskArchive, 0, len(ids))
+		if err := tx.Model(&Task{}).Where("id IN ?", ids).Where(stillFinishedBefore, finishedBefore).
+			Find(&tasks).Error; err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		archivedIDs := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			archivedIDs = append(archivedIDs, task.ID)
+		}
+		if err := tx.Model(&TaskArchive{}).Clauses(clause.OnConflict{DoNothing: true}).Create(&tasks).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", archivedIDs).Delete(&TaskTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", archivedIDs).Delete(&Task{}).Error
+	}); err != nil {
+		applog.Errorw("failed to archive tasks", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// PurgeTasks ...
+func (r *repo) PurgeTasks(ctx context.Context, ids []string, finishedBefore time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		purgedIDs := make([]string, 0, len(ids))
+		if err := tx.Model(&Task{}).Where("id IN ?", ids).Where(stillFinishedBefore, finishedBefore).
+			Pluck("id", &purgedIDs).Error; err != nil {
+			return err
+		}
+		if len(purgedIDs) == 0 {
+			return nil
+		}
+		if err := tx.Where("task_id IN ?", purgedIDs).Delete(&TaskTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", purgedIDs).Delete(&TaskEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", purgedIDs).Delete(&TaskIdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", purgedIDs).Delete(&Task{}).Error
+	}); err != nil {
+		applog.Errorw("failed to purge tasks", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys ...
+func (r *repo) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expire_time <= ?", now).Delete(&TaskIdempotencyKey{})
+	if res.Error != nil {
+		applog.Errorw("failed to delete expired idempotency keys", "err", res.Error)
+		return 0, apperrors.NewInternalError(res.Error)
+	}
+	return res.RowsAffected, nil
+}
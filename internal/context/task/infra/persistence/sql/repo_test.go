@@ -38,25 +38,33 @@ var taskPO = &Task{
 			}},
 			CreationTime:          now,
 			ClusterID:             utils.Point("cluster-01"),
+			QuotaHeld:             utils.Point(false),
+			MaxRetries:            3,
+			Retries:               1,
+			FinishTime:            &now,
 			StatusResourceVersion: 0,
 		},
 		Name:        "name",
 		Description: "description",
 		Resources: &Resources{
-			CPUCores: 1,
-			RamGB:    2,
-			DiskGB:   10,
-			GPUType:  utils.Point("gpu-01"),
-			GPUCount: utils.Point[float64](2),
+			CPUCores:          1,
+			RamGB:             2,
+			DiskGB:            10,
+			GPUType:           utils.Point("gpu-01"),
+			GPUCount:          utils.Point[float64](2),
+			Preemptible:       true,
+			Zones:             []string{"zone-a"},
+			BackendParameters: map[string]string{"VmSize": "Standard_D64_v3"},
 		},
 		Executors: []*Executor{{
-			Image:   "image:tag",
-			Command: []string{"command"},
-			Workdir: "/base/workdir",
-			Stdin:   "/base/stdin",
-			Stdout:  "/base/stdout",
-			Stderr:  "/base/stderr",
-			Env:     map[string]string{"abc": "def"},
+			Image:       "image:tag",
+			Command:     []string{"command"},
+			Workdir:     "/base/workdir",
+			Stdin:       "/base/stdin",
+			Stdout:      "/base/stdout",
+			Stderr:      "/base/stderr",
+			Env:         map[string]string{"abc": "def"},
+			IgnoreError: true,
 		}},
 		Volumes: []string{"volume"},
 		Tags:    map[string]string{"kkk": "vvv"},
@@ -79,7 +87,11 @@ var taskPO = &Task{
 				},
 			},
 		},
-		PriorityValue: 100,
+		PriorityValue:     100,
+		EffectivePriority: 120,
+		CallbackURL:       "https://example.com/callback",
+		RetryOf:           "task-0000",
+		Attempt:           2,
 	},
 	Inputs: []*Input{{
 		Name:        "filein",
@@ -114,6 +126,9 @@ var taskDO = &domain.Task{
 		}},
 		CreationTime:          now,
 		ClusterID:             "cluster-01",
+		MaxRetries:            3,
+		Retries:               1,
+		FinishTime:            &now,
 		StatusResourceVersion: 0,
 	},
 	Name:        "name",
@@ -141,15 +156,19 @@ var taskDO = &domain.Task{
 			Count: 2,
 			Type:  "gpu-01",
 		},
+		Preemptible:       true,
+		Zones:             []string{"zone-a"},
+		BackendParameters: map[string]string{"VmSize": "Standard_D64_v3"},
 	},
 	Executors: []*domain.Executor{{
-		Image:   "image:tag",
-		Command: []string{"command"},
-		Workdir: "/base/workdir",
-		Stdin:   "/base/stdin",
-		Stdout:  "/base/stdout",
-		Stderr:  "/base/stderr",
-		Env:     map[string]string{"abc": "def"},
+		Image:       "image:tag",
+		Command:     []string{"command"},
+		Workdir:     "/base/workdir",
+		Stdin:       "/base/stdin",
+		Stdout:      "/base/stdout",
+		Stderr:      "/base/stderr",
+		Env:         map[string]string{"abc": "def"},
+		IgnoreError: true,
 	}},
 	Volumes: []string{"volume"},
 	Tags:    map[string]string{"kkk": "vvv"},
@@ -172,47 +191,157 @@ var taskDO = &domain.Task{
 			},
 		},
 	},
-	PriorityValue: 100,
+	PriorityValue:     100,
+	EffectivePriority: 120,
+	CallbackURL:       "https://example.com/callback",
+	RetryOf:           "task-0000",
+	Attempt:           2,
 }
 
 var taskStateRows = []string{"id", "state"}
-var taskStatusRows = append(taskStateRows, []string{"logs", "creation_time", "cluster_id", "status_resource_version"}...)
+var taskStatusRows = append(taskStateRows, []string{"logs", "creation_time", "cluster_id", "quota_held", "max_retries", "retries", "finish_time", "status_resource_version"}...)
 var taskBasicRow = append(taskStatusRows, []string{"name", "description",
-	"cpu_cores", "ram_gb", "disk_gb", "boot_disk_gb", "gpu_count", "gpu_type", "executors", "volumes", "tags",
-	"account_id", "user_id", "submission_id", "run_id", `meta`, "priority_value"}...)
+	"cpu_cores", "ram_gb", "disk_gb", "boot_disk_gb", "gpu_count", "gpu_type",
+	"preemptible", "zones", "backend_parameters", "backend_parameters_strict", "executors", "volumes", "tags",
+	"account_id", "user_id", "submission_id", "run_id", `meta`, "priority_value", "effective_priority", "callback_url",
+	"retry_of", "attempt"}...)
 var taskRows = append(taskBasicRow, []string{"inputs", "outputs"}...)
+var taskTagRows = []string{"task_id", "tag_key", "tag_value"}
 
-func TestCreate(t *testing.T) {
-	g := gomega.NewWithT(t)
-	mock, gormDB := testutil.NewSqlMock()
-	r := &repo{db: gormDB}
-	mock.ExpectBegin()
+func expectCreateTask(mock sqlmock.Sqlmock) {
 	mock.ExpectExec(fmt.Sprintf("INSERT INTO `task` %s", testutil.GenInsertSql(taskRows))).
 		WithArgs(taskPO.ID, taskPO.State,
-			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
 			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
 			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
-			taskPO.Resources.GPUCount, taskPO.Resources.GPUType,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
 			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
 			testutil.MustJSONMarshal(taskPO.Tags),
 			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
 			taskPO.BioosInfo.RunID,
-			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt,
 			testutil.MustJSONMarshal(taskPO.Inputs), testutil.MustJSONMarshal(taskPO.Outputs)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO `task_tag` %s", testutil.GenInsertSql(taskTagRows))).
+		WithArgs(taskPO.ID, "kkk", "vvv").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestCreate(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	expectCreateTask(mock)
+	mock.ExpectCommit()
+	resp, err := r.Create(context.TODO(), taskDO, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(id))
+}
+
+func TestCreateBatch(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	expectCreateTask(mock)
+	mock.ExpectCommit()
+	err := r.CreateBatch(context.TODO(), []*domain.Task{taskDO})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+var taskIdempotencyKeyRows = []string{"account_id", "user_id", "idempotency_key", "task_id", "expire_time"}
+
+var idempotencyKey = &domain.IdempotencyKey{
+	AccountID:  "account-01",
+	UserID:     "user-01",
+	Key:        "key-01",
+	ExpireTime: now.Add(time.Hour),
+}
+
+func expectCreateIdempotencyKey(mock sqlmock.Sqlmock, affected int64) {
+	mock.ExpectExec("DELETE FROM `task_idempotency_key` WHERE (account_id = ? AND user_id = ? AND idempotency_key = ?) AND expire_time <= ?").
+		WithArgs(idempotencyKey.AccountID, idempotencyKey.UserID, idempotencyKey.Key, taskPO.CreationTime).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO `task_idempotency_key` %s %s", testutil.GenInsertSql(taskIdempotencyKeyRows), testutil.GenDoNothingSql("account_id"))).
+		WithArgs(idempotencyKey.AccountID, idempotencyKey.UserID, idempotencyKey.Key, id, idempotencyKey.ExpireTime).
+		WillReturnResult(sqlmock.NewResult(0, affected))
+}
+
+func TestCreateWithIdempotencyKey(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	expectCreateIdempotencyKey(mock, 1)
+	expectCreateTask(mock)
 	mock.ExpectCommit()
-	err := r.Create(context.TODO(), taskDO)
+	resp, err := r.Create(context.TODO(), taskDO, idempotencyKey)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal(id))
+}
+
+func TestCreateIdempotencyKeyHeld(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	expectCreateIdempotencyKey(mock, 0)
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT * FROM `task_idempotency_key` WHERE (account_id = ? AND user_id = ? AND idempotency_key = ?) AND expire_time > ? ORDER BY `task_idempotency_key`.`account_id` LIMIT 1").
+		WithArgs(idempotencyKey.AccountID, idempotencyKey.UserID, idempotencyKey.Key, taskPO.CreationTime).
+		WillReturnRows(sqlmock.NewRows(taskIdempotencyKeyRows).
+			AddRow(idempotencyKey.AccountID, idempotencyKey.UserID, idempotencyKey.Key, "task-2222", idempotencyKey.ExpireTime))
+	resp, err := r.Create(context.TODO(), taskDO, idempotencyKey)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal("task-2222"))
+}
+
+func TestGetIdempotentTaskIDNotFound(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `task_idempotency_key` WHERE (account_id = ? AND user_id = ? AND idempotency_key = ?) AND expire_time > ? ORDER BY `task_idempotency_key`.`account_id` LIMIT 1").
+		WithArgs(idempotencyKey.AccountID, idempotencyKey.UserID, idempotencyKey.Key, now).
+		WillReturnRows(sqlmock.NewRows(taskIdempotencyKeyRows))
+	resp, err := r.GetIdempotentTaskID(context.TODO(), idempotencyKey, now)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEmpty())
+}
+
+func TestGet(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(taskRows).AddRow(taskPO.ID, taskPO.State,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
+			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
+			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
+			testutil.MustJSONMarshal(taskPO.Tags),
+			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
+			taskPO.BioosInfo.RunID,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt,
+			testutil.MustJSONMarshal(taskPO.Inputs), testutil.MustJSONMarshal(taskPO.Outputs)))
+	resp, err := r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo(taskDO))
 }
 
 func TestGetStatus(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskStatusRows))).
 		WithArgs(id).WillReturnRows(sqlmock.NewRows(taskStatusRows).AddRow(taskPO.ID, taskPO.State,
-		testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.StatusResourceVersion))
+		testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime, taskPO.StatusResourceVersion))
 	resp, err := r.GetStatus(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(resp).To(gomega.BeEquivalentTo(&taskDO.TaskStatus))
@@ -222,7 +351,7 @@ func TestGetStatusNotFound(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE `id` = ? ORDER BY `task`.`id` LIMIT 1",
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id = ? ORDER BY `task`.`id` LIMIT 1",
 		testutil.GenSelectFieldsSql("task", taskStatusRows))).
 		WithArgs(id).WillReturnRows(sqlmock.NewRows(taskStatusRows))
 	_, err := r.GetStatus(context.TODO(), id)
@@ -234,8 +363,8 @@ func TestUpdateStatus(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE `id` = ? AND `status_resource_version` = ?", testutil.GenUpdateSql(taskStatusRows))).
-		WithArgs(taskPO.ID, taskPO.State, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID,
+	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE id = ? AND status_resource_version = ?", testutil.GenUpdateSql(taskStatusRows))).
+		WithArgs(taskPO.ID, taskPO.State, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
 			taskPO.StatusResourceVersion+1, id, taskPO.StatusResourceVersion).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	updated, err := r.UpdateStatus(context.TODO(), &taskDO.TaskStatus)
@@ -248,8 +377,8 @@ func TestUpdateStatusConflict(t *testing.T) {
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
 	mock.ExpectBegin()
-	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE `id` = ? AND `status_resource_version` = ?", testutil.GenUpdateSql(taskStatusRows))).
-		WithArgs(taskPO.ID, taskPO.State, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID,
+	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE id = ? AND status_resource_version = ?", testutil.GenUpdateSql(taskStatusRows))).
+		WithArgs(taskPO.ID, taskPO.State, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
 			taskPO.StatusResourceVersion+1, id, taskPO.StatusResourceVersion).WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectCommit()
 	updated, err := r.UpdateStatus(context.TODO(), &taskDO.TaskStatus)
@@ -257,13 +386,385 @@ func TestUpdateStatusConflict(t *testing.T) {
 	g.Expect(updated).To(gomega.BeFalse())
 }
 
+var taskEventRows = []string{"task_id", "event_time", "previous_state", "state", "cluster_id", "request_id"}
+
+func TestUpdateStatusWithEvents(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	taskStatus := taskDO.TaskStatus
+	taskStatus.Events = []*domain.TaskEvent{{
+		Time:          now,
+		PreviousState: consts.TaskInitializing,
+		State:         taskPO.State,
+		ClusterID:     *taskPO.ClusterID,
+	}}
+	mock.ExpectBegin()
+	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE id = ? AND status_resource_version = ?", testutil.GenUpdateSql(taskStatusRows))).
+		WithArgs(taskPO.ID, taskPO.State, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion+1, id, taskPO.StatusResourceVersion).WillReturnResult(sqlmock.NewResult(1, 1))
+	testutil.ExpectInsertWithID(mock, fmt.Sprintf("INSERT INTO `task_event` %s", testutil.GenInsertSql(taskEventRows)), 1,
+		taskPO.ID, now, consts.TaskInitializing, taskPO.State, *taskPO.ClusterID, "request-01")
+	mock.ExpectCommit()
+	updated, err := r.UpdateStatus(utils.WithRequestID(context.TODO(), "request-01"), &taskStatus)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeTrue())
+	g.Expect(taskStatus.Events).To(gomega.BeEmpty())
+}
+
+func TestUpdateStatusRequeuePreempted(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	taskStatus := taskDO.TaskStatus
+	taskStatus.State = consts.TaskQueued
+	taskStatus.ClusterID = ""
+	taskStatus.FinishTime = nil
+	taskStatus.Events = []*domain.TaskEvent{{
+		Time:          now,
+		PreviousState: consts.TaskPreempted,
+		State:         consts.TaskQueued,
+		ClusterID:     *taskPO.ClusterID,
+	}}
+	rows := []string{"id", "state", "logs", "creation_time", "cluster_id", "quota_held", "max_retries", "retries", "status_resource_version"}
+	mock.ExpectBegin()
+	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE id = ? AND status_resource_version = ?", testutil.GenUpdateSql(rows))).
+		WithArgs(taskPO.ID, consts.TaskQueued, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, "", taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries,
+			taskPO.StatusResourceVersion+1, id, taskPO.StatusResourceVersion).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `task` SET `finish_time`=? WHERE id = ?").
+		WithArgs(nil, taskPO.ID).WillReturnResult(sqlmock.NewResult(1, 1))
+	testutil.ExpectInsertWithID(mock, fmt.Sprintf("INSERT INTO `task_event` %s", testutil.GenInsertSql(taskEventRows)), 1,
+		taskPO.ID, now, consts.TaskPreempted, consts.TaskQueued, *taskPO.ClusterID, "request-01")
+	mock.ExpectCommit()
+	updated, err := r.UpdateStatus(utils.WithRequestID(context.TODO(), "request-01"), &taskStatus)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeTrue())
+}
+
+func TestUpdateStatusWithNotifications(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB, notify: true}
+	taskStatus := taskDO.TaskStatus
+	taskStatus.State = consts.TaskComplete
+	taskStatus.Events = []*domain.TaskEvent{{
+		Time:          now,
+		PreviousState: taskPO.State,
+		State:         consts.TaskComplete,
+		ClusterID:     *taskPO.ClusterID,
+	}}
+	mock.ExpectBegin()
+	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE id = ? AND status_resource_version = ?", testutil.GenUpdateSql(taskStatusRows))).
+		WithArgs(taskPO.ID, consts.TaskComplete, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion+1, id, taskPO.StatusResourceVersion).WillReturnResult(sqlmock.NewResult(1, 1))
+	testutil.ExpectInsertWithID(mock, fmt.Sprintf("INSERT INTO `task_event` %s", testutil.GenInsertSql(taskEventRows)), 1,
+		taskPO.ID, now, taskPO.State, consts.TaskComplete, *taskPO.ClusterID, "")
+	testutil.ExpectInsertWithID(mock, fmt.Sprintf("INSERT INTO `task_notification` %s", testutil.GenInsertSql(taskNotificationRows[1:] /*without id*/)), 1,
+		taskPO.ID, consts.TaskComplete, now, 0)
+	mock.ExpectCommit()
+	updated, err := r.UpdateStatus(context.TODO(), &taskStatus)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeTrue())
+}
+
+func TestUpdateStatusWithoutNotify(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	taskStatus := taskDO.TaskStatus
+	taskStatus.State = consts.TaskComplete
+	taskStatus.Events = []*domain.TaskEvent{{
+		Time:          now,
+		PreviousState: taskPO.State,
+		State:         consts.TaskComplete,
+		ClusterID:     *taskPO.ClusterID,
+	}}
+	mock.ExpectBegin()
+	mock.ExpectExec(fmt.Sprintf("UPDATE `task` SET %s WHERE id = ? AND status_resource_version = ?", testutil.GenUpdateSql(taskStatusRows))).
+		WithArgs(taskPO.ID, consts.TaskComplete, testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion+1, id, taskPO.StatusResourceVersion).WillReturnResult(sqlmock.NewResult(1, 1))
+	// no notification is recorded if webhooks are disabled
+	testutil.ExpectInsertWithID(mock, fmt.Sprintf("INSERT INTO `task_event` %s", testutil.GenInsertSql(taskEventRows)), 1,
+		taskPO.ID, now, taskPO.State, consts.TaskComplete, *taskPO.ClusterID, "")
+	mock.ExpectCommit()
+	updated, err := r.UpdateStatus(context.TODO(), &taskStatus)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeTrue())
+}
+
+var taskNotificationRows = []string{"id", "task_id", "state", "event_time", "attempts"}
+
+func TestListNotifications(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `task_notification` ORDER BY id LIMIT 10").
+		WillReturnRows(sqlmock.NewRows(taskNotificationRows).AddRow(1, id, consts.TaskComplete, now, 2))
+	resp, err := r.ListNotifications(context.TODO(), 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.Notification{{
+		ID:       1,
+		TaskID:   id,
+		State:    consts.TaskComplete,
+		Time:     now,
+		Attempts: 2,
+	}}))
+}
+
+func TestDeleteNotifications(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `task_notification` WHERE id IN (?,?)").WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	err := r.DeleteNotifications(context.TODO(), []int64{1, 2})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestIncreaseNotificationAttempts(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `task_notification` SET `attempts`=attempts + ? WHERE id IN (?,?)").WithArgs(1, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	err := r.IncreaseNotificationAttempts(context.TODO(), []int64{1, 2})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
 func TestCheckIDExist(t *testing.T) {
 	g := gomega.NewWithT(t)
 	mock, gormDB := testutil.NewSqlMock()
 	r := &repo{db: gormDB}
-	mock.ExpectQuery("SELECT count(*) FROM `task` WHERE `id` = ?").WithArgs(id).
+	mock.ExpectQuery("SELECT count(*) FROM `task` WHERE id = ?").WithArgs(id).
+		WillReturnRows(testutil.NewCountRows(0))
+	mock.ExpectQuery("SELECT count(*) FROM `task_archive` WHERE id = ?").WithArgs(id).
 		WillReturnRows(testutil.NewCountRows(0))
 	exist, err := r.CheckIDExist(context.TODO(), id)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(exist).To(gomega.BeFalse())
 }
+
+var taskPriorityRows = []string{"id", "account_id", "user_id", "submission_id", "run_id", "priority_value", "effective_priority"}
+
+func TestListPriorities(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE account_id = ? AND user_id = ? AND state IN (?,?)",
+		testutil.GenSelectFieldsSql("task", taskPriorityRows))).
+		WithArgs("account-01", "user-01", consts.TaskQueued, consts.TaskRunning).
+		WillReturnRows(sqlmock.NewRows(taskPriorityRows).AddRow(taskPO.ID, taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID,
+			taskPO.BioosInfo.SubmissionID, taskPO.BioosInfo.RunID, taskPO.PriorityValue, taskPO.EffectivePriority))
+	resp, err := r.ListPriorities(context.TODO(), &domain.PriorityFilter{
+		AccountID: "account-01",
+		UserID:    "user-01",
+		State:     []string{consts.TaskQueued, consts.TaskRunning},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.TaskPriority{{
+		ID: id,
+		BioosInfo: &domain.BioosInfo{
+			AccountID:    "account-01",
+			UserID:       "user-01",
+			SubmissionID: "submission-01",
+			RunID:        "run-01",
+		},
+		PriorityValue:     100,
+		EffectivePriority: 120,
+	}}))
+}
+
+func TestUpdateEffectivePriority(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `task` SET `effective_priority`=? WHERE id IN (?,?)").
+		WithArgs(130, id, "task-2222").WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+	err := r.UpdateEffectivePriority(context.TODO(), []string{id, "task-2222"}, 130)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestListClaimCandidates(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE state = ? AND cluster_id = '' AND "+
+		"(effective_priority < ? OR (effective_priority = ? AND (creation_time > ? OR (creation_time = ? AND id > ?)))) "+
+		"ORDER BY effective_priority DESC,creation_time,id LIMIT 10",
+		testutil.GenSelectFieldsSql("task", taskBasicRow))).
+		WithArgs(consts.TaskQueued, 200, 200, now, now, "task-1111").
+		WillReturnRows(sqlmock.NewRows(taskBasicRow).AddRow(taskPO.ID, taskPO.State,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
+			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
+			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
+			testutil.MustJSONMarshal(taskPO.Tags),
+			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
+			taskPO.BioosInfo.RunID,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt))
+	resp, err := r.ListClaimCandidates(context.TODO(), &domain.Task{
+		TaskStatus:        domain.TaskStatus{ID: "task-1111", CreationTime: now},
+		EffectivePriority: 200,
+	}, 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	expTask := *taskDO
+	expTask.Inputs = nil
+	expTask.Outputs = nil
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.Task{&expTask}))
+}
+
+func TestListStatusesByCluster(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	states := []string{consts.TaskQueued, consts.TaskRunning}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE cluster_id = ? AND state IN (?,?)",
+		testutil.GenSelectFieldsSql("task", taskStatusRows))).
+		WithArgs(*taskPO.ClusterID, consts.TaskQueued, consts.TaskRunning).
+		WillReturnRows(sqlmock.NewRows(taskStatusRows).AddRow(taskPO.ID, taskPO.State,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime, taskPO.StatusResourceVersion))
+	resp, err := r.ListStatusesByCluster(context.TODO(), *taskPO.ClusterID, states)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.TaskStatus{&taskDO.TaskStatus}))
+}
+
+func TestListStatuses(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE account_id = ? AND submission_id = ? AND state IN (?) "+
+		"AND id IN (SELECT `task_id` FROM `task_tag` WHERE tag_key = ? AND tag_value = ?) AND id > ? ORDER BY id LIMIT 10",
+		testutil.GenSelectFieldsSql("task", taskStatusRows))).
+		WithArgs("account-01", "submission-01", consts.TaskRunning, "key", "value", "task-0000").
+		WillReturnRows(sqlmock.NewRows(taskStatusRows).AddRow(taskPO.ID, taskPO.State,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime, taskPO.StatusResourceVersion))
+	resp, err := r.ListStatuses(context.TODO(), &domain.CancelFilter{
+		AccountID:    "account-01",
+		SubmissionID: "submission-01",
+		Tags:         map[string]string{"key": "value"},
+		State:        []string{consts.TaskRunning},
+	}, "task-0000", 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.TaskStatus{&taskDO.TaskStatus}))
+}
+
+func TestCountStatuses(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT count(*) FROM `task` WHERE run_id = ? AND state IN (?,?) "+
+		"AND id IN (SELECT `task_id` FROM `task_tag` WHERE tag_key = ?)").
+		WithArgs("run-01", consts.TaskComplete, consts.TaskCanceled, "key").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	count, err := r.CountStatuses(context.TODO(), &domain.CancelFilter{
+		RunID: "run-01",
+		Tags:  map[string]string{"key": ""},
+		State: []string{consts.TaskComplete, consts.TaskCanceled},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(count).To(gomega.Equal(int64(3)))
+}
+
+func TestListFinishedIDs(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT `id` FROM `task` WHERE finish_time < ? AND account_id NOT IN (?,?) LIMIT 10").
+		WithArgs(now, "account-01", "account-02").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	resp, err := r.ListFinishedIDs(context.TODO(), &domain.RetentionFilter{
+		ExcludedAccountIDs: []string{"account-01", "account-02"},
+		FinishedBefore:     now,
+	}, 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.Equal([]string{id}))
+}
+
+func TestArchiveTasks(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectQuery(fmt.Sprintf("SELECT %s FROM `task` WHERE id IN (?) AND (finish_time IS NOT NULL AND finish_time < ?)",
+		testutil.GenSelectFieldsSql("task", taskRows))).
+		WithArgs(id, now).
+		WillReturnRows(sqlmock.NewRows(taskRows).AddRow(taskPO.ID, taskPO.State,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
+			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
+			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
+			testutil.MustJSONMarshal(taskPO.Tags),
+			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
+			taskPO.BioosInfo.RunID,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt,
+			testutil.MustJSONMarshal(taskPO.Inputs), testutil.MustJSONMarshal(taskPO.Outputs)))
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO `task_archive` %s %s", testutil.GenInsertSql(taskRows), testutil.GenDoNothingSql("id"))).
+		WithArgs(taskPO.ID, taskPO.State,
+			testutil.MustJSONMarshal(taskPO.Logs), taskPO.CreationTime, taskPO.ClusterID, taskPO.QuotaHeld, taskPO.MaxRetries, taskPO.Retries, taskPO.FinishTime,
+			taskPO.StatusResourceVersion, taskPO.Name, taskPO.Description,
+			taskPO.Resources.CPUCores, taskPO.Resources.RamGB, taskPO.Resources.DiskGB, taskPO.Resources.BootDiskGB,
+			taskPO.Resources.GPUCount, taskPO.Resources.GPUType, taskPO.Resources.Preemptible,
+			testutil.MustJSONMarshal(taskPO.Resources.Zones), testutil.MustJSONMarshal(taskPO.Resources.BackendParameters),
+			taskPO.Resources.BackendParametersStrict,
+			testutil.MustJSONMarshal(taskPO.Executors), testutil.MustJSONMarshal(taskPO.Volumes),
+			testutil.MustJSONMarshal(taskPO.Tags),
+			taskPO.BioosInfo.AccountID, taskPO.BioosInfo.UserID, taskPO.BioosInfo.SubmissionID,
+			taskPO.BioosInfo.RunID,
+			testutil.MustJSONMarshal(taskPO.BioosInfo.Meta), taskPO.PriorityValue, taskPO.EffectivePriority, taskPO.CallbackURL, taskPO.RetryOf, taskPO.Attempt,
+			testutil.MustJSONMarshal(taskPO.Inputs), testutil.MustJSONMarshal(taskPO.Outputs)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM `task_tag` WHERE task_id IN (?)").WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `task` WHERE id IN (?)").WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := r.ArchiveTasks(context.TODO(), []string{id}, now)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestPurgeTasks(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `task` WHERE id IN (?,?) AND (finish_time IS NOT NULL AND finish_time < ?)").
+		WithArgs(id, "task-requeued", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	mock.ExpectExec("DELETE FROM `task_tag` WHERE task_id IN (?)").WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `task_event` WHERE task_id IN (?)").WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `task_idempotency_key` WHERE task_id IN (?)").WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `task` WHERE id IN (?)").WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := r.PurgeTasks(context.TODO(), []string{id, "task-requeued"}, now)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestDeleteExpiredIdempotencyKeys(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `task_idempotency_key` WHERE expire_time <= ?").WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+	count, err := r.DeleteExpiredIdempotencyKeys(context.TODO(), now)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(count).To(gomega.BeEquivalentTo(3))
+}
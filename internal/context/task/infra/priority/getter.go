@@ -0,0 +1,40 @@
+package priority
+
+import (
+	"context"
+
+	extraprioritydomain "github.com/GBA-BI/tes-api/internal/context/extrapriority/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+// getter ...
+type getter struct {
+	extraPriorityRepo extraprioritydomain.Repo
+}
+
+// NewExtraPriorityGetter ...
+func NewExtraPriorityGetter(extraPriorityRepo extraprioritydomain.Repo) domain.ExtraPriorityGetter {
+	return &getter{extraPriorityRepo: extraPriorityRepo}
+}
+
+var _ domain.ExtraPriorityGetter = (*getter)(nil)
+
+// GetExtraPriorityValue sums extra priorities of the account, account/user, submission and run
+func (g *getter) GetExtraPriorityValue(ctx context.Context, bioosInfo *domain.BioosInfo) (int, error) {
+	if bioosInfo == nil {
+		return 0, nil
+	}
+	var res int
+	for _, id := range extraprioritydomain.MatchedExtraPriorityIDs(bioosInfo.AccountID, bioosInfo.UserID, bioosInfo.SubmissionID, bioosInfo.RunID) {
+		extraPriority, err := g.extraPriorityRepo.Get(ctx, id)
+		if err != nil {
+			if apperrors.IsCode(err, apperrors.NotFoundCode) {
+				continue
+			}
+			return 0, err
+		}
+		res += extraPriority.ExtraPriorityValue
+	}
+	return res, nil
+}
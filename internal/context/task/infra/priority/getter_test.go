@@ -0,0 +1,50 @@
+package priority
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	extraprioritydomain "github.com/GBA-BI/tes-api/internal/context/extrapriority/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestGetExtraPriorityValue(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := extraprioritydomain.NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().Get(gomock.Any(), "account-01///").
+		Return(&extraprioritydomain.ExtraPriority{ID: "account-01///", AccountID: "account-01", ExtraPriorityValue: 10}, nil)
+	fakeRepo.EXPECT().Get(gomock.Any(), "account-01/user-01//").
+		Return(nil, apperrors.NewNotFoundError("extra priority", "account-01/user-01//"))
+	fakeRepo.EXPECT().Get(gomock.Any(), "//submission-01/").
+		Return(&extraprioritydomain.ExtraPriority{ID: "//submission-01/", SubmissionID: "submission-01", ExtraPriorityValue: -5}, nil)
+	fakeRepo.EXPECT().Get(gomock.Any(), "///run-01").
+		Return(&extraprioritydomain.ExtraPriority{ID: "///run-01", RunID: "run-01", ExtraPriorityValue: 100}, nil)
+
+	getter := NewExtraPriorityGetter(fakeRepo)
+	value, err := getter.GetExtraPriorityValue(context.TODO(), &domain.BioosInfo{
+		AccountID:    "account-01",
+		UserID:       "user-01",
+		SubmissionID: "submission-01",
+		RunID:        "run-01",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(value).To(gomega.Equal(105))
+}
+
+func TestGetExtraPriorityValueWithoutBioosInfo(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	getter := NewExtraPriorityGetter(extraprioritydomain.NewFakeRepo(ctrl))
+	value, err := getter.GetExtraPriorityValue(context.TODO(), nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(value).To(gomega.Equal(0))
+}
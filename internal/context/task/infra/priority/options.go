@@ -0,0 +1,36 @@
+package priority
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Options ...
+type Options struct {
+	Enable   bool          `mapstructure:"enable"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Enable:   true,
+		Interval: 5 * time.Minute,
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if o.Interval <= 0 {
+		return fmt.Errorf("priority interval should be positive")
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enable, "priority-enable", o.Enable, "enable refreshing effective priorities of all executing tasks periodically")
+	fs.DurationVar(&o.Interval, "priority-interval", o.Interval, "interval of refreshing effective priorities")
+}
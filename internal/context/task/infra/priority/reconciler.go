@@ -0,0 +1,40 @@
+package priority
+
+import (
+	"context"
+	"time"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+)
+
+// Reconciler refreshes effective priorities of all executing tasks periodically,
+// which makes up refreshes failed after extra priorities are changed
+type Reconciler struct {
+	opts *Options
+	svc  domain.Service
+}
+
+// NewReconciler ...
+func NewReconciler(opts *Options, svc domain.Service) *Reconciler {
+	return &Reconciler{opts: opts, svc: svc}
+}
+
+// Run blocks until ctx is done
+func (r *Reconciler) Run(ctx context.Context) {
+	if !r.opts.Enable {
+		return
+	}
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.svc.RefreshPriority(ctx, "", "", "", ""); err != nil {
+				applog.Errorw("failed to refresh priorities", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package priority
+
+import (
+	"context"
+
+	extraprioritydomain "github.com/GBA-BI/tes-api/internal/context/extrapriority/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+)
+
+// refresher ...
+type refresher struct {
+	svc domain.Service
+}
+
+// NewPriorityRefresher ...
+func NewPriorityRefresher(svc domain.Service) extraprioritydomain.PriorityRefresher {
+	return &refresher{svc: svc}
+}
+
+var _ extraprioritydomain.PriorityRefresher = (*refresher)(nil)
+
+// Refresh ...
+func (r *refresher) Refresh(ctx context.Context, accountID, userID, submissionID, runID string) error {
+	return r.svc.RefreshPriority(ctx, accountID, userID, submissionID, runID)
+}
@@ -0,0 +1,26 @@
+package reclaim
+
+import (
+	"context"
+
+	clusterdomain "github.com/GBA-BI/tes-api/internal/context/cluster/domain"
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+)
+
+// reclaimer ...
+type reclaimer struct {
+	svc            domain.Service
+	requeueRunning bool
+}
+
+// NewTaskReclaimer ...
+func NewTaskReclaimer(svc domain.Service, requeueRunning bool) clusterdomain.TaskReclaimer {
+	return &reclaimer{svc: svc, requeueRunning: requeueRunning}
+}
+
+var _ clusterdomain.TaskReclaimer = (*reclaimer)(nil)
+
+// Reclaim ...
+func (r *reclaimer) Reclaim(ctx context.Context, clusterID string) error {
+	return r.svc.Reclaim(ctx, clusterID, r.requeueRunning)
+}
@@ -0,0 +1,82 @@
+package retention
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+)
+
+const (
+	// ActionArchive moves expired tasks into the archive table, where GetTask can still find them
+	ActionArchive = "archive"
+	// ActionPurge deletes expired tasks together with their events
+	ActionPurge = "purge"
+)
+
+// Options ...
+type Options struct {
+	Enable   bool          `mapstructure:"enable"`
+	Interval time.Duration `mapstructure:"interval"`
+	// Age is how long tasks are kept after they are finished, zero keeps them forever,
+	// delivered webhook deliveries are kept as long after they are delivered
+	Age time.Duration `mapstructure:"age"`
+	// AccountAges overrides Age for tasks of the accounts
+	AccountAges map[string]time.Duration `mapstructure:"accountAges"`
+	Action      string                   `mapstructure:"action"`
+	BatchSize   int                      `mapstructure:"batchSize"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Enable:    false,
+		Interval:  time.Hour,
+		Age:       90 * 24 * time.Hour,
+		Action:    ActionArchive,
+		BatchSize: 500,
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if o.Interval <= 0 {
+		return fmt.Errorf("retention interval should be positive")
+	}
+	if o.Age < 0 {
+		return fmt.Errorf("retention age should not be negative")
+	}
+	for accountID, age := range o.AccountAges {
+		if age < 0 {
+			return fmt.Errorf("retention accountAges of %s should not be negative", accountID)
+		}
+	}
+	if o.Action != ActionArchive && o.Action != ActionPurge {
+		return fmt.Errorf("retention action should be %s or %s", ActionArchive, ActionPurge)
+	}
+	if o.BatchSize <= 0 || o.BatchSize > 10000 {
+		return fmt.Errorf("retention batchSize should be in (0, 10000]")
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enable, "retention-enable", o.Enable, "enable moving finished tasks out of the task table")
+	fs.DurationVar(&o.Interval, "retention-interval", o.Interval, "interval of task retention")
+	fs.DurationVar(&o.Age, "retention-age", o.Age, "how long tasks are kept after they are finished and delivered webhook deliveries after they are delivered, 0 keeps them forever")
+	fs.StringVar(&o.Action, "retention-action", o.Action, "action on expired tasks, archive or purge")
+	fs.IntVar(&o.BatchSize, "retention-batch-size", o.BatchSize, "how many tasks are moved in one transaction")
+}
+
+// RetentionPolicy ...
+func (o *Options) RetentionPolicy() *domain.RetentionPolicy {
+	return &domain.RetentionPolicy{
+		Age:         o.Age,
+		AccountAges: o.AccountAges,
+		Purge:       o.Action == ActionPurge,
+		BatchSize:   o.BatchSize,
+	}
+}
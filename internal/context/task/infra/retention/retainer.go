@@ -0,0 +1,44 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/domain"
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+)
+
+// Retainer archives or purges finished tasks periodically
+type Retainer struct {
+	opts *Options
+	svc  domain.Service
+}
+
+// NewRetainer ...
+func NewRetainer(opts *Options, svc domain.Service) *Retainer {
+	return &Retainer{opts: opts, svc: svc}
+}
+
+// Run blocks until ctx is done
+func (r *Retainer) Run(ctx context.Context) {
+	if !r.opts.Enable {
+		return
+	}
+	policy := r.opts.RetentionPolicy()
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := r.svc.Retain(ctx, policy)
+			if err != nil {
+				applog.Errorw("failed to retain tasks", "err", err)
+			}
+			if count > 0 {
+				applog.Infow("finished tasks are moved out by retention", "count", count, "action", r.opts.Action)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+
+	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+)
+
+const (
+	sseEventType         = "state"
+	sseKeepaliveInterval = 15 * time.Second
+)
+
+// eventStream writes task events as Server-Sent Events, the response is
+// committed on the first send, so errors before it can still be returned as JSON.
+type eventStream struct {
+	ctx       *app.RequestContext
+	started   bool
+	lastWrite time.Time
+}
+
+func newEventStream(ctx *app.RequestContext) *eventStream {
+	return &eventStream{ctx: ctx}
+}
+
+func (s *eventStream) start() {
+	s.ctx.SetStatusCode(http.StatusOK)
+	s.ctx.Response.Header.Set("Content-Type", "text/event-stream")
+	s.ctx.Response.Header.Set("Cache-Control", "no-cache")
+	s.ctx.Response.Header.Set("Connection", "keep-alive")
+	s.ctx.Response.HijackWriter(resp.NewChunkedBodyWriter(&s.ctx.Response, s.ctx.GetWriter()))
+	s.started = true
+}
+
+func (s *eventStream) send(events []*query.WatchEvent) error {
+	if !s.started {
+		s.start()
+	}
+	var buf bytes.Buffer
+	if len(events) == 0 {
+		// comment line keeps the connection alive and detects closed clients
+		if time.Since(s.lastWrite) < sseKeepaliveInterval {
+			return nil
+		}
+		buf.WriteString(": keepalive\n\n")
+	}
+	for _, event := range events {
+		data, err := json.Marshal(taskWatchEventDTOToVO(event))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "id: %d\nevent: %s\ndata: %s\n\n", event.ResourceVersion, sseEventType, data)
+	}
+	if _, err := s.ctx.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	s.lastWrite = time.Now()
+	return s.ctx.Flush()
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/GBA-BI/tes-api/internal/context/task/application/command"
 	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/pkg/auth"
+	"github.com/GBA-BI/tes-api/pkg/consts"
 	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
@@ -21,10 +23,15 @@ import (
 //	@Accept			application/json
 //	@Produce		application/json
 //	@Router			/api/ga4gh/tes/v1/tasks [post]
-//	@Param			request	body		CreateTaskRequest	true	"create task request"
-//	@Success		200		{object}	CreateTaskResponse
-//	@Failure		400		{object}	apperrors.AppError	"invalid param"
-//	@Failure		500		{object}	apperrors.AppError	"internal system error"
+//	@Security		BearerAuth
+//	@Param			request			body		CreateTaskRequest	true	"create task request"
+//	@Param			Idempotency-Key	header		string				false	"idempotency key, the task created first with the key is returned for repeated requests"
+//	@Success		200				{object}	CreateTaskResponse
+//	@Failure		400				{object}	apperrors.AppError	"invalid param"
+//	@Failure		401				{object}	apperrors.AppError	"unauthenticated"
+//	@Failure		403				{object}	apperrors.AppError	"bioos_info is different from the caller"
+//	@Failure		429				{object}	apperrors.AppError	"quota exceeded"
+//	@Failure		500				{object}	apperrors.AppError	"internal system error"
 func CreateTask(c context.Context, ctx *app.RequestContext, handler command.CreateHandler) {
 	var req CreateTaskRequest
 	if err := ctx.Bind(&req); err != nil {
@@ -32,6 +39,10 @@ func CreateTask(c context.Context, ctx *app.RequestContext, handler command.Crea
 		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
 		return
 	}
+	if err := req.bindIdentity(c); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
 
 	id, err := handler.Handle(c, req.toDTO())
 	if err != nil {
@@ -43,6 +54,43 @@ func CreateTask(c context.Context, ctx *app.RequestContext, handler command.Crea
 	utils.WriteHertzOKResponse(ctx, resp)
 }
 
+// BatchCreateTasks create tasks in batch
+//
+//	@Summary		batch create tasks
+//	@Description	create tasks in one transaction, results are returned by index, in ALL_OR_NOTHING mode none is created if any task fails
+//	@Tags			task
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Router			/api/v1/tasks/batch [post]
+//	@Security		BearerAuth
+//	@Param			request	body		BatchCreateTasksRequest	true	"batch create tasks request"
+//	@Success		200		{object}	BatchCreateTasksResponse
+//	@Failure		400		{object}	apperrors.AppError	"invalid param"
+//	@Failure		401		{object}	apperrors.AppError	"unauthenticated"
+//	@Failure		403		{object}	apperrors.AppError	"bioos_info is different from the caller"
+//	@Failure		500		{object}	apperrors.AppError	"internal system error"
+func BatchCreateTasks(c context.Context, ctx *app.RequestContext, handler command.BatchCreateHandler) {
+	var req BatchCreateTasksRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+	for _, task := range req.Tasks {
+		if err := task.bindIdentity(c); err != nil {
+			utils.WriteHertzErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	results, err := handler.Handle(c, req.toDTO())
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	utils.WriteHertzOKResponse(ctx, batchCreateResultsToVO(results))
+}
+
 // ListTasks list tasks
 //
 //	@Summary		list tasks
@@ -50,15 +98,22 @@ func CreateTask(c context.Context, ctx *app.RequestContext, handler command.Crea
 //	@Tags			task
 //	@Produce		application/json
 //	@Router			/api/ga4gh/tes/v1/tasks [get]
+//	@Security		BearerAuth
 //	@Param			name_prefix		query		string		false	"query name prefix"
 //	@Param			page_size		query		int			false	"query page size"	maximum(2048)	default(256)
 //	@Param			page_token		query		string		false	"query page token"
-//	@Param			view			query		string		false	"query view"	Enums(MINIMAL,BASIC,FULL)	default(MINIMAL)
+//	@Param			view			query		string		false	"query view"																		Enums(MINIMAL,BASIC,FULL)	default(MINIMAL)
+//	@Param			sort_by			query		string		false	"query sort by, PRIORITY sorts by effective priority descending then creation time"	Enums(ID,PRIORITY)			default(ID)
 //	@Param			state			query		[]string	false	"query state array"
 //	@Param			cluster_id		query		string		false	"query cluster id"
 //	@Param			without_cluster	query		bool		false	"query without cluster"
+//	@Param			quota_held		query		bool		false	"query quota held or not"
+//	@Param			retry_of		query		string		false	"query resubmissions of the task"
+//	@Param			tag_key			query		[]string	false	"query tag key array, all tags must be matched"
+//	@Param			tag_value		query		[]string	false	"query tag value array, paired with tag_key by index, empty matches any value"
 //	@Success		200				{object}	ListTasksResponse
 //	@Failure		400				{object}	apperrors.AppError	"invalid param"
+//	@Failure		403				{object}	apperrors.AppError	"permission denied"
 //	@Failure		500				{object}	apperrors.AppError	"internal system error"
 func ListTasks(c context.Context, ctx *app.RequestContext, handler query.ListHandler) {
 	var req ListTasksRequest
@@ -73,6 +128,10 @@ func ListTasks(c context.Context, ctx *app.RequestContext, handler query.ListHan
 		utils.WriteHertzErrorResponse(ctx, err)
 		return
 	}
+	if qry.Filter.AccountID, err = auth.GetIdentity(c).AccountScope(); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
 	tasks, nextPageToken, err := handler.Handle(c, qry)
 	if err != nil {
 		utils.WriteHertzErrorResponse(ctx, err)
@@ -97,11 +156,13 @@ func ListTasks(c context.Context, ctx *app.RequestContext, handler query.ListHan
 //	@Tags			task
 //	@Produce		application/json
 //	@Router			/api/ga4gh/tes/v1/tasks/{id} [get]
+//	@Security		BearerAuth
 //	@Param			id		path		string	true	"get task id"
 //	@Param			view	query		string	false	"query view"	Enums(MINIMAL,BASIC,FULL)	default(MINIMAL)
 //	@Success		200		{object}	GetTaskResponse
 //	@Failure		400		{object}	apperrors.AppError	"invalid param"
 //	@Failure		404		{object}	apperrors.AppError	"not found"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
 //	@Failure		500		{object}	apperrors.AppError	"internal system error"
 func GetTask(c context.Context, ctx *app.RequestContext, handler query.GetHandler) {
 	var req GetTaskRequest
@@ -111,7 +172,13 @@ func GetTask(c context.Context, ctx *app.RequestContext, handler query.GetHandle
 		return
 	}
 
-	task, err := handler.Handle(c, req.toDTO())
+	qry := req.toDTO()
+	var err error
+	if qry.AccountID, err = auth.GetIdentity(c).AccountScope(); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	task, err := handler.Handle(c, qry)
 	if err != nil {
 		utils.WriteHertzErrorResponse(ctx, err)
 		return
@@ -126,12 +193,14 @@ func GetTask(c context.Context, ctx *app.RequestContext, handler query.GetHandle
 //	@Tags			task
 //	@Produce		application/json
 //	@Router			/api/ga4gh/tes/v1/tasks/{id}:cancel [post]
+//	@Security		BearerAuth
 //	@Param			id	path		string	true	"cancel task id"
 //	@Success		200	{object}	CancelTaskResponse
 //	@Failure		400	{object}	apperrors.AppError	"invalid param or cannot execute"
 //	@Failure		404	{object}	apperrors.AppError	"not found"
+//	@Failure		403	{object}	apperrors.AppError	"permission denied"
 //	@Failure		500	{object}	apperrors.AppError	"internal system error"
-func CancelTask(c context.Context, ctx *app.RequestContext, handler command.CancelHandler) {
+func CancelTask(c context.Context, ctx *app.RequestContext, handler command.CancelHandler, getHandler query.GetHandler) {
 	// colon in "/{id}:cancel" conflict with hertz
 	idWithCancel := ctx.Param("idWithCancel")
 	if !strings.HasSuffix(idWithCancel, ":cancel") {
@@ -144,7 +213,20 @@ func CancelTask(c context.Context, ctx *app.RequestContext, handler command.Canc
 	req.ID = id
 	// no bind
 
-	if err := handler.Handle(c, req.toDTO()); err != nil {
+	// the account of a task never changes, so it is checked before canceling
+	accountID, err := auth.GetIdentity(c).AccountScope()
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	if accountID != "" {
+		if _, err = getHandler.Handle(c, &query.GetQuery{ID: id, AccountID: accountID}); err != nil {
+			utils.WriteHertzErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	if err = handler.Handle(c, req.toDTO()); err != nil {
 		utils.WriteHertzErrorResponse(ctx, err)
 		return
 	}
@@ -159,11 +241,13 @@ func CancelTask(c context.Context, ctx *app.RequestContext, handler command.Canc
 //	@Accept			application/json
 //	@Produce		application/json
 //	@Router			/api/v1/tasks/{id} [patch]
+//	@Security		BearerAuth
 //	@Param			id		path		string				true	"update task id"
 //	@Param			request	body		UpdateTaskRequest	true	"update task request"
 //	@Success		200		{object}	UpdateTaskResponse
 //	@Failure		400		{object}	apperrors.AppError	"invalid param or cannot execute"
 //	@Failure		404		{object}	apperrors.AppError	"not found"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
 //	@Failure		500		{object}	apperrors.AppError	"internal system error"
 func UpdateTask(c context.Context, ctx *app.RequestContext, handler command.UpdateHandler) {
 	var req UpdateTaskRequest
@@ -178,6 +262,10 @@ func UpdateTask(c context.Context, ctx *app.RequestContext, handler command.Upda
 		utils.WriteHertzErrorResponse(ctx, err)
 		return
 	}
+	if cmd.AssignedClusterID, err = auth.GetIdentity(c).ClusterScope(); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
 
 	if err = handler.Handle(c, cmd); err != nil {
 		utils.WriteHertzErrorResponse(ctx, err)
@@ -186,6 +274,127 @@ func UpdateTask(c context.Context, ctx *app.RequestContext, handler command.Upda
 	utils.WriteHertzOKResponse(ctx, &UpdateTaskResponse{})
 }
 
+// ResubmitTask resubmit task
+//
+//	@Summary		resubmit task
+//	@Description	clone the finished task into a new QUEUED task with retry_of and attempt, resources are overridden if set
+//	@Tags			task
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Router			/api/v1/tasks/{id}/resubmit [post]
+//	@Security		BearerAuth
+//	@Param			id		path		string				true	"resubmitted task id"
+//	@Param			request	body		ResubmitTaskRequest	false	"resubmit task request"
+//	@Success		200		{object}	ResubmitTaskResponse
+//	@Failure		400		{object}	apperrors.AppError	"invalid param or cannot execute"
+//	@Failure		404		{object}	apperrors.AppError	"not found"
+//	@Failure		429		{object}	apperrors.AppError	"quota exceeded"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
+//	@Failure		500		{object}	apperrors.AppError	"internal system error"
+func ResubmitTask(c context.Context, ctx *app.RequestContext, handler command.ResubmitHandler, getHandler query.GetHandler) {
+	var req ResubmitTaskRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	// the resubmitted task inherits the account of the source task, so the source task is checked
+	accountID, err := auth.GetIdentity(c).AccountScope()
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	if accountID != "" {
+		if _, err = getHandler.Handle(c, &query.GetQuery{ID: req.ID, AccountID: accountID}); err != nil {
+			utils.WriteHertzErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	id, err := handler.Handle(c, req.toDTO())
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	utils.WriteHertzOKResponse(ctx, &ResubmitTaskResponse{ID: id})
+}
+
+// ClaimTasks claim tasks
+//
+//	@Summary		claim tasks
+//	@Description	assign up to limit QUEUED tasks without cluster to the cluster, ordered by effective priority then creation time
+//	@Tags			task
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Router			/api/v1/tasks/claim [post]
+//	@Security		BearerAuth
+//	@Param			request	body		ClaimTasksRequest	true	"claim tasks request"
+//	@Success		200		{object}	ClaimTasksResponse
+//	@Failure		400		{object}	apperrors.AppError	"invalid param"
+//	@Failure		404		{object}	apperrors.AppError	"cluster not found"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
+//	@Failure		500		{object}	apperrors.AppError	"internal system error"
+func ClaimTasks(c context.Context, ctx *app.RequestContext, handler command.ClaimHandler, getHandler query.GetHandler) {
+	var req ClaimTasksRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	if err := req.bindCluster(c); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+
+	ids, err := handler.Handle(c, req.toDTO())
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	tasks := make([]*Task, 0, len(ids))
+	for _, id := range ids {
+		task, err := getHandler.Handle(c, &query.GetQuery{ID: id, View: consts.FullView})
+		if err != nil {
+			utils.WriteHertzErrorResponse(ctx, err)
+			return
+		}
+		tasks = append(tasks, taskDTOToVO(task))
+	}
+	utils.WriteHertzOKResponse(ctx, &ClaimTasksResponse{Tasks: tasks})
+}
+
+// BulkCancelTasks cancel tasks in bulk
+//
+//	@Summary		bulk cancel tasks
+//	@Description	move every non-finished task matching the filter to CANCELING, at least one of bioos_info and tags is required
+//	@Tags			task
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Router			/api/v1/tasks/cancel [post]
+//	@Security		BearerAuth
+//	@Param			request	body		BulkCancelTasksRequest	true	"bulk cancel tasks request"
+//	@Success		200		{object}	BulkCancelTasksResponse
+//	@Failure		400		{object}	apperrors.AppError	"invalid param"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
+//	@Failure		500		{object}	apperrors.AppError	"internal system error"
+func BulkCancelTasks(c context.Context, ctx *app.RequestContext, handler command.BulkCancelHandler) {
+	var req BulkCancelTasksRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	result, err := handler.Handle(c, req.toDTO())
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	utils.WriteHertzOKResponse(ctx, bulkCancelResultToVO(result))
+}
+
 // GatherTasksResources gather tasks resources
 //
 //	@Summary		gather tasks resources
@@ -193,6 +402,7 @@ func UpdateTask(c context.Context, ctx *app.RequestContext, handler command.Upda
 //	@Tags			task
 //	@Produce		application/json
 //	@Router			/api/v1/tasks/resources [get]
+//	@Security		BearerAuth
 //	@Param			state			query		[]string	false	"query state array"
 //	@Param			cluster_id		query		string		false	"query cluster id"
 //	@Param			with_cluster	query		bool		false	"query with cluster"
@@ -200,6 +410,7 @@ func UpdateTask(c context.Context, ctx *app.RequestContext, handler command.Upda
 //	@Param			user_id			query		string		false	"query user id"
 //	@Success		200				{object}	GatherTasksResourcesResponse
 //	@Failure		400				{object}	apperrors.AppError	"invalid param"
+//	@Failure		403				{object}	apperrors.AppError	"permission denied"
 //	@Failure		500				{object}	apperrors.AppError	"internal system error"
 func GatherTasksResources(c context.Context, ctx *app.RequestContext, handler query.GatherHandler) {
 	var req GatherTasksResourcesRequest
@@ -224,7 +435,9 @@ func GatherTasksResources(c context.Context, ctx *app.RequestContext, handler qu
 //	@Tags			task
 //	@Produce		application/json
 //	@Router			/api/v1/tasks/accounts [get]
+//	@Security		BearerAuth
 //	@Success		200	{object}	ListTasksAccountsResponse
+//	@Failure		403	{object}	apperrors.AppError	"permission denied"
 //	@Failure		500	{object}	apperrors.AppError	"internal system error"
 func ListTasksAccounts(c context.Context, ctx *app.RequestContext, handler query.ListAccountsHandler) {
 	accountInfos, err := handler.Handle(c)
@@ -238,3 +451,93 @@ func ListTasksAccounts(c context.Context, ctx *app.RequestContext, handler query
 	}
 	utils.WriteHertzOKResponse(ctx, &ListTasksAccountsResponse{Accounts: items})
 }
+
+// ListTaskEvents list task events
+//
+//	@Summary		list task events
+//	@Description	list state transitions of task in order
+//	@Tags			task
+//	@Produce		application/json
+//	@Router			/api/v1/tasks/{id}/events [get]
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"task id"
+//	@Success		200	{object}	ListTaskEventsResponse
+//	@Failure		400	{object}	apperrors.AppError	"invalid param"
+//	@Failure		404	{object}	apperrors.AppError	"not found"
+//	@Failure		403	{object}	apperrors.AppError	"permission denied"
+//	@Failure		500	{object}	apperrors.AppError	"internal system error"
+func ListTaskEvents(c context.Context, ctx *app.RequestContext, handler query.ListEventsHandler) {
+	var req ListTaskEventsRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	qry := req.toDTO()
+	var err error
+	if qry.AccountID, err = auth.GetIdentity(c).AccountScope(); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	events, err := handler.Handle(c, qry)
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	items := make([]*TaskEvent, 0, len(events))
+	for _, event := range events {
+		items = append(items, taskEventDTOToVO(event))
+	}
+	utils.WriteHertzOKResponse(ctx, &ListTaskEventsResponse{Events: items})
+}
+
+// WatchTasks watch task state changes
+//
+//	@Summary		watch tasks
+//	@Description	stream task state changes as Server-Sent Events, resumable by resource_version or Last-Event-ID
+//	@Tags			task
+//	@Produce		text/event-stream
+//	@Router			/api/v1/tasks/watch [get]
+//	@Security		BearerAuth
+//	@Param			ids					query		[]string	false	"query task ids"
+//	@Param			account_id			query		string		false	"query account id"
+//	@Param			user_id				query		string		false	"query user id"
+//	@Param			run_id				query		string		false	"query run id"
+//	@Param			resource_version	query		string		false	"resume after the resource version"
+//	@Param			Last-Event-ID		header		string		false	"resume after the event id"
+//	@Success		200					{object}	TaskWatchEvent
+//	@Failure		400					{object}	apperrors.AppError	"invalid param"
+//	@Failure		403					{object}	apperrors.AppError	"permission denied"
+//	@Failure		500					{object}	apperrors.AppError	"internal system error"
+func WatchTasks(c context.Context, ctx *app.RequestContext, handler query.WatchHandler) {
+	var req WatchTasksRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+	watchQuery, err := req.toDTO()
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	accountID, err := auth.GetIdentity(c).AccountScope()
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	if accountID != "" {
+		watchQuery.Filter.AccountID = accountID
+	}
+
+	stream := newEventStream(ctx)
+	if err = handler.Handle(c, watchQuery, stream.send); err != nil {
+		if !stream.started {
+			utils.WriteHertzErrorResponse(ctx, err)
+			return
+		}
+		// client closed or db failed, the client should reconnect with Last-Event-ID
+		applog.CtxInfow(c, "watch tasks stopped", "err", err)
+	}
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	applog "github.com/GBA-BI/tes-api/pkg/log"
 
 	"github.com/GBA-BI/tes-api/internal/context/task/application/command"
 	"github.com/GBA-BI/tes-api/internal/context/task/application/query"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
 	"github.com/GBA-BI/tes-api/pkg/utils"
 )
@@ -23,6 +27,13 @@ func (r *CreateTaskRequest) toDTO() *command.CreateCommand {
 		Tags:          r.Tags,
 		BioosInfo:     r.BioosInfo.toDTO(),
 		PriorityValue: r.PriorityValue,
+		CallbackURL:   r.CallbackURL,
+
+		MaxRetries:     r.MaxRetries,
+		IdempotencyKey: r.IdempotencyKey,
+	}
+	if r.IdempotencyKeyHeader != "" {
+		res.IdempotencyKey = r.IdempotencyKeyHeader
 	}
 	if len(r.Inputs) > 0 {
 		res.Inputs = make([]*command.Input, len(r.Inputs))
@@ -45,6 +56,48 @@ func (r *CreateTaskRequest) toDTO() *command.CreateCommand {
 	return res
 }
 
+// bindIdentity fills account and user of the task with the caller identity,
+// and denies ones different from the identity
+func (r *CreateTaskRequest) bindIdentity(ctx context.Context) error {
+	identity := auth.GetIdentity(ctx)
+	if r == nil || identity == nil || identity.AccountID == "" && identity.UserID == "" {
+		return nil
+	}
+	if r.BioosInfo == nil {
+		r.BioosInfo = &BioosInfo{}
+	}
+	var err error
+	r.BioosInfo.AccountID, r.BioosInfo.UserID, err = identity.BindOwner(r.BioosInfo.AccountID, r.BioosInfo.UserID)
+	return err
+}
+
+func (r *BatchCreateTasksRequest) toDTO() *command.BatchCreateCommand {
+	res := &command.BatchCreateCommand{Mode: r.Mode}
+	if len(r.Tasks) > 0 {
+		res.Tasks = make([]*command.CreateCommand, len(r.Tasks))
+		for index, task := range r.Tasks {
+			res.Tasks[index] = task.toDTO()
+		}
+	}
+	return res
+}
+
+func batchCreateResultsToVO(results []*command.BatchCreateResult) *BatchCreateTasksResponse {
+	res := &BatchCreateTasksResponse{Tasks: make([]*BatchCreateTaskResult, len(results))}
+	for index, result := range results {
+		res.Tasks[index] = &BatchCreateTaskResult{ID: result.ID}
+		if result.Err == nil {
+			continue
+		}
+		appError := new(apperrors.AppError)
+		if !errors.As(result.Err, &appError) {
+			appError = apperrors.NewInternalError(result.Err)
+		}
+		res.Tasks[index].Error = &BatchCreateTaskError{Code: appError.Code, Message: appError.Message}
+	}
+	return res
+}
+
 func (i *Input) toDTO() *command.Input {
 	if i == nil {
 		return nil
@@ -77,10 +130,14 @@ func (r *Resources) toDTO() *command.Resources {
 		return nil
 	}
 	res := &command.Resources{
-		CPUCores:   r.CPUCores,
-		RamGB:      r.RamGB,
-		DiskGB:     r.DiskGB,
-		BootDiskGB: r.BootDiskGB,
+		CPUCores:                r.CPUCores,
+		RamGB:                   r.RamGB,
+		DiskGB:                  r.DiskGB,
+		BootDiskGB:              r.BootDiskGB,
+		Preemptible:             r.Preemptible,
+		Zones:                   r.Zones,
+		BackendParameters:       r.BackendParameters,
+		BackendParametersStrict: r.BackendParametersStrict,
 	}
 	if r.GPU != nil {
 		res.GPU = &command.GPUResource{
@@ -96,13 +153,14 @@ func (e *Executor) toDTO() *command.Executor {
 		return nil
 	}
 	return &command.Executor{
-		Image:   e.Image,
-		Workdir: e.Workdir,
-		Command: e.Command,
-		Stdin:   e.Stdin,
-		Stdout:  e.Stdout,
-		Stderr:  e.Stderr,
-		Env:     e.Env,
+		Image:       e.Image,
+		Workdir:     e.Workdir,
+		Command:     e.Command,
+		Stdin:       e.Stdin,
+		Stdout:      e.Stdout,
+		Stderr:      e.Stderr,
+		Env:         e.Env,
+		IgnoreError: e.IgnoreError,
 	}
 }
 
@@ -156,19 +214,49 @@ func (r *ListTasksRequest) toDTO() (*query.ListQuery, error) {
 	if err != nil {
 		return nil, err
 	}
+	tags, err := parseTagFilter(r.TagKey, r.TagValue)
+	if err != nil {
+		return nil, err
+	}
 	return &query.ListQuery{
 		View:      r.View,
 		PageSize:  r.PageSize,
 		PageToken: pageToken,
+		SortBy:    r.SortBy,
 		Filter: &query.ListFilter{
 			NamePrefix:     r.NamePrefix,
 			State:          r.State,
 			ClusterID:      r.ClusterID,
 			WithoutCluster: r.WithoutCluster,
+			QuotaHeld:      r.QuotaHeld,
+			RetryOf:        r.RetryOf,
+			Tags:           tags,
 		},
 	}, nil
 }
 
+// parseTagFilter pairs tag_key and tag_value by index, as TES defines.
+// missing or empty tag_value means only the tag_key is required.
+func parseTagFilter(keys, values []string) (map[string]string, error) {
+	if len(values) > len(keys) {
+		return nil, apperrors.NewInvalidError("tag_value")
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	res := make(map[string]string, len(keys))
+	for index, key := range keys {
+		if _, ok := res[key]; ok {
+			return nil, apperrors.NewInvalidError("tag_key")
+		}
+		res[key] = ""
+		if index < len(values) {
+			res[key] = values[index]
+		}
+	}
+	return res, nil
+}
+
 func (r *GetTaskRequest) toDTO() *query.GetQuery {
 	if r == nil {
 		return nil
@@ -176,6 +264,37 @@ func (r *GetTaskRequest) toDTO() *query.GetQuery {
 	return &query.GetQuery{ID: r.ID, View: r.View}
 }
 
+func (r *ListTaskEventsRequest) toDTO() *query.ListEventsQuery {
+	if r == nil {
+		return nil
+	}
+	return &query.ListEventsQuery{ID: r.ID}
+}
+
+func (r *WatchTasksRequest) toDTO() (*query.WatchQuery, error) {
+	if r == nil {
+		return nil, nil
+	}
+	res := &query.WatchQuery{Filter: &query.WatchFilter{
+		IDs:       r.IDs,
+		AccountID: r.AccountID,
+		UserID:    r.UserID,
+		RunID:     r.RunID,
+	}}
+	resourceVersion := r.ResourceVersion
+	if resourceVersion == "" {
+		resourceVersion = r.LastEventID
+	}
+	if resourceVersion != "" {
+		value, err := strconv.ParseInt(resourceVersion, 10, 64)
+		if err != nil {
+			return nil, apperrors.NewInvalidError("resource_version")
+		}
+		res.ResourceVersion = &value
+	}
+	return res, nil
+}
+
 func (r *CancelTaskRequest) toDTO() *command.CancelCommand {
 	if r == nil {
 		return nil
@@ -242,6 +361,33 @@ func (t *TaskLog) toDTO() (*command.TaskLog, error) {
 			}
 		}
 	}
+	if len(t.Outputs) > 0 {
+		res.Outputs = make([]*command.OutputFileLog, len(t.Outputs))
+		for index, output := range t.Outputs {
+			if res.Outputs[index], err = output.toDTO(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+func (o *OutputFileLog) toDTO() (*command.OutputFileLog, error) {
+	if o == nil {
+		return nil, nil
+	}
+	res := &command.OutputFileLog{
+		URL:  o.URL,
+		Path: o.Path,
+	}
+	if o.SizeBytes != "" {
+		sizeBytes, err := strconv.ParseInt(o.SizeBytes, 10, 64)
+		if err != nil {
+			applog.Errorw("parse sizeBytes of outputFileLog", "err", err)
+			return nil, apperrors.NewInvalidError("size_bytes")
+		}
+		res.SizeBytes = sizeBytes
+	}
 	return res, nil
 }
 
@@ -251,6 +397,9 @@ func (e *ExecutorLog) toDTO() (*command.ExecutorLog, error) {
 	}
 	res := &command.ExecutorLog{
 		ExecutorID: e.ExecutorID,
+		ExitCode:   e.ExitCode,
+		Stdout:     e.Stdout,
+		Stderr:     e.Stderr,
 	}
 	if e.StartTime != nil && *e.StartTime != "" {
 		startTime, err := time.Parse(time.RFC3339, *e.StartTime)
@@ -271,6 +420,66 @@ func (e *ExecutorLog) toDTO() (*command.ExecutorLog, error) {
 	return res, nil
 }
 
+func (r *ClaimTasksRequest) toDTO() *command.ClaimCommand {
+	if r == nil {
+		return nil
+	}
+	return &command.ClaimCommand{ClusterID: r.ClusterID, Limit: r.Limit}
+}
+
+// bindCluster fills the cluster to claim tasks for with the caller identity,
+// and denies another cluster
+func (r *ClaimTasksRequest) bindCluster(ctx context.Context) error {
+	clusterID, err := auth.GetIdentity(ctx).ClusterScope()
+	if err != nil || clusterID == "" {
+		return err
+	}
+	if r.ClusterID != "" && r.ClusterID != clusterID {
+		return apperrors.NewPermissionDeniedError("cluster_id is different from the caller")
+	}
+	r.ClusterID = clusterID
+	return nil
+}
+
+func (r *ResubmitTaskRequest) toDTO() *command.ResubmitCommand {
+	res := &command.ResubmitCommand{ID: r.ID}
+	if r.Resources != nil {
+		res.Resources = &command.ResubmitResources{
+			CPUCores:    r.Resources.CPUCores,
+			RamGB:       r.Resources.RamGB,
+			DiskGB:      r.Resources.DiskGB,
+			BootDiskGB:  r.Resources.BootDiskGB,
+			Preemptible: r.Resources.Preemptible,
+		}
+	}
+	return res
+}
+
+func (r *BulkCancelTasksRequest) toDTO() *command.BulkCancelCommand {
+	res := &command.BulkCancelCommand{
+		Tags:  r.Tags,
+		State: r.State,
+	}
+	if r.BioosInfo != nil {
+		res.BioosInfo = &command.BulkCancelBioosInfo{
+			AccountID:    r.BioosInfo.AccountID,
+			UserID:       r.BioosInfo.UserID,
+			SubmissionID: r.BioosInfo.SubmissionID,
+			RunID:        r.BioosInfo.RunID,
+		}
+	}
+	return res
+}
+
+func bulkCancelResultToVO(result *command.BulkCancelResult) *BulkCancelTasksResponse {
+	return &BulkCancelTasksResponse{
+		Canceled:        result.Canceled,
+		AlreadyFinished: result.AlreadyFinished,
+		Failed:          result.Failed,
+		FailedIDs:       result.FailedIDs,
+	}
+}
+
 func (r *GatherTasksResourcesRequest) toDTO() *query.GatherQuery {
 	return &query.GatherQuery{Filter: &query.GatherFilter{
 		State:       r.State,
@@ -286,16 +495,23 @@ func taskDTOToVO(task *query.Task) *Task {
 		return nil
 	}
 	res := &Task{
-		ID:            task.ID,
-		State:         task.State,
-		Name:          task.Name,
-		Description:   task.Description,
-		Resources:     resourcesDTOToVO(task.Resources),
-		Volumes:       task.Volumes,
-		Tags:          task.Tags,
-		BioosInfo:     bioosInfoDTOToVO(task.BioosInfo),
-		PriorityValue: task.PriorityValue,
-		ClusterID:     task.ClusterID,
+		ID:                task.ID,
+		State:             task.State,
+		Name:              task.Name,
+		Description:       task.Description,
+		Resources:         resourcesDTOToVO(task.Resources),
+		Volumes:           task.Volumes,
+		Tags:              task.Tags,
+		BioosInfo:         bioosInfoDTOToVO(task.BioosInfo),
+		PriorityValue:     task.PriorityValue,
+		ClusterID:         task.ClusterID,
+		QuotaHeld:         task.QuotaHeld,
+		EffectivePriority: task.EffectivePriority,
+		CallbackURL:       task.CallbackURL,
+		RetryOf:           task.RetryOf,
+		Attempt:           task.Attempt,
+		MaxRetries:        task.MaxRetries,
+		Retries:           task.Retries,
 	}
 	if !task.CreationTime.IsZero() {
 		res.CreationTime = task.CreationTime.Format(time.RFC3339)
@@ -359,10 +575,14 @@ func resourcesDTOToVO(resources *query.Resources) *Resources {
 		return nil
 	}
 	res := &Resources{
-		CPUCores:   resources.CPUCores,
-		RamGB:      resources.RamGB,
-		DiskGB:     resources.DiskGB,
-		BootDiskGB: resources.BootDiskGB,
+		CPUCores:                resources.CPUCores,
+		RamGB:                   resources.RamGB,
+		DiskGB:                  resources.DiskGB,
+		BootDiskGB:              resources.BootDiskGB,
+		Preemptible:             resources.Preemptible,
+		Zones:                   resources.Zones,
+		BackendParameters:       resources.BackendParameters,
+		BackendParametersStrict: resources.BackendParametersStrict,
 	}
 	if resources.GPU != nil {
 		res.GPU = &GPUResource{Count: resources.GPU.Count, Type: resources.GPU.Type}
@@ -375,13 +595,14 @@ func executorDTOToVO(executor *query.Executor) *Executor {
 		return nil
 	}
 	return &Executor{
-		Image:   executor.Image,
-		Command: executor.Command,
-		Workdir: executor.Workdir,
-		Stdin:   executor.Stdin,
-		Stdout:  executor.Stdout,
-		Stderr:  executor.Stderr,
-		Env:     executor.Env,
+		Image:       executor.Image,
+		Command:     executor.Command,
+		Workdir:     executor.Workdir,
+		Stdin:       executor.Stdin,
+		Stdout:      executor.Stdout,
+		Stderr:      executor.Stderr,
+		Env:         executor.Env,
+		IgnoreError: executor.IgnoreError,
 	}
 }
 
@@ -410,15 +631,35 @@ func taskLogDTOToVO(taskLog *query.TaskLog) *TaskLog {
 			}
 		}
 	}
+	if len(taskLog.Outputs) > 0 {
+		res.Outputs = make([]*OutputFileLog, len(taskLog.Outputs))
+		for index, output := range taskLog.Outputs {
+			res.Outputs[index] = outputFileLogDTOToVO(output)
+		}
+	}
 	return res
 }
 
+func outputFileLogDTOToVO(output *query.OutputFileLog) *OutputFileLog {
+	if output == nil {
+		return nil
+	}
+	return &OutputFileLog{
+		URL:       output.URL,
+		Path:      output.Path,
+		SizeBytes: strconv.FormatInt(output.SizeBytes, 10),
+	}
+}
+
 func executorLogDTOToVO(executorLog *query.ExecutorLog) *ExecutorLog {
 	if executorLog == nil {
 		return nil
 	}
 	res := &ExecutorLog{
 		ExecutorID: executorLog.ExecutorID,
+		ExitCode:   executorLog.ExitCode,
+		Stdout:     executorLog.Stdout,
+		Stderr:     executorLog.Stderr,
 	}
 	if executorLog.StartTime != nil && !executorLog.StartTime.IsZero() {
 		res.StartTime = utils.Point(executorLog.StartTime.Format(time.RFC3339))
@@ -487,3 +728,21 @@ func taskAccountInfoDTOToVO(accountInfo *query.AccountInfo) *AccountInfo {
 		UserIDs:   accountInfo.UserIDs,
 	}
 }
+
+func taskEventDTOToVO(event *query.TaskEvent) *TaskEvent {
+	return &TaskEvent{
+		Time:          event.Time.Format(time.RFC3339),
+		PreviousState: event.PreviousState,
+		State:         event.State,
+		ClusterID:     event.ClusterID,
+		RequestID:     event.RequestID,
+	}
+}
+
+func taskWatchEventDTOToVO(event *query.WatchEvent) *TaskWatchEvent {
+	return &TaskWatchEvent{
+		ResourceVersion: event.ResourceVersion,
+		TaskID:          event.TaskID,
+		TaskEvent:       *taskEventDTOToVO(&event.TaskEvent),
+	}
+}
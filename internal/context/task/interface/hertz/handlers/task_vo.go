@@ -12,6 +12,15 @@ type CreateTaskRequest struct {
 	Tags          map[string]string `json:"tags,omitempty"`
 	BioosInfo     *BioosInfo        `json:"bioos_info,omitempty"`
 	PriorityValue int               `json:"priority_value,omitempty"`
+	// CallbackURL is notified when the task is finished
+	CallbackURL string `json:"callback_url,omitempty"`
+	// MaxRetries is how many times SYSTEM_ERROR is retried by requeueing the task,
+	// the policy of the account or server is applied if it is not set
+	MaxRetries *int `json:"max_retries,omitempty"`
+	// IdempotencyKey makes repeated creations of the same owner return the task created first
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// IdempotencyKeyHeader takes precedence over idempotency_key
+	IdempotencyKeyHeader string `header:"Idempotency-Key" json:"-"`
 }
 
 // CreateTaskResponse ...
@@ -19,15 +28,45 @@ type CreateTaskResponse struct {
 	ID string `json:"id"`
 }
 
+// BatchCreateTasksRequest ...
+type BatchCreateTasksRequest struct {
+	Tasks []*CreateTaskRequest `json:"tasks"`
+	// Mode is ALL_OR_NOTHING or BEST_EFFORT, none of the tasks is created in ALL_OR_NOTHING if any fails
+	Mode string `json:"mode,omitempty"`
+}
+
+// BatchCreateTasksResponse ...
+type BatchCreateTasksResponse struct {
+	// Tasks are results of tasks in the request by index
+	Tasks []*BatchCreateTaskResult `json:"tasks"`
+}
+
+// BatchCreateTaskResult has id if the task is created, or error if the task fails
+type BatchCreateTaskResult struct {
+	ID    string                `json:"id,omitempty"`
+	Error *BatchCreateTaskError `json:"error,omitempty"`
+}
+
+// BatchCreateTaskError ...
+type BatchCreateTaskError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
 // ListTasksRequest ...
 type ListTasksRequest struct {
 	NamePrefix     string   `query:"name_prefix"`
 	State          []string `query:"state"`
 	ClusterID      string   `query:"cluster_id"`
 	WithoutCluster bool     `query:"without_cluster"`
+	QuotaHeld      *bool    `query:"quota_held"`
+	RetryOf        string   `query:"retry_of"`
+	TagKey         []string `query:"tag_key"`
+	TagValue       []string `query:"tag_value"`
 	View           string   `query:"view"`
 	PageSize       int      `query:"page_size"`
 	PageToken      string   `query:"page_token"`
+	SortBy         string   `query:"sort_by"`
 }
 
 // ListTasksResponse ...
@@ -66,6 +105,61 @@ type UpdateTaskRequest struct {
 // UpdateTaskResponse ...
 type UpdateTaskResponse struct{}
 
+// ResubmitTaskRequest ...
+type ResubmitTaskRequest struct {
+	ID string `path:"id" json:"-"`
+	// Resources overrides resources of the finished task if set
+	Resources *ResubmitResources `json:"resources,omitempty"`
+}
+
+// ResubmitResources ...
+type ResubmitResources struct {
+	CPUCores    *int     `json:"cpu_cores,omitempty"`
+	RamGB       *float64 `json:"ram_gb,omitempty"` // nolint
+	DiskGB      *float64 `json:"disk_gb,omitempty"`
+	BootDiskGB  *int     `json:"boot_disk_gb,omitempty"`
+	Preemptible *bool    `json:"preemptible,omitempty"`
+}
+
+// ResubmitTaskResponse ...
+type ResubmitTaskResponse struct {
+	ID string `json:"id"`
+}
+
+// ClaimTasksRequest ...
+type ClaimTasksRequest struct {
+	ClusterID string `json:"cluster_id"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// ClaimTasksResponse ...
+type ClaimTasksResponse struct {
+	Tasks []*Task `json:"tasks"`
+}
+
+// BulkCancelTasksRequest ...
+type BulkCancelTasksRequest struct {
+	BioosInfo *BulkCancelBioosInfo `json:"bioos_info,omitempty"`
+	Tags      map[string]string    `json:"tags,omitempty"`
+	State     []string             `json:"state,omitempty"`
+}
+
+// BulkCancelBioosInfo ...
+type BulkCancelBioosInfo struct {
+	AccountID    string `json:"account_id,omitempty"`
+	UserID       string `json:"user_id,omitempty"`
+	SubmissionID string `json:"submission_id,omitempty"`
+	RunID        string `json:"run_id,omitempty"`
+}
+
+// BulkCancelTasksResponse ...
+type BulkCancelTasksResponse struct {
+	Canceled        int      `json:"canceled"`
+	AlreadyFinished int      `json:"already_finished"`
+	Failed          int      `json:"failed"`
+	FailedIDs       []string `json:"failed_ids,omitempty"`
+}
+
 // GatherTasksResourcesRequest ...
 type GatherTasksResourcesRequest struct {
 	State       []string `query:"state"`
@@ -95,6 +189,43 @@ type AccountInfo struct {
 	UserIDs   []string `json:"user_ids"`
 }
 
+// ListTaskEventsRequest ...
+type ListTaskEventsRequest struct {
+	ID string `path:"id"`
+}
+
+// ListTaskEventsResponse ...
+type ListTaskEventsResponse struct {
+	Events []*TaskEvent `json:"events"`
+}
+
+// TaskEvent ...
+type TaskEvent struct {
+	Time          string `json:"time"`
+	PreviousState string `json:"previous_state,omitempty"`
+	State         string `json:"state"`
+	ClusterID     string `json:"cluster_id,omitempty"`
+	RequestID     string `json:"request_id,omitempty"`
+}
+
+// WatchTasksRequest ...
+type WatchTasksRequest struct {
+	IDs             []string `query:"ids"`
+	AccountID       string   `query:"account_id"`
+	UserID          string   `query:"user_id"`
+	RunID           string   `query:"run_id"`
+	ResourceVersion string   `query:"resource_version"`
+	// LastEventID is set by EventSource when reconnecting, resource_version takes precedence
+	LastEventID string `header:"Last-Event-ID"`
+}
+
+// TaskWatchEvent ...
+type TaskWatchEvent struct {
+	ResourceVersion int64  `json:"resource_version"`
+	TaskID          string `json:"task_id"`
+	TaskEvent
+}
+
 // Task ...
 type Task struct {
 	ID            string            `json:"id"`
@@ -112,6 +243,18 @@ type Task struct {
 	BioosInfo     *BioosInfo        `json:"bioos_info,omitempty"`
 	PriorityValue int               `json:"priority_value,omitempty"`
 	ClusterID     string            `json:"cluster_id,omitempty"`
+	QuotaHeld     bool              `json:"quota_held,omitempty"`
+	// EffectivePriority is priority_value plus extra priorities applied to the task
+	EffectivePriority int    `json:"effective_priority,omitempty"`
+	CallbackURL       string `json:"callback_url,omitempty"`
+	// RetryOf is id of the task resubmitted as this one
+	RetryOf string `json:"retry_of,omitempty"`
+	// Attempt is 1 for the first submission, and increased by each resubmission
+	Attempt int `json:"attempt,omitempty"`
+	// MaxRetries is how many times SYSTEM_ERROR is retried by requeueing the task
+	MaxRetries int `json:"max_retries,omitempty"`
+	// Retries is how many times the task is requeued after SYSTEM_ERROR
+	Retries int `json:"retries,omitempty"`
 }
 
 // Input ...
@@ -135,11 +278,15 @@ type Output struct {
 
 // Resources ...
 type Resources struct {
-	CPUCores   int          `json:"cpu_cores,omitempty"`
-	RamGB      float64      `json:"ram_gb,omitempty"` // nolint
-	DiskGB     float64      `json:"disk_gb,omitempty"`
-	BootDiskGB *int         `json:"boot_disk_gb,omitempty"`
-	GPU        *GPUResource `json:"gpu,omitempty"`
+	CPUCores                int               `json:"cpu_cores,omitempty"`
+	RamGB                   float64           `json:"ram_gb,omitempty"` // nolint
+	DiskGB                  float64           `json:"disk_gb,omitempty"`
+	BootDiskGB              *int              `json:"boot_disk_gb,omitempty"`
+	GPU                     *GPUResource      `json:"gpu,omitempty"`
+	Preemptible             bool              `json:"preemptible,omitempty"`
+	Zones                   []string          `json:"zones,omitempty"`
+	BackendParameters       map[string]string `json:"backend_parameters,omitempty"`
+	BackendParametersStrict bool              `json:"backend_parameters_strict,omitempty"`
 }
 
 // GPUResource ...
@@ -150,13 +297,14 @@ type GPUResource struct {
 
 // Executor ...
 type Executor struct {
-	Image   string            `json:"image"`
-	Command []string          `json:"command"`
-	Workdir string            `json:"workdir,omitempty"`
-	Stdin   string            `json:"stdin,omitempty"`
-	Stdout  string            `json:"stdout,omitempty"`
-	Stderr  string            `json:"stderr,omitempty"`
-	Env     map[string]string `json:"env,omitempty"`
+	Image       string            `json:"image"`
+	Command     []string          `json:"command"`
+	Workdir     string            `json:"workdir,omitempty"`
+	Stdin       string            `json:"stdin,omitempty"`
+	Stdout      string            `json:"stdout,omitempty"`
+	Stderr      string            `json:"stderr,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	IgnoreError bool              `json:"ignore_error,omitempty"`
 }
 
 // BioosInfo ...
@@ -196,6 +344,14 @@ type TaskLog struct {
 	StartTime  *string          `json:"start_time,omitempty"`
 	EndTime    *string          `json:"end_time,omitempty"`
 	SystemLogs []string         `json:"system_logs,omitempty"`
+	Outputs    []*OutputFileLog `json:"outputs,omitempty"`
+}
+
+// OutputFileLog ...
+type OutputFileLog struct {
+	URL       string `json:"url"`
+	Path      string `json:"path"`
+	SizeBytes string `json:"size_bytes"`
 }
 
 // ExecutorLog ...
@@ -203,4 +359,7 @@ type ExecutorLog struct {
 	ExecutorID string  `json:"executor_id"`
 	StartTime  *string `json:"start_time,omitempty"`
 	EndTime    *string `json:"end_time,omitempty"`
+	ExitCode   *int32  `json:"exit_code,omitempty"`
+	Stdout     string  `json:"stdout,omitempty"`
+	Stderr     string  `json:"stderr,omitempty"`
 }
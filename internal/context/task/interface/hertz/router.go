@@ -8,6 +8,7 @@ import (
 
 	"github.com/GBA-BI/tes-api/internal/context/task/application"
 	"github.com/GBA-BI/tes-api/internal/context/task/interface/hertz/handlers"
+	"github.com/GBA-BI/tes-api/pkg/auth"
 	"github.com/GBA-BI/tes-api/pkg/consts"
 	appserver "github.com/GBA-BI/tes-api/pkg/server"
 )
@@ -26,34 +27,58 @@ func NewRouterRegister(taskService *application.TaskService) appserver.RouteRegi
 func (r *register) AddRoute(h route.IRouter) {
 	taskGA4GH := h.Group(consts.Ga4ghAPIPrefix + "/tasks")
 
-	taskGA4GH.POST("", func(c context.Context, ctx *app.RequestContext) {
+	taskGA4GH.POST("", auth.RequireRoles(auth.RoleUser, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.CreateTask(c, ctx, r.svc.TaskCommands.Create)
 	})
 
-	taskGA4GH.GET("", func(c context.Context, ctx *app.RequestContext) {
+	taskGA4GH.GET("", auth.RequireRoles(auth.RoleUser, auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.ListTasks(c, ctx, r.svc.TaskQueries.List)
 	})
 
-	taskGA4GH.GET("/:id", func(c context.Context, ctx *app.RequestContext) {
+	taskGA4GH.GET("/:id", auth.RequireRoles(auth.RoleUser, auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.GetTask(c, ctx, r.svc.TaskQueries.Get)
 	})
 
 	// colon in "/{id}:cancel" conflict with hertz
-	taskGA4GH.POST("/:idWithCancel", func(c context.Context, ctx *app.RequestContext) {
-		handlers.CancelTask(c, ctx, r.svc.TaskCommands.Cancel)
+	taskGA4GH.POST("/:idWithCancel", auth.RequireRoles(auth.RoleUser, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.CancelTask(c, ctx, r.svc.TaskCommands.Cancel, r.svc.TaskQueries.Get)
 	})
 
 	taskOther := h.Group(consts.OtherAPIPrefix + "/tasks")
 
-	taskOther.PATCH("/:id", func(c context.Context, ctx *app.RequestContext) {
+	taskOther.PATCH("/:id", auth.RequireRoles(auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.UpdateTask(c, ctx, r.svc.TaskCommands.Update)
 	})
 
-	taskOther.GET("/resources", func(c context.Context, ctx *app.RequestContext) {
+	taskOther.POST("/batch", auth.RequireRoles(auth.RoleUser, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.BatchCreateTasks(c, ctx, r.svc.TaskCommands.BatchCreate)
+	})
+
+	taskOther.POST("/:id/resubmit", auth.RequireRoles(auth.RoleUser, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.ResubmitTask(c, ctx, r.svc.TaskCommands.Resubmit, r.svc.TaskQueries.Get)
+	})
+
+	taskOther.POST("/claim", auth.RequireRoles(auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.ClaimTasks(c, ctx, r.svc.TaskCommands.Claim, r.svc.TaskQueries.Get)
+	})
+
+	taskOther.POST("/cancel", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.BulkCancelTasks(c, ctx, r.svc.TaskCommands.BulkCancel)
+	})
+
+	taskOther.GET("/resources", auth.RequireRoles(auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.GatherTasksResources(c, ctx, r.svc.TaskQueries.Gather)
 	})
 
-	taskOther.GET("/accounts", func(c context.Context, ctx *app.RequestContext) {
+	taskOther.GET("/accounts", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
 		handlers.ListTasksAccounts(c, ctx, r.svc.TaskQueries.ListAccounts)
 	})
+
+	taskOther.GET("/watch", auth.RequireRoles(auth.RoleUser, auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.WatchTasks(c, ctx, r.svc.TaskQueries.Watch)
+	})
+
+	taskOther.GET("/:id/events", auth.RequireRoles(auth.RoleUser, auth.RoleClusterAgent, auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.ListTaskEvents(c, ctx, r.svc.TaskQueries.ListEvents)
+	})
 }
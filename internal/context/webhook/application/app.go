@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/internal/apiserver/options"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/command"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/infra/dispatch"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/infra/persistence/sql"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/infra/retention"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/infra/sender"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+)
+
+// WebhookService ...
+type WebhookService struct {
+	WebhookCommands *command.Commands
+	WebhookQueries  *query.Queries
+	// Dispatcher sends notifications of finished tasks in background
+	Dispatcher *dispatch.Dispatcher
+	// Retainer deletes delivered deliveries in background
+	Retainer *retention.Retainer
+}
+
+// NewWebhookService ...
+func NewWebhookService(ctx context.Context, opts *options.Options, source domain.NotificationSource) (*WebhookService, error) {
+	var (
+		err       error
+		repo      domain.Repo
+		readModel query.ReadModel
+	)
+
+	switch opts.DB.Type {
+	case consts.MySQLType, consts.PostgresType, consts.SQLiteType:
+		var db *gorm.DB
+		if db, err = opts.DB.GetGORMInstance(); err != nil {
+			return nil, err
+		}
+		if repo, err = sql.NewRepo(ctx, db); err != nil {
+			return nil, err
+		}
+		if readModel, err = sql.NewReadModel(ctx, db); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported db type")
+	}
+
+	webhookSender, err := sender.NewSender(opts.Webhook.Secret, opts.Webhook.Timeout, opts.Webhook.AllowedDestinations)
+	if err != nil {
+		return nil, err
+	}
+	svc := domain.NewService(repo, source, webhookSender)
+	webhookCommands := command.NewCommands(svc)
+	webhookQueries := query.NewQueries(readModel)
+
+	return &WebhookService{
+		WebhookCommands: webhookCommands,
+		WebhookQueries:  webhookQueries,
+		Dispatcher:      dispatch.NewDispatcher(opts.Webhook, svc),
+		Retainer:        retention.NewRetainer(opts.Retention, svc),
+	}, nil
+}
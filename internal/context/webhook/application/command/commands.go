@@ -0,0 +1,17 @@
+package command
+
+import "github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+
+// Commands ...
+type Commands struct {
+	Put    PutHandler
+	Delete DeleteHandler
+}
+
+// NewCommands ...
+func NewCommands(svc domain.Service) *Commands {
+	return &Commands{
+		Put:    NewPutHandler(svc),
+		Delete: NewDeleteHandler(svc),
+	}
+}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+// PutCommand ...
+type PutCommand struct {
+	ID        string `validate:"required,max=32"`
+	AccountID string `validate:"required,max=32"`
+	URL       string `validate:"required,http_url,max=1024"`
+	Secret    string `validate:"max=256"`
+}
+
+func (c *PutCommand) setDefault() {}
+
+func (c *PutCommand) validate() error {
+	return validator.Validate(c)
+}
+
+func (c *PutCommand) toDO() *domain.Webhook {
+	return &domain.Webhook{
+		ID:        c.ID,
+		AccountID: c.AccountID,
+		URL:       c.URL,
+		Secret:    c.Secret,
+	}
+}
+
+// PutHandler ...
+type PutHandler interface {
+	Handle(ctx context.Context, cmd *PutCommand) error
+}
+
+type putHandler struct {
+	svc domain.Service
+}
+
+var _ PutHandler = (*putHandler)(nil)
+
+// NewPutHandler ...
+func NewPutHandler(svc domain.Service) PutHandler {
+	return &putHandler{svc: svc}
+}
+
+// Handle ...
+func (h *putHandler) Handle(ctx context.Context, cmd *PutCommand) error {
+	cmd.setDefault()
+	if err := cmd.validate(); err != nil {
+		return err
+	}
+	return h.svc.Put(ctx, cmd.toDO())
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestPut(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := domain.NewFakeService(ctrl)
+	fakeService.EXPECT().Put(gomock.Any(), &domain.Webhook{
+		ID:        "webhook-01",
+		AccountID: "account-01",
+		URL:       "https://example.com/webhook",
+		Secret:    "secret",
+	}).Return(nil)
+
+	handler := NewPutHandler(fakeService)
+	err := handler.Handle(context.TODO(), &PutCommand{
+		ID:        "webhook-01",
+		AccountID: "account-01",
+		URL:       "https://example.com/webhook",
+		Secret:    "secret",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestPutInvalidURL(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewPutHandler(domain.NewFakeService(ctrl))
+	for _, url := range []string{"example", "ftp://example.com/webhook", "file:///etc/passwd"} {
+		err := handler.Handle(context.TODO(), &PutCommand{
+			ID:        "webhook-01",
+			AccountID: "account-01",
+			URL:       url,
+		})
+		g.Expect(apperrors.IsCode(err, apperrors.InvalidCode)).To(gomega.BeTrue())
+	}
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+// ListQuery ...
+type ListQuery struct {
+	Filter *ListFilter
+}
+
+// ListFilter ...
+type ListFilter struct {
+	AccountID string
+}
+
+func (q *ListQuery) setDefault() {}
+
+func (q *ListQuery) validate() error {
+	return validator.Validate(q)
+}
+
+// ListHandler ...
+type ListHandler interface {
+	Handle(ctx context.Context, query *ListQuery) ([]*Webhook, error)
+}
+
+type listHandler struct {
+	readModel ReadModel
+}
+
+var _ ListHandler = (*listHandler)(nil)
+
+// NewListHandler ...
+func NewListHandler(readModel ReadModel) ListHandler {
+	return &listHandler{readModel: readModel}
+}
+
+// Handle ...
+func (h *listHandler) Handle(ctx context.Context, query *ListQuery) ([]*Webhook, error) {
+	query.setDefault()
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+	return h.readModel.List(ctx, query.Filter)
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+
+	"github.com/GBA-BI/tes-api/pkg/validator"
+)
+
+const defaultDeadLettersLimit = 100
+
+// ListDeadLettersQuery ...
+type ListDeadLettersQuery struct {
+	Limit  int `validate:"gte=0,lte=1000"`
+	Filter *ListDeadLettersFilter
+}
+
+// ListDeadLettersFilter ...
+type ListDeadLettersFilter struct {
+	AccountID string
+	WebhookID string
+}
+
+func (q *ListDeadLettersQuery) setDefault() {
+	if q.Limit == 0 {
+		q.Limit = defaultDeadLettersLimit
+	}
+}
+
+func (q *ListDeadLettersQuery) validate() error {
+	return validator.Validate(q)
+}
+
+// ListDeadLettersHandler ...
+type ListDeadLettersHandler interface {
+	Handle(ctx context.Context, query *ListDeadLettersQuery) ([]*Delivery, error)
+}
+
+type listDeadLettersHandler struct {
+	readModel ReadModel
+}
+
+var _ ListDeadLettersHandler = (*listDeadLettersHandler)(nil)
+
+// NewListDeadLettersHandler ...
+func NewListDeadLettersHandler(readModel ReadModel) ListDeadLettersHandler {
+	return &listDeadLettersHandler{readModel: readModel}
+}
+
+// Handle ...
+func (h *listDeadLettersHandler) Handle(ctx context.Context, query *ListDeadLettersQuery) ([]*Delivery, error) {
+	query.setDefault()
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+	return h.readModel.ListDeadLetters(ctx, query.Limit, query.Filter)
+}
@@ -0,0 +1,25 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+)
+
+func TestListDeadLetters(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := &ListDeadLettersFilter{AccountID: "account-01"}
+	fakeReadModel := NewFakeReadModel(ctrl)
+	fakeReadModel.EXPECT().ListDeadLetters(gomock.Any(), defaultDeadLettersLimit, filter).
+		Return([]*Delivery{{ID: 1, State: "DEAD"}}, nil)
+
+	handler := NewListDeadLettersHandler(fakeReadModel)
+	resp, err := handler.Handle(context.TODO(), &ListDeadLettersQuery{Filter: filter})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.HaveLen(1))
+}
@@ -0,0 +1,35 @@
+package query
+
+import "time"
+
+// Webhook ...
+type Webhook struct {
+	ID        string
+	AccountID string
+	URL       string
+}
+
+// Delivery ...
+type Delivery struct {
+	ID              int64
+	WebhookID       string
+	URL             string
+	Event           *Event
+	State           string
+	Attempts        int
+	NextAttemptTime time.Time
+	LastError       string
+	CreationTime    time.Time
+}
+
+// Event ...
+type Event struct {
+	TaskID       string
+	State        string
+	Time         time.Time
+	Name         string
+	AccountID    string
+	UserID       string
+	SubmissionID string
+	RunID        string
+}
@@ -0,0 +1,15 @@
+package query
+
+// Queries ...
+type Queries struct {
+	List            ListHandler
+	ListDeadLetters ListDeadLettersHandler
+}
+
+// NewQueries ...
+func NewQueries(readModel ReadModel) *Queries {
+	return &Queries{
+		List:            NewListHandler(readModel),
+		ListDeadLetters: NewListDeadLettersHandler(readModel),
+	}
+}
@@ -0,0 +1,10 @@
+package query
+
+import "context"
+
+// ReadModel ...
+type ReadModel interface {
+	List(ctx context.Context, filter *ListFilter) ([]*Webhook, error)
+	// ListDeadLetters lists the latest DEAD deliveries
+	ListDeadLetters(ctx context.Context, limit int, filter *ListDeadLettersFilter) ([]*Delivery, error)
+}
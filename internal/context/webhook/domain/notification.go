@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+// Notification is a task notification to be dispatched to webhooks
+type Notification struct {
+	ID          int64
+	Event       *Event
+	CallbackURL string
+	// Attempts is how many times the notification failed to be dispatched
+	Attempts int
+	// Err is why the notification is not read completely, it fails the dispatch of the notification
+	Err error
+}
+
+// NotificationSource provides task notifications in the order of occurrence
+type NotificationSource interface {
+	List(ctx context.Context, limit int) ([]*Notification, error)
+	// Ack removes the dispatched notifications
+	Ack(ctx context.Context, ids []int64) error
+	// Fail counts a failed attempt of dispatching the notifications, which are listed again later
+	Fail(ctx context.Context, ids []int64) error
+}
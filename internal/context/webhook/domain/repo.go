@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Repo ...
+type Repo interface {
+	Get(ctx context.Context, id string) (*Webhook, error)
+	Save(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, id string) error
+	ListByAccount(ctx context.Context, accountID string) ([]*Webhook, error)
+	// CreateDeliveries ignores the delivery whose notification and webhook already exist
+	CreateDeliveries(ctx context.Context, deliveries []*Delivery) error
+	// ListDueDeliveries lists PENDING deliveries whose next attempt time is not after the time
+	ListDueDeliveries(ctx context.Context, before time.Time, limit int) ([]*Delivery, error)
+	// UpdateDelivery updates the delivery if its attempts is not changed,
+	// it returns false if the delivery is updated by others
+	UpdateDelivery(ctx context.Context, delivery *Delivery, oldAttempts int) (bool, error)
+	// DeleteDeliveredDeliveries deletes DELIVERED deliveries delivered before the time and returns how many are deleted
+	DeleteDeliveredDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
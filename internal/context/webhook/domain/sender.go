@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// Sender sends the signed event to the URL
+type Sender interface {
+	// Send signs the payload with the default secret if secret is empty
+	Send(ctx context.Context, url, secret string, event *Event) error
+}
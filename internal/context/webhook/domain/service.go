@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+)
+
+const (
+	dispatchBatchSize = 100
+	deliverBatchSize  = 100
+	// maxDispatchAttempts is how many times a notification fails to be dispatched before it is dropped
+	maxDispatchAttempts = 10
+)
+
+// timeNow is replaceable in tests
+var timeNow = time.Now
+
+// Service ...
+type Service interface {
+	Put(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, id string) error
+	// Dispatch turns task notifications into deliveries to the callback URLs of tasks
+	// and webhooks of their accounts
+	Dispatch(ctx context.Context) error
+	// Deliver sends due deliveries, failed ones are retried by the policy
+	Deliver(ctx context.Context, policy *RetryPolicy) error
+	// Retain deletes deliveries delivered longer than the age ago, and returns how many are deleted
+	Retain(ctx context.Context, age time.Duration) (int64, error)
+}
+
+type service struct {
+	repo   Repo
+	source NotificationSource
+	sender Sender
+}
+
+var _ Service = (*service)(nil)
+
+// NewService ...
+func NewService(repo Repo, source NotificationSource, sender Sender) Service {
+	return &service{repo: repo, source: source, sender: sender}
+}
+
+// Put ...
+func (s *service) Put(ctx context.Context, webhook *Webhook) error {
+	return s.repo.Save(ctx, webhook)
+}
+
+// Delete ...
+func (s *service) Delete(ctx context.Context, id string) error {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Dispatch ...
+func (s *service) Dispatch(ctx context.Context) error {
+	notifications, err := s.source.List(ctx, dispatchBatchSize)
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+	now := timeNow().UTC().Truncate(time.Second)
+	ackIDs := make([]int64, 0, len(notifications))
+	failedIDs := make([]int64, 0)
+	deliveries := make([]*Delivery, 0)
+	accountWebhooks := make(map[string][]*Webhook)
+	for _, notification := range notifications {
+		notificationDeliveries, err := s.deliveriesOf(ctx, notification, accountWebhooks, now)
+		if err != nil {
+			if notification.Attempts+1 < maxDispatchAttempts {
+				applog.CtxErrorw(ctx, "failed to dispatch task notification", "id", notification.ID,
+					"task", notification.Event.TaskID, "err", err)
+				failedIDs = append(failedIDs, notification.ID)
+				continue
+			}
+			// the notification is dropped, so that the outbox is not blocked by it
+			applog.CtxErrorw(ctx, "drop task notification failed to be dispatched too many times", "id", notification.ID,
+				"task", notification.Event.TaskID, "err", err)
+		}
+		ackIDs = append(ackIDs, notification.ID)
+		deliveries = append(deliveries, notificationDeliveries...)
+	}
+	if len(deliveries) > 0 {
+		if err = s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+			return err
+		}
+	}
+	if len(failedIDs) > 0 {
+		if err = s.source.Fail(ctx, failedIDs); err != nil {
+			return err
+		}
+	}
+	return s.source.Ack(ctx, ackIDs)
+}
+
+// deliveriesOf returns deliveries to the callback URL of the task and webhooks of its account,
+// webhooks of accounts are cached in accountWebhooks
+func (s *service) deliveriesOf(ctx context.Context, notification *Notification, accountWebhooks map[string][]*Webhook,
+	now time.Time) ([]*Delivery, error) {
+	if notification.Err != nil {
+		return nil, notification.Err
+	}
+	res := make([]*Delivery, 0)
+	if notification.CallbackURL != "" {
+		res = append(res, newDelivery(notification, "", notification.CallbackURL, now))
+	}
+	accountID := notification.Event.AccountID
+	if accountID == "" {
+		return res, nil
+	}
+	webhooks, ok := accountWebhooks[accountID]
+	if !ok {
+		var err error
+		if webhooks, err = s.repo.ListByAccount(ctx, accountID); err != nil {
+			return nil, err
+		}
+		accountWebhooks[accountID] = webhooks
+	}
+	for _, webhook := range webhooks {
+		res = append(res, newDelivery(notification, webhook.ID, webhook.URL, now))
+	}
+	return res, nil
+}
+
+func newDelivery(notification *Notification, webhookID, url string, now time.Time) *Delivery {
+	return &Delivery{
+		NotificationID:  notification.ID,
+		WebhookID:       webhookID,
+		URL:             url,
+		Event:           notification.Event,
+		State:           consts.DeliveryPending,
+		NextAttemptTime: now,
+		CreationTime:    now,
+	}
+}
+
+// Deliver ...
+func (s *service) Deliver(ctx context.Context, policy *RetryPolicy) error {
+	now := timeNow().UTC().Truncate(time.Second)
+	deliveries, err := s.repo.ListDueDeliveries(ctx, now, deliverBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err = s.deliver(ctx, delivery, policy, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) deliver(ctx context.Context, delivery *Delivery, policy *RetryPolicy, now time.Time) error {
+	var secret string
+	if delivery.WebhookID != "" {
+		webhook, err := s.repo.Get(ctx, delivery.WebhookID)
+		if apperrors.IsCode(err, apperrors.NotFoundCode) {
+			delivery.State = consts.DeliveryDead
+			delivery.LastError = "webhook is deleted"
+			_, err = s.repo.UpdateDelivery(ctx, delivery, delivery.Attempts)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		secret = webhook.Secret
+	}
+
+	// the attempt is taken before sending, so that the delivery is not sent by others meanwhile,
+	// and it is retried after backoff if the sending is interrupted
+	oldAttempts := delivery.Attempts
+	delivery.Attempts++
+	delivery.NextAttemptTime = now.Add(policy.backoff(delivery.Attempts))
+	if taken, err := s.repo.UpdateDelivery(ctx, delivery, oldAttempts); err != nil || !taken {
+		return err
+	}
+
+	sendErr := s.sender.Send(ctx, delivery.URL, secret, delivery.Event)
+	switch {
+	case sendErr == nil:
+		delivery.State = consts.DeliveryDelivered
+	case delivery.Attempts >= policy.MaxAttempts:
+		delivery.State = consts.DeliveryDead
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.LastError = sendErr.Error()
+	}
+	_, err := s.repo.UpdateDelivery(ctx, delivery, delivery.Attempts)
+	return err
+}
+
+// Retain ...
+func (s *service) Retain(ctx context.Context, age time.Duration) (int64, error) {
+	return s.repo.DeleteDeliveredDeliveries(ctx, timeNow().UTC().Truncate(time.Second).Add(-age))
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+var now = time.Now().UTC().Truncate(time.Second)
+
+func init() {
+	timeNow = func() time.Time { return now }
+}
+
+var webhook = &Webhook{
+	ID:        "webhook-01",
+	AccountID: "account-01",
+	URL:       "https://example.com/webhook",
+	Secret:    "secret",
+}
+
+var event = &Event{
+	TaskID:    "task-01",
+	State:     "COMPLETE",
+	Time:      now,
+	AccountID: "account-01",
+	UserID:    "user-01",
+}
+
+var policy = &RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  10 * time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+func TestPut(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().Save(gomock.Any(), webhook).Return(nil)
+
+	svc := NewService(fakeRepo, nil, nil)
+	err := svc.Put(context.TODO(), webhook)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestDelete(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().Get(gomock.Any(), webhook.ID).Return(nil, apperrors.NewNotFoundError("webhook", webhook.ID))
+
+	svc := NewService(fakeRepo, nil, nil)
+	err := svc.Delete(context.TODO(), webhook.ID)
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
+
+func TestDispatch(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	noAccountEvent := *event
+	noAccountEvent.AccountID = ""
+	fakeSource := NewFakeNotificationSource(ctrl)
+	fakeSource.EXPECT().List(gomock.Any(), dispatchBatchSize).Return([]*Notification{
+		{ID: 1, Event: event, CallbackURL: "https://example.com/callback"},
+		{ID: 2, Event: event},
+		{ID: 3, Event: &noAccountEvent},
+	}, nil)
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListByAccount(gomock.Any(), "account-01").Return([]*Webhook{webhook}, nil)
+	fakeRepo.EXPECT().CreateDeliveries(gomock.Any(), []*Delivery{
+		{NotificationID: 1, URL: "https://example.com/callback", Event: event, State: consts.DeliveryPending, NextAttemptTime: now, CreationTime: now},
+		{NotificationID: 1, WebhookID: webhook.ID, URL: webhook.URL, Event: event, State: consts.DeliveryPending, NextAttemptTime: now, CreationTime: now},
+		{NotificationID: 2, WebhookID: webhook.ID, URL: webhook.URL, Event: event, State: consts.DeliveryPending, NextAttemptTime: now, CreationTime: now},
+	}).Return(nil)
+	fakeSource.EXPECT().Ack(gomock.Any(), []int64{1, 2, 3}).Return(nil)
+
+	svc := NewService(fakeRepo, fakeSource, nil)
+	err := svc.Dispatch(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestDispatchFailed(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherAccountEvent := *event
+	otherAccountEvent.AccountID = "account-02"
+	fakeSource := NewFakeNotificationSource(ctrl)
+	fakeSource.EXPECT().List(gomock.Any(), dispatchBatchSize).Return([]*Notification{
+		{ID: 1, Event: &Event{TaskID: "task-01"}, Err: fmt.Errorf("db error")},
+		// dropped after too many failures
+		{ID: 2, Event: &Event{TaskID: "task-02"}, Err: fmt.Errorf("db error"), Attempts: maxDispatchAttempts - 1},
+		{ID: 3, Event: &otherAccountEvent},
+		{ID: 4, Event: event, CallbackURL: "https://example.com/callback"},
+	}, nil)
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListByAccount(gomock.Any(), "account-02").Return(nil, apperrors.NewInternalError(fmt.Errorf("db error")))
+	fakeRepo.EXPECT().ListByAccount(gomock.Any(), "account-01").Return(nil, nil)
+	fakeRepo.EXPECT().CreateDeliveries(gomock.Any(), []*Delivery{
+		{NotificationID: 4, URL: "https://example.com/callback", Event: event, State: consts.DeliveryPending, NextAttemptTime: now, CreationTime: now},
+	}).Return(nil)
+	fakeSource.EXPECT().Fail(gomock.Any(), []int64{1, 3}).Return(nil)
+	fakeSource.EXPECT().Ack(gomock.Any(), []int64{2, 4}).Return(nil)
+
+	svc := NewService(fakeRepo, fakeSource, nil)
+	err := svc.Dispatch(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestDeliver(t *testing.T) {
+	tests := []struct {
+		name       string
+		delivery   *Delivery
+		getWebhook bool
+		sendErr    error
+		exp        *Delivery
+	}{
+		{
+			name:     "callback delivered",
+			delivery: &Delivery{ID: 1, URL: "https://example.com/callback", Event: event, State: consts.DeliveryPending},
+			exp: &Delivery{ID: 1, URL: "https://example.com/callback", Event: event, State: consts.DeliveryDelivered,
+				Attempts: 1, NextAttemptTime: now.Add(10 * time.Second)},
+		},
+		{
+			name:       "webhook retried",
+			delivery:   &Delivery{ID: 1, WebhookID: webhook.ID, URL: webhook.URL, Event: event, State: consts.DeliveryPending, Attempts: 1},
+			getWebhook: true,
+			sendErr:    fmt.Errorf("status code 500"),
+			exp: &Delivery{ID: 1, WebhookID: webhook.ID, URL: webhook.URL, Event: event, State: consts.DeliveryPending,
+				Attempts: 2, NextAttemptTime: now.Add(20 * time.Second), LastError: "status code 500"},
+		},
+		{
+			name:       "webhook dead",
+			delivery:   &Delivery{ID: 1, WebhookID: webhook.ID, URL: webhook.URL, Event: event, State: consts.DeliveryPending, Attempts: 2},
+			getWebhook: true,
+			sendErr:    fmt.Errorf("status code 500"),
+			exp: &Delivery{ID: 1, WebhookID: webhook.ID, URL: webhook.URL, Event: event, State: consts.DeliveryDead,
+				Attempts: 3, NextAttemptTime: now.Add(30 * time.Second), LastError: "status code 500"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeRepo := NewFakeRepo(ctrl)
+			fakeSender := NewFakeSender(ctrl)
+			fakeRepo.EXPECT().ListDueDeliveries(gomock.Any(), now, deliverBatchSize).Return([]*Delivery{test.delivery}, nil)
+			var secret string
+			if test.getWebhook {
+				fakeRepo.EXPECT().Get(gomock.Any(), webhook.ID).Return(webhook, nil)
+				secret = webhook.Secret
+			}
+			oldAttempts := test.delivery.Attempts
+			fakeRepo.EXPECT().UpdateDelivery(gomock.Any(), test.delivery, oldAttempts).Return(true, nil)
+			fakeSender.EXPECT().Send(gomock.Any(), test.delivery.URL, secret, event).Return(test.sendErr)
+			fakeRepo.EXPECT().UpdateDelivery(gomock.Any(), test.delivery, oldAttempts+1).Return(true, nil)
+
+			svc := NewService(fakeRepo, nil, fakeSender)
+			err := svc.Deliver(context.TODO(), policy)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(test.delivery).To(gomega.Equal(test.exp))
+		})
+	}
+}
+
+func TestDeliverTakenByOthers(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	delivery := &Delivery{ID: 1, URL: "https://example.com/callback", Event: event, State: consts.DeliveryPending}
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().ListDueDeliveries(gomock.Any(), now, deliverBatchSize).Return([]*Delivery{delivery}, nil)
+	fakeRepo.EXPECT().UpdateDelivery(gomock.Any(), delivery, 0).Return(false, nil)
+
+	svc := NewService(fakeRepo, nil, NewFakeSender(ctrl))
+	err := svc.Deliver(context.TODO(), policy)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestRetain(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRepo := NewFakeRepo(ctrl)
+	fakeRepo.EXPECT().DeleteDeliveredDeliveries(gomock.Any(), now.Add(-time.Hour)).Return(int64(2), nil)
+
+	svc := NewService(fakeRepo, nil, nil)
+	count, err := svc.Retain(context.TODO(), time.Hour)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(count).To(gomega.BeEquivalentTo(2))
+}
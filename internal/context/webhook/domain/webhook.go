@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// Webhook is a subscription of task notifications of an account
+type Webhook struct {
+	ID        string
+	AccountID string
+	URL       string
+	// Secret signs the payload, the default secret is used if empty
+	Secret string
+}
+
+// Event is the content of a notification, it is sent as the payload
+type Event struct {
+	TaskID       string
+	State        string
+	Time         time.Time
+	Name         string
+	AccountID    string
+	UserID       string
+	SubmissionID string
+	RunID        string
+}
+
+// Delivery is an event to be sent to a URL, it is retried with backoff until delivered
+// or the attempts are exhausted, then it is dead
+type Delivery struct {
+	ID             int64
+	NotificationID int64
+	// WebhookID is empty if the URL is the callback URL of the task
+	WebhookID       string
+	URL             string
+	Event           *Event
+	State           string
+	Attempts        int
+	NextAttemptTime time.Time
+	LastError       string
+	CreationTime    time.Time
+}
+
+// RetryPolicy ...
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// backoff returns the delay before the next attempt after attempts failed
+func (p *RetryPolicy) backoff(attempts int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
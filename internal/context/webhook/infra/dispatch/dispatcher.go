@@ -0,0 +1,43 @@
+package dispatch
+
+import (
+	"context"
+	"time"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+)
+
+// Dispatcher dispatches task notifications and delivers them periodically
+type Dispatcher struct {
+	opts *Options
+	svc  domain.Service
+}
+
+// NewDispatcher ...
+func NewDispatcher(opts *Options, svc domain.Service) *Dispatcher {
+	return &Dispatcher{opts: opts, svc: svc}
+}
+
+// Run blocks until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	if !d.opts.Enable {
+		return
+	}
+	policy := d.opts.RetryPolicy()
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.svc.Dispatch(ctx); err != nil {
+				applog.Errorw("failed to dispatch task notifications", "err", err)
+			}
+			if err := d.svc.Deliver(ctx, policy); err != nil {
+				applog.Errorw("failed to deliver task notifications", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package dispatch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+)
+
+// Options ...
+type Options struct {
+	Enable   bool          `mapstructure:"enable"`
+	Interval time.Duration `mapstructure:"interval"`
+	// Secret signs payloads of callback URLs and webhooks without their own secret
+	Secret      string        `mapstructure:"secret"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+	MaxAttempts int           `mapstructure:"maxAttempts"`
+	MinBackoff  time.Duration `mapstructure:"minBackoff"`
+	MaxBackoff  time.Duration `mapstructure:"maxBackoff"`
+	// AllowedDestinations are host names, wildcards like "*.example.com", IPs or CIDRs that payloads can be sent to,
+	// only public addresses are allowed if empty
+	AllowedDestinations []string `mapstructure:"allowedDestinations"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Enable:      false,
+		Interval:    5 * time.Second,
+		Timeout:     10 * time.Second,
+		MaxAttempts: 8,
+		MinBackoff:  10 * time.Second,
+		MaxBackoff:  time.Hour,
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if o.Enable && o.Secret == "" {
+		return fmt.Errorf("webhook secret should not be empty when webhook is enabled")
+	}
+	if o.Interval <= 0 {
+		return fmt.Errorf("webhook interval should be positive")
+	}
+	if o.Timeout <= 0 {
+		return fmt.Errorf("webhook timeout should be positive")
+	}
+	if o.MaxAttempts <= 0 {
+		return fmt.Errorf("webhook maxAttempts should be positive")
+	}
+	if o.MinBackoff <= 0 || o.MaxBackoff < o.MinBackoff {
+		return fmt.Errorf("webhook minBackoff should be positive and not greater than maxBackoff")
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enable, "webhook-enable", o.Enable, "enable sending task notifications to callback URLs and webhooks")
+	fs.DurationVar(&o.Interval, "webhook-interval", o.Interval, "interval of dispatching and delivering task notifications")
+	fs.StringVar(&o.Secret, "webhook-secret", o.Secret, "default secret to sign payloads")
+	fs.DurationVar(&o.Timeout, "webhook-timeout", o.Timeout, "timeout of each delivery")
+	fs.IntVar(&o.MaxAttempts, "webhook-max-attempts", o.MaxAttempts, "delivery is dead after the attempts")
+	fs.DurationVar(&o.MinBackoff, "webhook-min-backoff", o.MinBackoff, "backoff after the first failed attempt, doubled after each failure")
+	fs.DurationVar(&o.MaxBackoff, "webhook-max-backoff", o.MaxBackoff, "max backoff between attempts")
+	fs.StringSliceVar(&o.AllowedDestinations, "webhook-allowed-destinations", o.AllowedDestinations, "host names, wildcards, IPs or CIDRs that payloads can be sent to, only public addresses are allowed if empty")
+}
+
+// RetryPolicy ...
+func (o *Options) RetryPolicy() *domain.RetryPolicy {
+	return &domain.RetryPolicy{
+		MaxAttempts: o.MaxAttempts,
+		MinBackoff:  o.MinBackoff,
+		MaxBackoff:  o.MaxBackoff,
+	}
+}
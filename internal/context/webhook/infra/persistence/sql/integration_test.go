@@ -0,0 +1,59 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	"github.com/GBA-BI/tes-api/internal/migrations"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+)
+
+func TestIntegration(t *testing.T) {
+	g := gomega.NewWithT(t)
+	gormDB := testutil.NewSQLiteDB()
+	migrator, err := migrations.NewMigrator(gormDB, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(migrator.Up(context.TODO(), 0)).To(gomega.Succeed())
+	r, err := NewRepo(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	rm, err := NewReadModel(context.TODO(), gormDB)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(r.Save(context.TODO(), webhookDO)).To(gomega.Succeed())
+	webhooks, err := r.ListByAccount(context.TODO(), "account-01")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(webhooks).To(gomega.BeEquivalentTo([]*domain.Webhook{webhookDO}))
+
+	delivery := *deliveryDO
+	delivery.ID = 0
+	g.Expect(r.CreateDeliveries(context.TODO(), []*domain.Delivery{&delivery})).To(gomega.Succeed())
+	// the notification is dispatched again
+	g.Expect(r.CreateDeliveries(context.TODO(), []*domain.Delivery{&delivery})).To(gomega.Succeed())
+	deliveries, err := r.ListDueDeliveries(context.TODO(), now.Add(time.Second), 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(deliveries).To(gomega.BeEquivalentTo([]*domain.Delivery{deliveryDO}))
+
+	dead := *deliveryDO
+	dead.State = consts.DeliveryDead
+	dead.Attempts = 2
+	updated, err := r.UpdateDelivery(context.TODO(), &dead, deliveryDO.Attempts)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeTrue())
+	// attempts are changed by another replica
+	updated, err = r.UpdateDelivery(context.TODO(), &dead, deliveryDO.Attempts)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeFalse())
+
+	deadLetters, err := rm.ListDeadLetters(context.TODO(), 10, &query.ListDeadLettersFilter{WebhookID: id})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(deadLetters).To(gomega.HaveLen(1))
+	g.Expect(deadLetters[0].State).To(gomega.Equal(consts.DeliveryDead))
+	g.Expect(deadLetters[0].Attempts).To(gomega.Equal(2))
+}
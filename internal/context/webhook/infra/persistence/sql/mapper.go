@@ -0,0 +1,146 @@
+package sql
+
+import (
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/query"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+)
+
+func (w *Webhook) toDO() *domain.Webhook {
+	if w == nil {
+		return nil
+	}
+	return &domain.Webhook{
+		ID:        w.ID,
+		AccountID: w.AccountID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+	}
+}
+
+func (w *Webhook) toDTO() *query.Webhook {
+	if w == nil {
+		return nil
+	}
+	return &query.Webhook{
+		ID:        w.ID,
+		AccountID: w.AccountID,
+		URL:       w.URL,
+	}
+}
+
+func webhookDOToPO(webhook *domain.Webhook) *Webhook {
+	if webhook == nil {
+		return nil
+	}
+	return &Webhook{
+		ID:        webhook.ID,
+		AccountID: webhook.AccountID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+	}
+}
+
+func (d *WebhookDelivery) toDO() *domain.Delivery {
+	if d == nil {
+		return nil
+	}
+	return &domain.Delivery{
+		ID:              d.ID,
+		NotificationID:  d.NotificationID,
+		WebhookID:       d.WebhookID,
+		URL:             d.URL,
+		Event:           d.Event.toDO(),
+		State:           d.State,
+		Attempts:        d.Attempts,
+		NextAttemptTime: d.NextAttemptTime,
+		LastError:       d.LastError,
+		CreationTime:    d.CreationTime,
+	}
+}
+
+func (d *WebhookDelivery) toDTO() *query.Delivery {
+	if d == nil {
+		return nil
+	}
+	return &query.Delivery{
+		ID:              d.ID,
+		WebhookID:       d.WebhookID,
+		URL:             d.URL,
+		Event:           d.Event.toDTO(),
+		State:           d.State,
+		Attempts:        d.Attempts,
+		NextAttemptTime: d.NextAttemptTime,
+		LastError:       d.LastError,
+		CreationTime:    d.CreationTime,
+	}
+}
+
+func deliveryDOToPO(delivery *domain.Delivery) *WebhookDelivery {
+	if delivery == nil {
+		return nil
+	}
+	res := &WebhookDelivery{
+		ID:              delivery.ID,
+		NotificationID:  delivery.NotificationID,
+		WebhookID:       delivery.WebhookID,
+		URL:             delivery.URL,
+		Event:           eventDOToPO(delivery.Event),
+		State:           delivery.State,
+		Attempts:        delivery.Attempts,
+		NextAttemptTime: delivery.NextAttemptTime,
+		LastError:       delivery.LastError,
+		CreationTime:    delivery.CreationTime,
+	}
+	if delivery.Event != nil {
+		res.AccountID = delivery.Event.AccountID
+	}
+	return res
+}
+
+func (e *Event) toDO() *domain.Event {
+	if e == nil {
+		return nil
+	}
+	return &domain.Event{
+		TaskID:       e.TaskID,
+		State:        e.State,
+		Time:         e.Time,
+		Name:         e.Name,
+		AccountID:    e.AccountID,
+		UserID:       e.UserID,
+		SubmissionID: e.SubmissionID,
+		RunID:        e.RunID,
+	}
+}
+
+func (e *Event) toDTO() *query.Event {
+	if e == nil {
+		return nil
+	}
+	return &query.Event{
+		TaskID:       e.TaskID,
+		State:        e.State,
+		Time:         e.Time,
+		Name:         e.Name,
+		AccountID:    e.AccountID,
+		UserID:       e.UserID,
+		SubmissionID: e.SubmissionID,
+		RunID:        e.RunID,
+	}
+}
+
+func eventDOToPO(event *domain.Event) *Event {
+	if event == nil {
+		return nil
+	}
+	return &Event{
+		TaskID:       event.TaskID,
+		State:        event.State,
+		Time:         event.Time,
+		Name:         event.Name,
+		AccountID:    event.AccountID,
+		UserID:       event.UserID,
+		SubmissionID: event.SubmissionID,
+		RunID:        event.RunID,
+	}
+}
@@ -0,0 +1,49 @@
+package sql
+
+import "time"
+
+// Webhook ...
+type Webhook struct {
+	ID        string `gorm:"column:id;type:VARCHAR(32);not null;primaryKey"`
+	AccountID string `gorm:"column:account_id;type:VARCHAR(32);not null;index:account_id"`
+	URL       string `gorm:"column:url;type:VARCHAR(1024);not null"`
+	Secret    string `gorm:"column:secret;type:VARCHAR(256);not null;default:''"`
+}
+
+// TableName ...
+func (w *Webhook) TableName() string {
+	return "webhook"
+}
+
+// WebhookDelivery ...
+type WebhookDelivery struct {
+	ID             int64  `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement"`
+	NotificationID int64  `gorm:"column:notification_id;type:BIGINT;not null;uniqueIndex:notification_webhook,priority:1"`
+	WebhookID      string `gorm:"column:webhook_id;type:VARCHAR(32);not null;default:'';uniqueIndex:notification_webhook,priority:2"`
+	URL            string `gorm:"column:url;type:VARCHAR(1024);not null"`
+	Event          *Event `gorm:"column:event;type:LONGTEXT;serializer:json"`
+	// AccountID is copied from Event for filtering
+	AccountID       string    `gorm:"column:account_id;type:VARCHAR(32);not null;default:'';index:state_account,priority:2"`
+	State           string    `gorm:"column:state;type:VARCHAR(16);not null;index:state_next_attempt_time,priority:1;index:state_account,priority:1"`
+	Attempts        int       `gorm:"column:attempts;type:INT;not null;default:0"`
+	NextAttemptTime time.Time `gorm:"column:next_attempt_time;type:DATETIME;not null;index:state_next_attempt_time,priority:2"`
+	LastError       string    `gorm:"column:last_error;type:TEXT"`
+	CreationTime    time.Time `gorm:"column:creation_time;type:DATETIME;not null"`
+}
+
+// Event ...
+type Event struct {
+	TaskID       string    `json:"task_id"`
+	State        string    `json:"state"`
+	Time         time.Time `json:"time"`
+	Name         string    `json:"name,omitempty"`
+	AccountID    string    `json:"account_id,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+	SubmissionID string    `json:"submission_id,omitempty"`
+	RunID        string    `json:"run_id,omitempty"`
+}
+
+// TableName ...
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
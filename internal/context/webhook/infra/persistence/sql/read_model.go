@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"context"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/query"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+type readModel struct {
+	db *gorm.DB
+}
+
+// NewReadModel ...
+func NewReadModel(ctx context.Context, db *gorm.DB) (query.ReadModel, error) {
+	return &readModel{db: db}, nil
+}
+
+var _ query.ReadModel = (*readModel)(nil)
+
+// List ...
+func (r *readModel) List(ctx context.Context, filter *query.ListFilter) ([]*query.Webhook, error) {
+	db := r.db.WithContext(ctx).Model(&Webhook{})
+	if filter != nil && filter.AccountID != "" {
+		db = db.Where("account_id = ?", filter.AccountID)
+	}
+	webhooks := make([]*Webhook, 0)
+	if err := db.Order("id").Find(&webhooks).Error; err != nil {
+		applog.Errorw("failed to list webhooks", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*query.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		res = append(res, webhook.toDTO())
+	}
+	return res, nil
+}
+
+// ListDeadLetters ...
+func (r *readModel) ListDeadLetters(ctx context.Context, limit int, filter *query.ListDeadLettersFilter) ([]*query.Delivery, error) {
+	db := r.db.WithContext(ctx).Model(&WebhookDelivery{}).Where("state = ?", consts.DeliveryDead)
+	if filter != nil {
+		if filter.AccountID != "" {
+			db = db.Where("account_id = ?", filter.AccountID)
+		}
+		if filter.WebhookID != "" {
+			db = db.Where("webhook_id = ?", filter.WebhookID)
+		}
+	}
+	deliveries := make([]*WebhookDelivery, 0)
+	if err := db.Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		applog.Errorw("failed to list webhook dead letters", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*query.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, delivery.toDTO())
+	}
+	return res, nil
+}
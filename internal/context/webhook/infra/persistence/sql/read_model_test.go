@@ -0,0 +1,56 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/query"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+)
+
+func TestList(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `webhook` WHERE account_id = ? ORDER BY id").WithArgs("account-01").
+		WillReturnRows(sqlmock.NewRows(rows).AddRow(webhookPO.ID, webhookPO.AccountID, webhookPO.URL, webhookPO.Secret))
+	resp, err := r.List(context.TODO(), &query.ListFilter{AccountID: "account-01"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.Webhook{{
+		ID:        id,
+		AccountID: "account-01",
+		URL:       "https://example.com/webhook",
+	}}))
+}
+
+func TestListDeadLetters(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &readModel{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `webhook_delivery` WHERE state = ? AND account_id = ? ORDER BY id DESC LIMIT 10").
+		WithArgs(consts.DeliveryDead, "account-01").
+		WillReturnRows(deliveryPORow())
+	resp, err := r.ListDeadLetters(context.TODO(), 10, &query.ListDeadLettersFilter{AccountID: "account-01"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*query.Delivery{{
+		ID:        1,
+		WebhookID: id,
+		URL:       "https://example.com/webhook",
+		Event: &query.Event{
+			TaskID:    "task-01",
+			State:     consts.TaskComplete,
+			Time:      now,
+			AccountID: "account-01",
+			UserID:    "user-01",
+		},
+		State:           consts.DeliveryPending,
+		Attempts:        1,
+		NextAttemptTime: now,
+		LastError:       "status code 500",
+		CreationTime:    now,
+	}}))
+}
@@ -0,0 +1,130 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+// NewRepo ...
+func NewRepo(ctx context.Context, db *gorm.DB) (domain.Repo, error) {
+	return &repo{db: db}, nil
+}
+
+var _ domain.Repo = (*repo)(nil)
+
+// Get ...
+func (r *repo) Get(ctx context.Context, id string) (*domain.Webhook, error) {
+	var webhook Webhook
+	if err := r.db.WithContext(ctx).Model(&Webhook{}).Where("id = ?", id).First(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("webhook", id)
+		}
+		applog.Errorw("failed to get webhook", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	return webhook.toDO(), nil
+}
+
+// Save ...
+func (r *repo) Save(ctx context.Context, webhook *domain.Webhook) error {
+	webhookPO := webhookDOToPO(webhook)
+	if err := r.db.WithContext(ctx).Model(&Webhook{}).Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(webhookPO).Error; err != nil {
+		applog.Errorw("failed to save webhook", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// Delete ...
+func (r *repo) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Model(&Webhook{}).Where("id = ?", id).Delete(&Webhook{}).Error; err != nil {
+		applog.Errorw("failed to delete webhook", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// ListByAccount ...
+func (r *repo) ListByAccount(ctx context.Context, accountID string) ([]*domain.Webhook, error) {
+	var webhooks []*Webhook
+	if err := r.db.WithContext(ctx).Model(&Webhook{}).Where("account_id = ?", accountID).
+		Find(&webhooks).Error; err != nil {
+		applog.Errorw("failed to list webhooks", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		res = append(res, webhook.toDO())
+	}
+	return res, nil
+}
+
+// CreateDeliveries ...
+func (r *repo) CreateDeliveries(ctx context.Context, deliveries []*domain.Delivery) error {
+	deliveryPOs := make([]*WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryPOs = append(deliveryPOs, deliveryDOToPO(delivery))
+	}
+	// the notification may be dispatched again if it failed to be acked
+	if err := r.db.WithContext(ctx).Model(&WebhookDelivery{}).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveryPOs).Error; err != nil {
+		applog.Errorw("failed to create webhook deliveries", "err", err)
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+// ListDueDeliveries ...
+func (r *repo) ListDueDeliveries(ctx context.Context, before time.Time, limit int) ([]*domain.Delivery, error) {
+	var deliveries []*WebhookDelivery
+	if err := r.db.WithContext(ctx).Model(&WebhookDelivery{}).
+		Where("state = ? AND next_attempt_time <= ?", consts.DeliveryPending, before).
+		Order("next_attempt_time").Limit(limit).Find(&deliveries).Error; err != nil {
+		applog.Errorw("failed to list due webhook deliveries", "err", err)
+		return nil, apperrors.NewInternalError(err)
+	}
+	res := make([]*domain.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, delivery.toDO())
+	}
+	return res, nil
+}
+
+// UpdateDelivery ...
+func (r *repo) UpdateDelivery(ctx context.Context, delivery *domain.Delivery, oldAttempts int) (bool, error) {
+	deliveryPO := deliveryDOToPO(delivery)
+	res := r.db.WithContext(ctx).Model(&WebhookDelivery{}).
+		Where("id = ? AND attempts = ?", deliveryPO.ID, oldAttempts).
+		Select("state", "attempts", "next_attempt_time", "last_error").Updates(deliveryPO)
+	if res.Error != nil {
+		applog.Errorw("failed to update webhook delivery", "err", res.Error)
+		return false, apperrors.NewInternalError(res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// DeleteDeliveredDeliveries deletes by next_attempt_time, which is set just before the delivery is sent
+func (r *repo) DeleteDeliveredDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("state = ? AND next_attempt_time < ?", consts.DeliveryDelivered, before).
+		Delete(&WebhookDelivery{})
+	if res.Error != nil {
+		applog.Errorw("failed to delete delivered webhook deliveries", "err", res.Error)
+		return 0, apperrors.NewInternalError(res.Error)
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,184 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+)
+
+var id = "webhook-01"
+var now = time.Now().UTC().Truncate(time.Second)
+
+var webhookPO = &Webhook{
+	ID:        id,
+	AccountID: "account-01",
+	URL:       "https://example.com/webhook",
+	Secret:    "secret",
+}
+
+var webhookDO = &domain.Webhook{
+	ID:        id,
+	AccountID: "account-01",
+	URL:       "https://example.com/webhook",
+	Secret:    "secret",
+}
+
+var rows = []string{"id", "account_id", "url", "secret"}
+
+var deliveryPO = &WebhookDelivery{
+	ID:             1,
+	NotificationID: 10,
+	WebhookID:      id,
+	URL:            "https://example.com/webhook",
+	Event: &Event{
+		TaskID:    "task-01",
+		State:     consts.TaskComplete,
+		Time:      now,
+		AccountID: "account-01",
+		UserID:    "user-01",
+	},
+	AccountID:       "account-01",
+	State:           consts.DeliveryPending,
+	Attempts:        1,
+	NextAttemptTime: now,
+	LastError:       "status code 500",
+	CreationTime:    now,
+}
+
+var deliveryDO = &domain.Delivery{
+	ID:             1,
+	NotificationID: 10,
+	WebhookID:      id,
+	URL:            "https://example.com/webhook",
+	Event: &domain.Event{
+		TaskID:    "task-01",
+		State:     consts.TaskComplete,
+		Time:      now,
+		AccountID: "account-01",
+		UserID:    "user-01",
+	},
+	State:           consts.DeliveryPending,
+	Attempts:        1,
+	NextAttemptTime: now,
+	LastError:       "status code 500",
+	CreationTime:    now,
+}
+
+var deliveryRows = []string{"id", "notification_id", "webhook_id", "url", "event", "account_id", "state",
+	"attempts", "next_attempt_time", "last_error", "creation_time"}
+
+func deliveryPORow() *sqlmock.Rows {
+	return sqlmock.NewRows(deliveryRows).AddRow(deliveryPO.ID, deliveryPO.NotificationID, deliveryPO.WebhookID,
+		deliveryPO.URL, testutil.MustJSONMarshal(deliveryPO.Event), deliveryPO.AccountID, deliveryPO.State,
+		deliveryPO.Attempts, deliveryPO.NextAttemptTime, deliveryPO.LastError, deliveryPO.CreationTime)
+}
+
+func TestGet(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `webhook` WHERE id = ? ORDER BY `webhook`.`id` LIMIT 1").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(rows).AddRow(webhookPO.ID, webhookPO.AccountID, webhookPO.URL, webhookPO.Secret))
+	resp, err := r.Get(context.TODO(), id)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo(webhookDO))
+}
+
+func TestGetNotFound(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `webhook` WHERE id = ? ORDER BY `webhook`.`id` LIMIT 1").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(rows))
+	_, err := r.Get(context.TODO(), id)
+	g.Expect(apperrors.IsCode(err, apperrors.NotFoundCode)).To(gomega.BeTrue())
+}
+
+func TestSave(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectExec(fmt.Sprintf("INSERT INTO `webhook` %s %s", testutil.GenInsertSql(rows), testutil.GenDuplicateKeySql(rows[:1], rows[1:]))).
+		WithArgs(webhookPO.ID, webhookPO.AccountID, webhookPO.URL, webhookPO.Secret).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	err := r.Save(context.TODO(), webhookDO)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestListByAccount(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `webhook` WHERE account_id = ?").WithArgs("account-01").
+		WillReturnRows(sqlmock.NewRows(rows).AddRow(webhookPO.ID, webhookPO.AccountID, webhookPO.URL, webhookPO.Secret))
+	resp, err := r.ListByAccount(context.TODO(), "account-01")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.Webhook{webhookDO}))
+}
+
+func TestCreateDeliveries(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	delivery := *deliveryDO
+	delivery.ID = 0
+	mock.ExpectBegin()
+	testutil.ExpectInsertWithID(mock, fmt.Sprintf("INSERT INTO `webhook_delivery` %s %s", testutil.GenInsertSql(deliveryRows[1:] /*without id*/), testutil.GenDoNothingSql("id")), 1,
+		deliveryPO.NotificationID, deliveryPO.WebhookID, deliveryPO.URL, testutil.MustJSONMarshal(deliveryPO.Event),
+		deliveryPO.AccountID, deliveryPO.State, deliveryPO.Attempts, deliveryPO.NextAttemptTime, deliveryPO.LastError,
+		deliveryPO.CreationTime)
+	mock.ExpectCommit()
+	err := r.CreateDeliveries(context.TODO(), []*domain.Delivery{&delivery})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
+func TestListDueDeliveries(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectQuery("SELECT * FROM `webhook_delivery` WHERE state = ? AND next_attempt_time <= ? ORDER BY next_attempt_time LIMIT 10").
+		WithArgs(consts.DeliveryPending, now).
+		WillReturnRows(deliveryPORow())
+	resp, err := r.ListDueDeliveries(context.TODO(), now, 10)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(resp).To(gomega.BeEquivalentTo([]*domain.Delivery{deliveryDO}))
+}
+
+func TestUpdateDelivery(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `webhook_delivery` SET `state`=?,`attempts`=?,`next_attempt_time`=?,`last_error`=? WHERE id = ? AND attempts = ?").
+		WithArgs(deliveryPO.State, deliveryPO.Attempts, deliveryPO.NextAttemptTime, deliveryPO.LastError, deliveryPO.ID, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	updated, err := r.UpdateDelivery(context.TODO(), deliveryDO, 0)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(updated).To(gomega.BeFalse())
+}
+
+func TestDeleteDeliveredDeliveries(t *testing.T) {
+	g := gomega.NewWithT(t)
+	mock, gormDB := testutil.NewSqlMock()
+	r := &repo{db: gormDB}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `webhook_delivery` WHERE state = ? AND next_attempt_time < ?").
+		WithArgs(consts.DeliveryDelivered, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	count, err := r.DeleteDeliveredDeliveries(context.TODO(), now)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(count).To(gomega.BeEquivalentTo(2))
+}
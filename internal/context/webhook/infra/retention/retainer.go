@@ -0,0 +1,44 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	taskretention "github.com/GBA-BI/tes-api/internal/context/task/infra/retention"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+)
+
+// Retainer deletes delivered deliveries periodically, it follows the retention of tasks
+type Retainer struct {
+	opts *taskretention.Options
+	svc  domain.Service
+}
+
+// NewRetainer ...
+func NewRetainer(opts *taskretention.Options, svc domain.Service) *Retainer {
+	return &Retainer{opts: opts, svc: svc}
+}
+
+// Run blocks until ctx is done
+func (r *Retainer) Run(ctx context.Context) {
+	if !r.opts.Enable || r.opts.Age == 0 {
+		return
+	}
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := r.svc.Retain(ctx, r.opts.Age)
+			if err != nil {
+				applog.Errorw("failed to retain webhook deliveries", "err", err)
+			}
+			if count > 0 {
+				applog.Infow("delivered webhook deliveries are deleted by retention", "count", count)
+			}
+		}
+	}
+}
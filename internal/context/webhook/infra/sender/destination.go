@@ -0,0 +1,106 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// destinations restricts where payloads are sent to. Without any allowed destination, only public addresses are
+// allowed, otherwise only the allowed hosts and networks are.
+type destinations struct {
+	hosts    []string
+	networks []*net.IPNet
+}
+
+// newDestinations parses allowed destinations, each of which is a host name, a wildcard like "*.example.com", an IP
+// or a CIDR
+func newDestinations(allowed []string) (*destinations, error) {
+	d := &destinations{}
+	for _, item := range allowed {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			return nil, fmt.Errorf("empty webhook allowed destination")
+		}
+		if _, network, err := net.ParseCIDR(item); err == nil {
+			d.networks = append(d.networks, network)
+			continue
+		}
+		if ip := net.ParseIP(item); ip != nil {
+			d.networks = append(d.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		d.hosts = append(d.hosts, item)
+	}
+	return d, nil
+}
+
+func (d *destinations) restricted() bool {
+	return len(d.hosts) > 0 || len(d.networks) > 0
+}
+
+func (d *destinations) allowHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range d.hosts {
+		if host == allowed {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok && strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *destinations) allowIP(ip net.IP) bool {
+	if d.restricted() {
+		for _, network := range d.networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
+
+// checkURL checks the scheme of the URL, destination addresses are checked when dialing
+func (d *destinations) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q of webhook url", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("webhook url has no host")
+	}
+	return nil
+}
+
+// dialContext only dials allowed addresses. Host names are resolved here and the checked address is dialed, so the
+// check can not be bypassed by resolving to another address later.
+func (d *destinations) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if d.allowHost(host) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			if d.allowIP(ip.IP) {
+				return dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+			}
+		}
+		return nil, fmt.Errorf("webhook destination %s is not allowed", host)
+	}
+}
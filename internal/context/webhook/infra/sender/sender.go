@@ -0,0 +1,117 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+)
+
+const (
+	// TimestampHeader is the unix seconds when the payload is signed
+	TimestampHeader = "X-TES-Timestamp"
+	// SignatureHeader is "sha256=" followed by hex of HMAC-SHA256 of "{timestamp}.{body}"
+	SignatureHeader = "X-TES-Signature"
+)
+
+// payload is the JSON body sent to webhooks
+type payload struct {
+	TaskID       string `json:"task_id"`
+	State        string `json:"state"`
+	Time         string `json:"time"`
+	Name         string `json:"name,omitempty"`
+	AccountID    string `json:"account_id,omitempty"`
+	UserID       string `json:"user_id,omitempty"`
+	SubmissionID string `json:"submission_id,omitempty"`
+	RunID        string `json:"run_id,omitempty"`
+}
+
+type sender struct {
+	client        *http.Client
+	destinations  *destinations
+	defaultSecret string
+}
+
+// NewSender ...
+func NewSender(defaultSecret string, timeout time.Duration, allowedDestinations []string) (domain.Sender, error) {
+	d, err := newDestinations(allowedDestinations)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = d.dialContext(&net.Dialer{Timeout: timeout})
+	return &sender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			// redirects are not followed, so they are failures
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		destinations:  d,
+		defaultSecret: defaultSecret,
+	}, nil
+}
+
+var _ domain.Sender = (*sender)(nil)
+
+// Send ...
+func (s *sender) Send(ctx context.Context, url, secret string, event *domain.Event) error {
+	if err := s.destinations.checkURL(url); err != nil {
+		return err
+	}
+	body, err := json.Marshal(&payload{
+		TaskID:       event.TaskID,
+		State:        event.State,
+		Time:         event.Time.Format(time.RFC3339),
+		Name:         event.Name,
+		AccountID:    event.AccountID,
+		UserID:       event.UserID,
+		SubmissionID: event.SubmissionID,
+		RunID:        event.RunID,
+	})
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		secret = s.defaultSecret
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature of the body signed at the timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
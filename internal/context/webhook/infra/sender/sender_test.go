@@ -0,0 +1,148 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/domain"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+)
+
+var event = &domain.Event{
+	TaskID:    "task-01",
+	State:     consts.TaskComplete,
+	Time:      time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	AccountID: "account-01",
+}
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     string
+		statusCode int
+		expSecret  string
+		expErr     bool
+	}{
+		{
+			name:       "webhook secret",
+			secret:     "secret",
+			statusCode: http.StatusOK,
+			expSecret:  "secret",
+		},
+		{
+			name:       "default secret",
+			statusCode: http.StatusNoContent,
+			expSecret:  "default",
+		},
+		{
+			name:       "failed",
+			statusCode: http.StatusInternalServerError,
+			expSecret:  "default",
+			expErr:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				g.Expect(string(body)).To(gomega.Equal(
+					`{"task_id":"task-01","state":"COMPLETE","time":"2023-01-01T00:00:00Z","account_id":"account-01"}`))
+				g.Expect(r.Header.Get(SignatureHeader)).To(gomega.Equal(
+					Sign(test.expSecret, r.Header.Get(TimestampHeader), body)))
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			s, err := NewSender("default", time.Second, []string{"127.0.0.1"})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			err = s.Send(context.TODO(), server.URL, test.secret, event)
+			if test.expErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestSendDestinations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusFound))
+	defer redirect.Close()
+
+	tests := []struct {
+		name    string
+		allowed []string
+		url     string
+		expErr  bool
+	}{
+		{
+			name:   "loopback denied by default",
+			url:    server.URL,
+			expErr: true,
+		},
+		{
+			name:    "allowed by CIDR",
+			allowed: []string{"127.0.0.0/8"},
+			url:     server.URL,
+		},
+		{
+			name:    "allowed by host",
+			allowed: []string{"localhost"},
+			url:     strings.Replace(server.URL, "127.0.0.1", "localhost", 1),
+		},
+		{
+			name:    "not allowed",
+			allowed: []string{"10.0.0.0/8", "*.example.com"},
+			url:     server.URL,
+			expErr:  true,
+		},
+		{
+			name:    "unsupported scheme",
+			allowed: []string{"127.0.0.1"},
+			url:     strings.Replace(server.URL, "http://", "ftp://", 1),
+			expErr:  true,
+		},
+		{
+			name:    "redirect not followed",
+			allowed: []string{"127.0.0.1"},
+			url:     redirect.URL,
+			expErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			s, err := NewSender("default", time.Second, test.allowed)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			err = s.Send(context.TODO(), test.url, "", event)
+			if test.expErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAllowIP(t *testing.T) {
+	g := gomega.NewWithT(t)
+	d, err := newDestinations(nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "192.168.0.1", "169.254.169.254", "::1", "fd00::1", "0.0.0.0"} {
+		g.Expect(d.allowIP(net.ParseIP(ip))).To(gomega.BeFalse(), ip)
+	}
+	g.Expect(d.allowIP(net.ParseIP("8.8.8.8"))).To(gomega.BeTrue())
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/command"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/query"
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+// PutWebhook subscribes notifications of finished tasks of the account
+//
+//	@Summary		put webhook
+//	@Description	put webhook
+//	@Tags			webhook
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Router			/api/v1/webhooks/{id} [put]
+//	@Security		BearerAuth
+//	@Param			id		path		string				true	"put webhook id"
+//	@Param			request	body		PutWebhookRequest	true	"put webhook request"
+//	@Success		200		{object}	PutWebhookResponse
+//	@Failure		400		{object}	apperrors.AppError	"invalid param"
+//	@Failure		403		{object}	apperrors.AppError	"permission denied"
+//	@Failure		500		{object}	apperrors.AppError	"internal system error"
+func PutWebhook(c context.Context, ctx *app.RequestContext, handler command.PutHandler) {
+	var req PutWebhookRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	if err := handler.Handle(c, req.toDTO()); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+
+	resp := &PutWebhookResponse{}
+	utils.WriteHertzOKResponse(ctx, resp)
+}
+
+// ListWebhooks lists webhooks
+//
+//	@Summary		list webhooks
+//	@Description	list webhooks
+//	@Tags			webhook
+//	@Produce		application/json
+//	@Router			/api/v1/webhooks [get]
+//	@Security		BearerAuth
+//	@Param			account_id	query		string	false	"filter by account_id"
+//	@Success		200			{object}	ListWebhooksResponse
+//	@Failure		400			{object}	apperrors.AppError	"invalid param"
+//	@Failure		403			{object}	apperrors.AppError	"permission denied"
+//	@Failure		500			{object}	apperrors.AppError	"internal system error"
+func ListWebhooks(c context.Context, ctx *app.RequestContext, handler query.ListHandler) {
+	var req ListWebhooksRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	webhooks, err := handler.Handle(c, req.toDTO())
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	resp := make(ListWebhooksResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resp = append(resp, webhookDTOToVO(webhook))
+	}
+	utils.WriteHertzOKResponse(ctx, resp)
+}
+
+// DeleteWebhook delete webhook
+//
+//	@Summary		delete webhook
+//	@Description	delete webhook
+//	@Tags			webhook
+//	@Produce		application/json
+//	@Router			/api/v1/webhooks/{id} [delete]
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"delete webhook id"
+//	@Success		200	{object}	DeleteWebhookResponse
+//	@Failure		400	{object}	apperrors.AppError	"invalid param"
+//	@Failure		404	{object}	apperrors.AppError	"not found"
+//	@Failure		403	{object}	apperrors.AppError	"permission denied"
+//	@Failure		500	{object}	apperrors.AppError	"internal system error"
+func DeleteWebhook(c context.Context, ctx *app.RequestContext, handler command.DeleteHandler) {
+	var req DeleteWebhookRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	if err := handler.Handle(c, req.toDTO()); err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+
+	resp := &DeleteWebhookResponse{}
+	utils.WriteHertzOKResponse(ctx, resp)
+}
+
+// ListWebhookDeadLetters lists the latest deliveries which are dead after all attempts failed
+//
+//	@Summary		list webhook dead letters
+//	@Description	list webhook dead letters, latest first
+//	@Tags			webhook
+//	@Produce		application/json
+//	@Router			/api/v1/webhooks/dead_letters [get]
+//	@Security		BearerAuth
+//	@Param			limit		query		int		false	"max count of dead letters, default 100"
+//	@Param			account_id	query		string	false	"filter by account_id"
+//	@Param			webhook_id	query		string	false	"filter by webhook_id"
+//	@Success		200			{object}	ListWebhookDeadLettersResponse
+//	@Failure		400			{object}	apperrors.AppError	"invalid param"
+//	@Failure		403			{object}	apperrors.AppError	"permission denied"
+//	@Failure		500			{object}	apperrors.AppError	"internal system error"
+func ListWebhookDeadLetters(c context.Context, ctx *app.RequestContext, handler query.ListDeadLettersHandler) {
+	var req ListWebhookDeadLettersRequest
+	if err := ctx.Bind(&req); err != nil {
+		applog.Errorw("hertz bind error", "err", err)
+		utils.WriteHertzErrorResponse(ctx, apperrors.NewHertzBindError(err))
+		return
+	}
+
+	deliveries, err := handler.Handle(c, req.toDTO())
+	if err != nil {
+		utils.WriteHertzErrorResponse(ctx, err)
+		return
+	}
+	resp := make(ListWebhookDeadLettersResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, deliveryDTOToVO(delivery))
+	}
+	utils.WriteHertzOKResponse(ctx, resp)
+}
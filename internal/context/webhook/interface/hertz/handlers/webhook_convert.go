@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/command"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application/query"
+)
+
+func (r *PutWebhookRequest) toDTO() *command.PutCommand {
+	return &command.PutCommand{
+		ID:        r.ID,
+		AccountID: r.AccountID,
+		URL:       r.URL,
+		Secret:    r.Secret,
+	}
+}
+
+func (r *ListWebhooksRequest) toDTO() *query.ListQuery {
+	return &query.ListQuery{Filter: &query.ListFilter{AccountID: r.AccountID}}
+}
+
+func (r *DeleteWebhookRequest) toDTO() *command.DeleteCommand {
+	return &command.DeleteCommand{ID: r.ID}
+}
+
+func (r *ListWebhookDeadLettersRequest) toDTO() *query.ListDeadLettersQuery {
+	return &query.ListDeadLettersQuery{
+		Limit: r.Limit,
+		Filter: &query.ListDeadLettersFilter{
+			AccountID: r.AccountID,
+			WebhookID: r.WebhookID,
+		},
+	}
+}
+
+func webhookDTOToVO(webhook *query.Webhook) *Webhook {
+	if webhook == nil {
+		return nil
+	}
+	return &Webhook{
+		ID:        webhook.ID,
+		AccountID: webhook.AccountID,
+		URL:       webhook.URL,
+	}
+}
+
+func deliveryDTOToVO(delivery *query.Delivery) *Delivery {
+	if delivery == nil {
+		return nil
+	}
+	return &Delivery{
+		ID:              delivery.ID,
+		WebhookID:       delivery.WebhookID,
+		URL:             delivery.URL,
+		Event:           eventDTOToVO(delivery.Event),
+		State:           delivery.State,
+		Attempts:        delivery.Attempts,
+		NextAttemptTime: delivery.NextAttemptTime.Format(time.RFC3339),
+		LastError:       delivery.LastError,
+		CreationTime:    delivery.CreationTime.Format(time.RFC3339),
+	}
+}
+
+func eventDTOToVO(event *query.Event) *Event {
+	if event == nil {
+		return nil
+	}
+	return &Event{
+		TaskID:       event.TaskID,
+		State:        event.State,
+		Time:         event.Time.Format(time.RFC3339),
+		Name:         event.Name,
+		AccountID:    event.AccountID,
+		UserID:       event.UserID,
+		SubmissionID: event.SubmissionID,
+		RunID:        event.RunID,
+	}
+}
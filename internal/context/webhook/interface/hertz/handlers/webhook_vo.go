@@ -0,0 +1,71 @@
+package handlers
+
+// PutWebhookRequest ...
+type PutWebhookRequest struct {
+	ID        string `path:"id" json:"-"`
+	AccountID string `json:"account_id"`
+	URL       string `json:"url"`
+	// Secret signs the payload, the default secret of the server is used if empty
+	Secret string `json:"secret,omitempty"`
+}
+
+// PutWebhookResponse ...
+type PutWebhookResponse struct{}
+
+// ListWebhooksRequest ...
+type ListWebhooksRequest struct {
+	AccountID string `query:"account_id"`
+}
+
+// ListWebhooksResponse ...
+type ListWebhooksResponse []*Webhook
+
+// DeleteWebhookRequest ...
+type DeleteWebhookRequest struct {
+	ID string `path:"id"`
+}
+
+// DeleteWebhookResponse ...
+type DeleteWebhookResponse struct{}
+
+// ListWebhookDeadLettersRequest ...
+type ListWebhookDeadLettersRequest struct {
+	Limit     int    `query:"limit"`
+	AccountID string `query:"account_id"`
+	WebhookID string `query:"webhook_id"`
+}
+
+// ListWebhookDeadLettersResponse ...
+type ListWebhookDeadLettersResponse []*Delivery
+
+// Webhook ...
+type Webhook struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+	URL       string `json:"url"`
+}
+
+// Delivery ...
+type Delivery struct {
+	ID              int64  `json:"id"`
+	WebhookID       string `json:"webhook_id,omitempty"`
+	URL             string `json:"url"`
+	Event           *Event `json:"event"`
+	State           string `json:"state"`
+	Attempts        int    `json:"attempts"`
+	NextAttemptTime string `json:"next_attempt_time"`
+	LastError       string `json:"last_error,omitempty"`
+	CreationTime    string `json:"creation_time"`
+}
+
+// Event is the payload sent to webhooks
+type Event struct {
+	TaskID       string `json:"task_id"`
+	State        string `json:"state"`
+	Time         string `json:"time"`
+	Name         string `json:"name,omitempty"`
+	AccountID    string `json:"account_id,omitempty"`
+	UserID       string `json:"user_id,omitempty"`
+	SubmissionID string `json:"submission_id,omitempty"`
+	RunID        string `json:"run_id,omitempty"`
+}
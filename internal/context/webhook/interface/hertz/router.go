@@ -0,0 +1,42 @@
+package hertz
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/route"
+
+	"github.com/GBA-BI/tes-api/internal/context/webhook/application"
+	"github.com/GBA-BI/tes-api/internal/context/webhook/interface/hertz/handlers"
+	"github.com/GBA-BI/tes-api/pkg/auth"
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	appserver "github.com/GBA-BI/tes-api/pkg/server"
+)
+
+type register struct {
+	svc *application.WebhookService
+}
+
+// NewRouterRegister ...
+func NewRouterRegister(webhookService *application.WebhookService) appserver.RouteRegister {
+	return &register{
+		svc: webhookService,
+	}
+}
+
+// AddRoute ...
+func (r *register) AddRoute(h route.IRouter) {
+	webhook := h.Group(consts.OtherAPIPrefix + "/webhooks")
+	webhook.PUT("/:id", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.PutWebhook(c, ctx, r.svc.WebhookCommands.Put)
+	})
+	webhook.GET("", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.ListWebhooks(c, ctx, r.svc.WebhookQueries.List)
+	})
+	webhook.DELETE("/:id", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.DeleteWebhook(c, ctx, r.svc.WebhookCommands.Delete)
+	})
+	webhook.GET("/dead_letters", auth.RequireRoles(auth.RoleOperator), func(c context.Context, ctx *app.RequestContext) {
+		handlers.ListWebhookDeadLetters(c, ctx, r.svc.WebhookQueries.ListDeadLetters)
+	})
+}
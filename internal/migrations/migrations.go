@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+)
+
+// All returns migrations of the db schema, new migrations are appended with increasing versions,
+// and applied migrations must never be changed
+func All() []*migrate.Migration {
+	return []*migrate.Migration{
+		v1Baseline,
+		v2PreemptedFinishTime,
+		v3IdempotencyKeyIndexes,
+		v4NotificationAttempts,
+	}
+}
+
+// NewMigrator ...
+func NewMigrator(db *gorm.DB, opts *migrate.Options) (*migrate.Migrator, error) {
+	return migrate.NewMigrator(db, All(), opts)
+}
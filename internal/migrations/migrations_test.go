@@ -0,0 +1,136 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+func TestUpDown(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	m, err := NewMigrator(db, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	g.Expect(m.Check(context.TODO())).To(gomega.Succeed())
+	for _, table := range v1Tables {
+		g.Expect(db.Migrator().HasTable(table)).To(gomega.BeTrue())
+	}
+
+	// the baseline is irreversible
+	g.Expect(m.Down(context.TODO(), 0)).NotTo(gomega.Succeed())
+	for _, table := range v1Tables {
+		g.Expect(db.Migrator().HasTable(table)).To(gomega.BeTrue())
+	}
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	g.Expect(m.Check(context.TODO())).To(gomega.Succeed())
+}
+
+// legacyTask is the task table before task_tag, effective_priority and finish_time are added
+type legacyTask struct {
+	ID            string    `gorm:"column:id;type:VARCHAR(16);not null;primaryKey"`
+	State         string    `gorm:"column:state;type:VARCHAR(16);not null"`
+	CreationTime  time.Time `gorm:"column:creation_time;type:DATETIME;not null"`
+	CPUCores      int       `gorm:"column:cpu_cores;type:SMALLINT;not null"`
+	RamGB         float64   `gorm:"column:ram_gb;type:DOUBLE;not null"` // nolint
+	DiskGB        float64   `gorm:"column:disk_gb;type:DOUBLE;not null"`
+	Tags          *string   `gorm:"column:tags;type:LONGTEXT"`
+	PriorityValue int       `gorm:"column:priority_value;type:BIGINT;not null;default:0"`
+}
+
+func (t *legacyTask) TableName() string {
+	return "task"
+}
+
+func TestBaselineBackfill(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	creationTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	g.Expect(db.AutoMigrate(&legacyTask{})).To(gomega.Succeed())
+	g.Expect(db.Create([]*legacyTask{
+		{ID: "task-1111", State: consts.TaskRunning, CreationTime: creationTime, Tags: utils.Point(`{"k1":"v1"}`), PriorityValue: 10},
+		{ID: "task-2222", State: consts.TaskComplete, CreationTime: creationTime},
+	}).Error).To(gomega.Succeed())
+
+	m, err := NewMigrator(db, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+
+	tags := make([]*v1TaskTag, 0)
+	g.Expect(db.Find(&tags).Error).To(gomega.Succeed())
+	g.Expect(tags).To(gomega.Equal([]*v1TaskTag{{TaskID: "task-1111", TagKey: "k1", TagValue: "v1"}}))
+
+	tasks := make([]*v1Task, 0)
+	g.Expect(db.Order("id").Find(&tasks).Error).To(gomega.Succeed())
+	g.Expect(tasks).To(gomega.HaveLen(2))
+	g.Expect(tasks[0].EffectivePriority).To(gomega.Equal(10))
+	g.Expect(tasks[0].FinishTime).To(gomega.BeNil())
+	g.Expect(tasks[1].FinishTime).NotTo(gomega.BeNil())
+	g.Expect(tasks[1].FinishTime.Equal(creationTime)).To(gomega.BeTrue())
+}
+
+func TestPreemptedFinishTime(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	creationTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	m, err := NewMigrator(db, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(m.Up(context.TODO(), 1)).To(gomega.Succeed())
+	g.Expect(db.Create([]*v1Task{
+		{ID: "task-1111", State: consts.TaskPreempted, CreationTime: creationTime},
+		{ID: "task-2222", State: consts.TaskRunning, CreationTime: creationTime},
+	}).Error).To(gomega.Succeed())
+	g.Expect(db.Create(&v1TaskEvent{TaskID: "task-1111", EventTime: creationTime.Add(time.Hour),
+		PreviousState: consts.TaskRunning, State: consts.TaskPreempted}).Error).To(gomega.Succeed())
+
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	tasks := make([]*v1Task, 0)
+	g.Expect(db.Order("id").Find(&tasks).Error).To(gomega.Succeed())
+	g.Expect(tasks).To(gomega.HaveLen(2))
+	g.Expect(tasks[0].FinishTime).NotTo(gomega.BeNil())
+	g.Expect(tasks[0].FinishTime.Equal(creationTime.Add(time.Hour))).To(gomega.BeTrue())
+	g.Expect(tasks[1].FinishTime).To(gomega.BeNil())
+}
+
+func TestIdempotencyKeyIndexes(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	m, err := NewMigrator(db, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	for _, index := range v3Indexes {
+		g.Expect(db.Migrator().HasIndex(&v3TaskIdempotencyKey{}, index)).To(gomega.BeTrue())
+	}
+	g.Expect(m.Down(context.TODO(), 2)).To(gomega.Succeed())
+	for _, index := range v3Indexes {
+		g.Expect(db.Migrator().HasIndex(&v3TaskIdempotencyKey{}, index)).To(gomega.BeFalse())
+	}
+}
+
+func TestNotificationAttempts(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	m, err := NewMigrator(db, migrate.NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(m.Up(context.TODO(), 3)).To(gomega.Succeed())
+	g.Expect(db.Create(&v1TaskNotification{TaskID: "task-1111", State: consts.TaskComplete, EventTime: time.Now()}).Error).
+		To(gomega.Succeed())
+
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	notifications := make([]*v4TaskNotification, 0)
+	g.Expect(db.Find(&notifications).Error).To(gomega.Succeed())
+	g.Expect(notifications).To(gomega.HaveLen(1))
+	g.Expect(notifications[0].Attempts).To(gomega.Equal(0))
+
+	g.Expect(m.Down(context.TODO(), 3)).To(gomega.Succeed())
+	g.Expect(db.Migrator().HasColumn(&v4TaskNotification{}, "attempts")).To(gomega.BeFalse())
+}
@@ -0,0 +1,297 @@
+package migrations
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+)
+
+// v1Baseline creates the schema which was auto migrated by persistent objects before migrations are
+// introduced, and backfills columns added by those auto migrations. Tables are snapshots of persistent
+// objects at that time, they must not follow later changes of persistent objects.
+// It is irreversible, since the tables may hold data created before migrations are introduced.
+var v1Baseline = &migrate.Migration{
+	Version: 1,
+	Name:    "baseline",
+	Up: func(tx *gorm.DB) error {
+		needBackfillTags := !tx.Migrator().HasTable(&v1TaskTag{})
+		needBackfillPriority := !tx.Migrator().HasColumn(&v1Task{}, "effective_priority")
+		needBackfillFinishTime := !tx.Migrator().HasColumn(&v1Task{}, "finish_time")
+		if err := tx.AutoMigrate(v1Tables...); err != nil {
+			return err
+		}
+		if needBackfillTags {
+			if err := v1BackfillTaskTags(tx); err != nil {
+				return err
+			}
+		}
+		if needBackfillPriority {
+			if err := v1BackfillEffectivePriority(tx); err != nil {
+				return err
+			}
+		}
+		if needBackfillFinishTime {
+			return v1BackfillFinishTime(tx)
+		}
+		return nil
+	},
+}
+
+var v1Tables = []interface{}{
+	&v1Task{}, &v1TaskArchive{}, &v1TaskTag{}, &v1TaskEvent{}, &v1TaskNotification{}, &v1TaskIdempotencyKey{},
+	&v1Cluster{}, &v1Quota{}, &v1ExtraPriority{}, &v1Webhook{}, &v1WebhookDelivery{},
+}
+
+const v1BackfillBatchSize = 1000
+
+// v1BackfillTaskTags fills task_tag with tags of tasks created before task_tag exists
+func v1BackfillTaskTags(tx *gorm.DB) error {
+	tasks := make([]*struct {
+		ID   string  `gorm:"column:id"`
+		Tags *string `gorm:"column:tags"`
+	}, 0)
+	return tx.Model(&v1Task{}).Select("id", "tags").
+		FindInBatches(&tasks, v1BackfillBatchSize, func(_ *gorm.DB, _ int) error {
+			taskTags := make([]*v1TaskTag, 0)
+			for _, task := range tasks {
+				if task.Tags == nil {
+					continue
+				}
+				tags := make(map[string]string)
+				if err := json.Unmarshal([]byte(*task.Tags), &tags); err != nil {
+					return err
+				}
+				for key, value := range tags {
+					taskTags = append(taskTags, &v1TaskTag{TaskID: task.ID, TagKey: key, TagValue: value})
+				}
+			}
+			if len(taskTags) == 0 {
+				return nil
+			}
+			return tx.Session(&gorm.Session{NewDB: true}).Clauses(clause.OnConflict{DoNothing: true}).Create(&taskTags).Error
+		}).Error
+}
+
+// v1BackfillEffectivePriority fills effective_priority of tasks created before it exists,
+// extra priorities are applied to them when the extra priorities change
+func v1BackfillEffectivePriority(tx *gorm.DB) error {
+	return tx.Model(&v1Task{}).Where("effective_priority <> priority_value").
+		Update("effective_priority", gorm.Expr("priority_value")).Error
+}
+
+// v1BackfillFinishTime fills finish_time of tasks finished before it exists with their last event time,
+// or creation time for tasks without events
+func v1BackfillFinishTime(tx *gorm.DB) error {
+	finishedStates := []string{consts.TaskCanceled, consts.TaskComplete, consts.TaskExecutorError, consts.TaskSystemError}
+	lastEventTime := tx.Session(&gorm.Session{NewDB: true}).Model(&v1TaskEvent{}).Select("MAX(event_time)").
+		Where("task_event.task_id = task.id")
+	return tx.Model(&v1Task{}).Where("state IN ?", finishedStates).Where("finish_time IS NULL").
+		Update("finish_time", gorm.Expr("COALESCE((?), creation_time)", lastEventTime)).Error
+}
+
+type v1Task struct {
+	ID                      string     `gorm:"column:id;type:VARCHAR(16);not null;primaryKey"`
+	State                   string     `gorm:"column:state;type:VARCHAR(16);not null;index:state_cluster,priority:1;index:state_account_user,priority:1;index:state_priority,priority:1"`
+	Logs                    *string    `gorm:"column:logs;type:LONGTEXT"`
+	CreationTime            time.Time  `gorm:"column:creation_time;type:DATETIME;not null"`
+	ClusterID               string     `gorm:"column:cluster_id;type:VARCHAR(32);not null;default:'';index:state_cluster,priority:2"`
+	QuotaHeld               bool       `gorm:"column:quota_held;type:BOOLEAN;not null;default:false"`
+	MaxRetries              int        `gorm:"column:max_retries;type:BIGINT;not null;default:0"`
+	Retries                 int        `gorm:"column:retries;type:BIGINT;not null;default:0"`
+	FinishTime              *time.Time `gorm:"column:finish_time;type:DATETIME;index:finish_time"`
+	StatusResourceVersion   int        `gorm:"column:status_resource_version;type:BIGINT;not null;default:0"`
+	Name                    string     `gorm:"column:name;type:VARCHAR(512);not null;default:'';index:name"`
+	Description             *string    `gorm:"column:description;type:LONGTEXT"`
+	CPUCores                int        `gorm:"column:cpu_cores;type:SMALLINT;not null"`
+	RamGB                   float64    `gorm:"column:ram_gb;type:DOUBLE;not null"` // nolint
+	DiskGB                  float64    `gorm:"column:disk_gb;type:DOUBLE;not null"`
+	BootDiskGB              *int       `gorm:"column:boot_disk_gb;type:SMALLINT"`
+	GPUCount                *float64   `gorm:"column:gpu_count;type:DOUBLE"`
+	GPUType                 *string    `gorm:"column:gpu_type;type:VARCHAR(32)"`
+	Preemptible             bool       `gorm:"column:preemptible;type:BOOLEAN;not null;default:false"`
+	Zones                   *string    `gorm:"column:zones;type:LONGTEXT"`
+	BackendParameters       *string    `gorm:"column:backend_parameters;type:LONGTEXT"`
+	BackendParametersStrict bool       `gorm:"column:backend_parameters_strict;type:BOOLEAN;not null;default:false"`
+	Executors               *string    `gorm:"column:executors;type:LONGTEXT"`
+	Volumes                 *string    `gorm:"column:volumes;type:LONGTEXT"`
+	Tags                    *string    `gorm:"column:tags;type:LONGTEXT"`
+	AccountID               string     `gorm:"column:account_id;type:VARCHAR(32);not null;default:'';index:state_account_user,priority:2"`
+	UserID                  string     `gorm:"column:user_id;type:VARCHAR(32);not null;default:'';index:state_account_user,priority:3"`
+	SubmissionID            string     `gorm:"column:submission_id;type:VARCHAR(32);not null;default:''"`
+	RunID                   string     `gorm:"column:run_id;type:VARCHAR(32);not null;default:''"`
+	Meta                    *string    `gorm:"column:meta;type:longtext"`
+	PriorityValue           int        `gorm:"column:priority_value;type:BIGINT;not null;default:0"`
+	EffectivePriority       int        `gorm:"column:effective_priority;type:BIGINT;not null;default:0;index:state_priority,priority:2"`
+	CallbackURL             string     `gorm:"column:callback_url;type:VARCHAR(1024);not null;default:''"`
+	RetryOf                 string     `gorm:"column:retry_of;type:VARCHAR(16);not null;default:'';index:retry_of"`
+	Attempt                 int        `gorm:"column:attempt;type:BIGINT;not null;default:1"`
+	Inputs                  *string    `gorm:"column:inputs;type:LONGTEXT"`
+	Outputs                 *string    `gorm:"column:outputs;type:LONGTEXT"`
+}
+
+func (t *v1Task) TableName() string {
+	return "task"
+}
+
+// v1TaskArchive has the same columns as v1Task, but archived tasks are only got by id, so it has no
+// secondary indexes, whose names would conflict with indexes of task in postgres and sqlite
+type v1TaskArchive struct {
+	ID                      string     `gorm:"column:id;type:VARCHAR(16);not null;primaryKey"`
+	State                   string     `gorm:"column:state;type:VARCHAR(16);not null"`
+	Logs                    *string    `gorm:"column:logs;type:LONGTEXT"`
+	CreationTime            time.Time  `gorm:"column:creation_time;type:DATETIME;not null"`
+	ClusterID               string     `gorm:"column:cluster_id;type:VARCHAR(32);not null;default:''"`
+	QuotaHeld               bool       `gorm:"column:quota_held;type:BOOLEAN;not null;default:false"`
+	MaxRetries              int        `gorm:"column:max_retries;type:BIGINT;not null;default:0"`
+	Retries                 int        `gorm:"column:retries;type:BIGINT;not null;default:0"`
+	FinishTime              *time.Time `gorm:"column:finish_time;type:DATETIME"`
+	StatusResourceVersion   int        `gorm:"column:status_resource_version;type:BIGINT;not null;default:0"`
+	Name                    string     `gorm:"column:name;type:VARCHAR(512);not null;default:''"`
+	Description             *string    `gorm:"column:description;type:LONGTEXT"`
+	CPUCores                int        `gorm:"column:cpu_cores;type:SMALLINT;not null"`
+	RamGB                   float64    `gorm:"column:ram_gb;type:DOUBLE;not null"` // nolint
+	DiskGB                  float64    `gorm:"column:disk_gb;type:DOUBLE;not null"`
+	BootDiskGB              *int       `gorm:"column:boot_disk_gb;type:SMALLINT"`
+	GPUCount                *float64   `gorm:"column:gpu_count;type:DOUBLE"`
+	GPUType                 *string    `gorm:"column:gpu_type;type:VARCHAR(32)"`
+	Preemptible             bool       `gorm:"column:preemptible;type:BOOLEAN;not null;default:false"`
+	Zones                   *string    `gorm:"column:zones;type:LONGTEXT"`
+	BackendParameters       *string    `gorm:"column:backend_parameters;type:LONGTEXT"`
+	BackendParametersStrict bool       `gorm:"column:backend_parameters_strict;type:BOOLEAN;not null;default:false"`
+	Executors               *string    `gorm:"column:executors;type:LONGTEXT"`
+	Volumes                 *string    `gorm:"column:volumes;type:LONGTEXT"`
+	Tags                    *string    `gorm:"column:tags;type:LONGTEXT"`
+	AccountID               string     `gorm:"column:account_id;type:VARCHAR(32);not null;default:''"`
+	UserID                  string     `gorm:"column:user_id;type:VARCHAR(32);not null;default:''"`
+	SubmissionID            string     `gorm:"column:submission_id;type:VARCHAR(32);not null;default:''"`
+	RunID                   string     `gorm:"column:run_id;type:VARCHAR(32);not null;default:''"`
+	Meta                    *string    `gorm:"column:meta;type:longtext"`
+	PriorityValue           int        `gorm:"column:priority_value;type:BIGINT;not null;default:0"`
+	EffectivePriority       int        `gorm:"column:effective_priority;type:BIGINT;not null;default:0"`
+	CallbackURL             string     `gorm:"column:callback_url;type:VARCHAR(1024);not null;default:''"`
+	RetryOf                 string     `gorm:"column:retry_of;type:VARCHAR(16);not null;default:''"`
+	Attempt                 int        `gorm:"column:attempt;type:BIGINT;not null;default:1"`
+	Inputs                  *string    `gorm:"column:inputs;type:LONGTEXT"`
+	Outputs                 *string    `gorm:"column:outputs;type:LONGTEXT"`
+}
+
+func (t *v1TaskArchive) TableName() string {
+	return "task_archive"
+}
+
+type v1TaskTag struct {
+	TaskID   string `gorm:"column:task_id;type:VARCHAR(16);not null;primaryKey"`
+	TagKey   string `gorm:"column:tag_key;type:VARCHAR(128);not null;primaryKey;index:key_value,priority:1"`
+	TagValue string `gorm:"column:tag_value;type:VARCHAR(512);not null;default:'';index:key_value,priority:2"`
+}
+
+func (t *v1TaskTag) TableName() string {
+	return "task_tag"
+}
+
+type v1TaskEvent struct {
+	ID            int64     `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement"`
+	TaskID        string    `gorm:"column:task_id;type:VARCHAR(16);not null;index:task_id"`
+	EventTime     time.Time `gorm:"column:event_time;type:DATETIME;not null"`
+	PreviousState string    `gorm:"column:previous_state;type:VARCHAR(16);not null;default:''"`
+	State         string    `gorm:"column:state;type:VARCHAR(16);not null"`
+	ClusterID     string    `gorm:"column:cluster_id;type:VARCHAR(32);not null;default:''"`
+	RequestID     string    `gorm:"column:request_id;type:VARCHAR(64);not null;default:''"`
+}
+
+func (t *v1TaskEvent) TableName() string {
+	return "task_event"
+}
+
+type v1TaskNotification struct {
+	ID        int64     `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement"`
+	TaskID    string    `gorm:"column:task_id;type:VARCHAR(16);not null"`
+	State     string    `gorm:"column:state;type:VARCHAR(16);not null"`
+	EventTime time.Time `gorm:"column:event_time;type:DATETIME;not null"`
+}
+
+func (t *v1TaskNotification) TableName() string {
+	return "task_notification"
+}
+
+type v1TaskIdempotencyKey struct {
+	AccountID      string    `gorm:"column:account_id;type:VARCHAR(32);not null;default:'';primaryKey"`
+	UserID         string    `gorm:"column:user_id;type:VARCHAR(32);not null;default:'';primaryKey"`
+	IdempotencyKey string    `gorm:"column:idempotency_key;type:VARCHAR(128);not null;primaryKey"`
+	TaskID         string    `gorm:"column:task_id;type:VARCHAR(16);not null"`
+	ExpireTime     time.Time `gorm:"column:expire_time;type:DATETIME;not null"`
+}
+
+func (t *v1TaskIdempotencyKey) TableName() string {
+	return "task_idempotency_key"
+}
+
+type v1Cluster struct {
+	ID                 string    `gorm:"column:id;type:VARCHAR(32);not null;primaryKey"`
+	HeartbeatTimestamp time.Time `gorm:"column:heartbeat_timestamp;type:DATETIME;not null"`
+	Capacity           *string   `gorm:"column:capacity;type:LONGTEXT"`
+	Limits             *string   `gorm:"column:limits;type:LONGTEXT"`
+	Healthy            bool      `gorm:"column:healthy;type:BOOLEAN;not null;default:true"`
+}
+
+func (c *v1Cluster) TableName() string {
+	return "cluster"
+}
+
+type v1Quota struct {
+	ID            string  `gorm:"column:id;type:VARCHAR(128);not null;primaryKey"`
+	AccountID     string  `gorm:"column:account_id;type:VARCHAR(32);not null;default:''"`
+	UserID        string  `gorm:"column:user_id;type:VARCHAR(32);not null;default:''"`
+	ResourceQuota *string `gorm:"column:resource_quota;type:LONGTEXT"`
+}
+
+func (q *v1Quota) TableName() string {
+	return "quota"
+}
+
+type v1ExtraPriority struct {
+	ID                 string `gorm:"column:id;type:VARCHAR(128);not null;primaryKey"`
+	AccountID          string `gorm:"column:account_id;type:VARCHAR(32);not null;default:''"`
+	UserID             string `gorm:"column:user_id;type:VARCHAR(32);not null;default:''"`
+	SubmissionID       string `gorm:"column:submission_id;type:VARCHAR(32);not null;default:''"`
+	RunID              string `gorm:"column:run_id;type:VARCHAR(32);not null;default:''"`
+	ExtraPriorityValue int    `gorm:"column:extra_priority_value;type:BIGINT;not null;default:0"`
+}
+
+func (e *v1ExtraPriority) TableName() string {
+	return "extra_priority"
+}
+
+type v1Webhook struct {
+	ID        string `gorm:"column:id;type:VARCHAR(32);not null;primaryKey"`
+	AccountID string `gorm:"column:account_id;type:VARCHAR(32);not null;index:account_id"`
+	URL       string `gorm:"column:url;type:VARCHAR(1024);not null"`
+	Secret    string `gorm:"column:secret;type:VARCHAR(256);not null;default:''"`
+}
+
+func (w *v1Webhook) TableName() string {
+	return "webhook"
+}
+
+type v1WebhookDelivery struct {
+	ID              int64     `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement"`
+	NotificationID  int64     `gorm:"column:notification_id;type:BIGINT;not null;uniqueIndex:notification_webhook,priority:1"`
+	WebhookID       string    `gorm:"column:webhook_id;type:VARCHAR(32);not null;default:'';uniqueIndex:notification_webhook,priority:2"`
+	URL             string    `gorm:"column:url;type:VARCHAR(1024);not null"`
+	Event           *string   `gorm:"column:event;type:LONGTEXT"`
+	AccountID       string    `gorm:"column:account_id;type:VARCHAR(32);not null;default:'';index:state_account,priority:2"`
+	State           string    `gorm:"column:state;type:VARCHAR(16);not null;index:state_next_attempt_time,priority:1;index:state_account,priority:1"`
+	Attempts        int       `gorm:"column:attempts;type:INT;not null;default:0"`
+	NextAttemptTime time.Time `gorm:"column:next_attempt_time;type:DATETIME;not null;index:state_next_attempt_time,priority:2"`
+	LastError       *string   `gorm:"column:last_error;type:TEXT"`
+	CreationTime    time.Time `gorm:"column:creation_time;type:DATETIME;not null"`
+}
+
+func (d *v1WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
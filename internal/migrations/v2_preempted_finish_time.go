@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+)
+
+// v2PreemptedFinishTime fills finish_time of PREEMPTED tasks, which are finished unless they are requeued,
+// with their last event time, so that they are moved out by retention. finish_time of PREEMPTED tasks is
+// harmless to older versions, so nothing is reverted.
+var v2PreemptedFinishTime = &migrate.Migration{
+	Version: 2,
+	Name:    "preempted_finish_time",
+	Up: func(tx *gorm.DB) error {
+		lastEventTime := tx.Session(&gorm.Session{NewDB: true}).Table("task_event").Select("MAX(event_time)").
+			Where("task_event.task_id = task.id")
+		return tx.Table("task").Where("state = ?", consts.TaskPreempted).Where("finish_time IS NULL").
+			Update("finish_time", gorm.Expr("COALESCE((?), creation_time)", lastEventTime)).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return nil
+	},
+}
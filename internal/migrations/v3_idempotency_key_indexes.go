@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+)
+
+// v3IdempotencyKeyIndexes indexes idempotency keys by task and expire time, so that they are deleted together
+// with purged tasks and after they expire
+var v3IdempotencyKeyIndexes = &migrate.Migration{
+	Version: 3,
+	Name:    "idempotency_key_indexes",
+	Up: func(tx *gorm.DB) error {
+		for _, index := range v3Indexes {
+			if tx.Migrator().HasIndex(&v3TaskIdempotencyKey{}, index) {
+				continue
+			}
+			if err := tx.Migrator().CreateIndex(&v3TaskIdempotencyKey{}, index); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, index := range v3Indexes {
+			if !tx.Migrator().HasIndex(&v3TaskIdempotencyKey{}, index) {
+				continue
+			}
+			if err := tx.Migrator().DropIndex(&v3TaskIdempotencyKey{}, index); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var v3Indexes = []string{"idempotency_task_id", "idempotency_expire_time"}
+
+type v3TaskIdempotencyKey struct {
+	AccountID      string    `gorm:"column:account_id;type:VARCHAR(32);not null;default:'';primaryKey"`
+	UserID         string    `gorm:"column:user_id;type:VARCHAR(32);not null;default:'';primaryKey"`
+	IdempotencyKey string    `gorm:"column:idempotency_key;type:VARCHAR(128);not null;primaryKey"`
+	TaskID         string    `gorm:"column:task_id;type:VARCHAR(16);not null;index:idempotency_task_id"`
+	ExpireTime     time.Time `gorm:"column:expire_time;type:DATETIME;not null;index:idempotency_expire_time"`
+}
+
+func (t *v3TaskIdempotencyKey) TableName() string {
+	return "task_idempotency_key"
+}
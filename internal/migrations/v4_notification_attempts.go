@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/pkg/migrate"
+)
+
+// v4NotificationAttempts counts failed attempts of dispatching task notifications, so that a notification
+// failing again and again is dropped instead of blocking the outbox
+var v4NotificationAttempts = &migrate.Migration{
+	Version: 4,
+	Name:    "notification_attempts",
+	Up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasColumn(&v4TaskNotification{}, "attempts") {
+			return nil
+		}
+		return tx.Migrator().AddColumn(&v4TaskNotification{}, "Attempts")
+	},
+	Down: func(tx *gorm.DB) error {
+		if !tx.Migrator().HasColumn(&v4TaskNotification{}, "attempts") {
+			return nil
+		}
+		return tx.Migrator().DropColumn(&v4TaskNotification{}, "attempts")
+	},
+}
+
+type v4TaskNotification struct {
+	ID        int64     `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement"`
+	TaskID    string    `gorm:"column:task_id;type:VARCHAR(16);not null"`
+	State     string    `gorm:"column:state;type:VARCHAR(16);not null"`
+	EventTime time.Time `gorm:"column:event_time;type:DATETIME;not null"`
+	Attempts  int       `gorm:"column:attempts;type:INT;not null;default:0"`
+}
+
+func (t *v4TaskNotification) TableName() string {
+	return "task_notification"
+}
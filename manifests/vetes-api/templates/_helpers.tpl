@@ -69,3 +69,40 @@ Create the normalize configmap name
 {{- define "vetes-api.normalizeName" -}}
 {{ include "vetes-api.fullname" . | trunc 53 | trimSuffix "-" }}-normalize
 {{- end -}}
+
+{{/*
+Env of db credentials, shared by the migrate init container and the api server container
+*/}}
+{{- define "vetes-api.dbEnv" -}}
+{{- if eq .Values.db.type "mysql" }}
+- name: "DB_MYSQL_USERNAME"
+  valueFrom:
+    secretKeyRef:
+      key: mysqlUsername
+      name: {{ include "vetes-api.fullname" . }}
+- name: "DB_MYSQL_PASSWORD"
+  valueFrom:
+    secretKeyRef:
+      key: mysqlPassword
+      name: {{ include "vetes-api.fullname" . }}
+{{- end }}
+{{- if eq .Values.db.type "postgres" }}
+- name: "DB_POSTGRES_USERNAME"
+  valueFrom:
+    secretKeyRef:
+      key: postgresUsername
+      name: {{ include "vetes-api.fullname" . }}
+- name: "DB_POSTGRES_PASSWORD"
+  valueFrom:
+    secretKeyRef:
+      key: postgresPassword
+      name: {{ include "vetes-api.fullname" . }}
+{{- end }}
+{{- end -}}
+
+{{/*
+Whether the api server migrates the db schema at startup, sqlite db is not shared by pods
+*/}}
+{{- define "vetes-api.migrateAuto" -}}
+{{- or .Values.migrate.auto (eq .Values.db.type "sqlite") -}}
+{{- end -}}
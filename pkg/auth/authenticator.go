@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidToken is returned if the token is not accepted by any authenticator
+var ErrInvalidToken = errors.New("invalid token")
+
+// Authenticator authenticates bearer tokens
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+// NewAuthenticator returns nil if authentication is disabled
+func NewAuthenticator(opts *Options) (Authenticator, error) {
+	if !opts.Enable {
+		return nil, nil
+	}
+	authenticators := make(chainAuthenticator, 0)
+	if opts.TokensFile != "" {
+		tokens, err := newTokenAuthenticator(opts.TokensFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, tokens)
+	}
+	if opts.JWT.JWKSFile != "" {
+		jwt, err := newJWTAuthenticator(opts.JWT)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, jwt)
+	}
+	return authenticators, nil
+}
+
+// chainAuthenticator accepts tokens accepted by any of the authenticators
+type chainAuthenticator []Authenticator
+
+// Authenticate returns the error of the last authenticator if the token is not accepted
+func (c chainAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	err := ErrInvalidToken
+	for _, authenticator := range c {
+		var identity *Identity
+		if identity, err = authenticator.Authenticate(ctx, token); err == nil {
+			return identity, nil
+		}
+	}
+	return nil, err
+}
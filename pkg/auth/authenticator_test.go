@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestNewAuthenticator(t *testing.T) {
+	g := gomega.NewWithT(t)
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	g.Expect(os.WriteFile(path, []byte(`[
+		{"token": "token-01", "subject": "operator", "roles": ["operator"]},
+		{"token": "token-02", "subject": "user-01", "accountID": "account-01", "userID": "user-01"},
+		{"token": "token-04", "subject": "agent-01", "roles": ["cluster-agent"], "clusterID": "cluster-01"}
+	]`), 0600)).To(gomega.Succeed())
+
+	opts := NewOptions()
+	a, err := NewAuthenticator(opts)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(a).To(gomega.BeNil())
+
+	opts.Enable = true
+	opts.TokensFile = path
+	a, err = NewAuthenticator(opts)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	identity, err := a.Authenticate(context.TODO(), "token-01")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(identity).To(gomega.Equal(&Identity{Subject: "operator", Roles: []string{RoleOperator}}))
+	identity, err = a.Authenticate(context.TODO(), "token-02")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(identity).To(gomega.Equal(&Identity{Subject: "user-01", AccountID: "account-01", UserID: "user-01", Roles: []string{RoleUser}}))
+	identity, err = a.Authenticate(context.TODO(), "token-04")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(identity).To(gomega.Equal(&Identity{Subject: "agent-01", Roles: []string{RoleClusterAgent}, ClusterID: "cluster-01"}))
+	_, err = a.Authenticate(context.TODO(), "token-03")
+	g.Expect(err).To(gomega.MatchError(ErrInvalidToken))
+
+	g.Expect(os.WriteFile(path, []byte(`[{"token": "token-01"}]`), 0600)).To(gomega.Succeed())
+	_, err = NewAuthenticator(opts)
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	g.Expect(os.WriteFile(path, []byte(`[{"token": "token-01", "subject": "admin", "roles": ["admin"]}]`), 0600)).To(gomega.Succeed())
+	_, err = NewAuthenticator(opts)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestBindOwner(t *testing.T) {
+	tests := []struct {
+		name       string
+		identity   *Identity
+		accountID  string
+		userID     string
+		expAccount string
+		expUser    string
+		expErr     bool
+	}{
+		{
+			name:       "not authenticated",
+			accountID:  "account-01",
+			userID:     "user-01",
+			expAccount: "account-01",
+			expUser:    "user-01",
+		},
+		{
+			name:       "not bound",
+			identity:   &Identity{Subject: "operator"},
+			accountID:  "account-01",
+			expAccount: "account-01",
+		},
+		{
+			name:       "filled",
+			identity:   &Identity{Subject: "user-01", AccountID: "account-01", UserID: "user-01"},
+			expAccount: "account-01",
+			expUser:    "user-01",
+		},
+		{
+			name:       "same",
+			identity:   &Identity{Subject: "user-01", AccountID: "account-01"},
+			accountID:  "account-01",
+			userID:     "user-02",
+			expAccount: "account-01",
+			expUser:    "user-02",
+		},
+		{
+			name:      "different account",
+			identity:  &Identity{Subject: "user-01", AccountID: "account-01", UserID: "user-01"},
+			accountID: "account-02",
+			expErr:    true,
+		},
+		{
+			name:     "different user",
+			identity: &Identity{Subject: "user-01", AccountID: "account-01", UserID: "user-01"},
+			userID:   "user-02",
+			expErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			accountID, userID, err := test.identity.BindOwner(test.accountID, test.userID)
+			if test.expErr {
+				g.Expect(apperrors.IsCode(err, apperrors.PermissionDeniedCode)).To(gomega.BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(accountID).To(gomega.Equal(test.expAccount))
+			g.Expect(userID).To(gomega.Equal(test.expUser))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+// Identity is the authenticated caller
+type Identity struct {
+	// Subject identifies the caller, e.g. subject of a static token or sub claim of a JWT
+	Subject string
+	// AccountID and UserID bind the caller to its own resources, empty if it is not bound
+	AccountID string
+	UserID    string
+	// Roles decide routes allowed for the caller, see RoleUser, RoleClusterAgent and RoleOperator
+	Roles []string
+	// ClusterID binds a cluster agent to its own cluster
+	ClusterID string
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the identity
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// GetIdentity returns the identity carried by ctx, or nil if the caller is not authenticated
+func GetIdentity(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// BindOwner returns the account and user of a resource created by the caller, empty ones are
+// filled with the identity, and ones different from the identity are denied
+func (i *Identity) BindOwner(accountID, userID string) (string, string, error) {
+	if i == nil {
+		return accountID, userID, nil
+	}
+	if i.AccountID != "" {
+		if accountID != "" && accountID != i.AccountID {
+			return "", "", apperrors.NewPermissionDeniedError("account_id is different from the caller")
+		}
+		accountID = i.AccountID
+	}
+	if i.UserID != "" {
+		if userID != "" && userID != i.UserID {
+			return "", "", apperrors.NewPermissionDeniedError("user_id is different from the caller")
+		}
+		userID = i.UserID
+	}
+	return accountID, userID, nil
+}
@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // for SHA256 of RS256, PS256 and ES256
+	_ "crypto/sha512" // for SHA384 and SHA512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// jwtHashes are hashes of supported signing algorithms, HMAC and none are never accepted
+var jwtHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// jwtCurves are curves of ECDSA signing algorithms
+var jwtCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+type jsonWebKeySet struct {
+	Keys []*jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// N and E are parameters of RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// Crv, X and Y are parameters of EC keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type verificationKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+type jwtAuthenticator struct {
+	opts *JWTOptions
+	keys []*verificationKey
+	now  func() time.Time
+}
+
+func newJWTAuthenticator(opts *JWTOptions) (*jwtAuthenticator, error) {
+	data, err := os.ReadFile(opts.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwks file %s: %w", opts.JWKSFile, err)
+	}
+	return &jwtAuthenticator{opts: opts, keys: keys, now: time.Now}, nil
+}
+
+// parseJWKS returns RSA and EC signing keys of the key set, other keys are ignored
+func parseJWKS(data []byte) ([]*verificationKey, error) {
+	set := &jsonWebKeySet{}
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+	keys := make([]*verificationKey, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			key, err = parseRSAKey(jwk)
+		case "EC":
+			key, err = parseECKey(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", jwk.Kid, err)
+		}
+		keys = append(keys, &verificationKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+	return keys, nil
+}
+
+func parseRSAKey(jwk *jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeBigInt(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func parseECKey(jwk *jsonWebKey) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+	}
+	x, err := decodeBigInt(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBigInt(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// Authenticate verifies the signature and registered claims of the JWT
+func (a *jwtAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	header := &struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, fmt.Errorf("%w: invalid header: %v", ErrInvalidToken, err)
+	}
+	hash, ok := jwtHashes[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported alg %s", ErrInvalidToken, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid signature: %v", ErrInvalidToken, err)
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	if !a.verify(header.Alg, header.Kid, hash, hasher.Sum(nil), signature) {
+		return nil, fmt.Errorf("%w: signature is not verified", ErrInvalidToken)
+	}
+
+	claims := make(map[string]interface{})
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: invalid claims: %v", ErrInvalidToken, err)
+	}
+	if err = a.validateClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	identity := &Identity{
+		Subject:   stringClaim(claims, "sub"),
+		AccountID: stringClaim(claims, a.opts.AccountIDClaim),
+		UserID:    stringClaim(claims, a.opts.UserIDClaim),
+		Roles:     rolesClaim(claims, a.opts.RolesClaim),
+		ClusterID: stringClaim(claims, a.opts.ClusterIDClaim),
+	}
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: sub is required", ErrInvalidToken)
+	}
+	return identity, nil
+}
+
+// verify tries keys with the kid of the header, or all keys if the header has no kid
+func (a *jwtAuthenticator) verify(alg, kid string, hash crypto.Hash, digest, signature []byte) bool {
+	for _, key := range a.keys {
+		if kid != "" && key.kid != kid {
+			continue
+		}
+		if key.alg != "" && key.alg != alg {
+			continue
+		}
+		switch publicKey := key.key.(type) {
+		case *rsa.PublicKey:
+			switch {
+			case strings.HasPrefix(alg, "RS"):
+				if rsa.VerifyPKCS1v15(publicKey, hash, digest, signature) == nil {
+					return true
+				}
+			case strings.HasPrefix(alg, "PS"):
+				if rsa.VerifyPSS(publicKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil {
+					return true
+				}
+			}
+		case *ecdsa.PublicKey:
+			if jwtCurves[alg] != publicKey.Curve {
+				continue
+			}
+			size := (publicKey.Curve.Params().BitSize + 7) / 8
+			if len(signature) != 2*size {
+				continue
+			}
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			if ecdsa.Verify(publicKey, digest, r, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *jwtAuthenticator) validateClaims(claims map[string]interface{}) error {
+	now := a.now()
+	exp, ok := timeClaim(claims, "exp")
+	if !ok {
+		return errors.New("exp is required")
+	}
+	if now.After(exp.Add(a.opts.Leeway)) {
+		return errors.New("token is expired")
+	}
+	if nbf, ok := timeClaim(claims, "nbf"); ok && now.Add(a.opts.Leeway).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+	if a.opts.Issuer != "" && stringClaim(claims, "iss") != a.opts.Issuer {
+		return errors.New("unexpected issuer")
+	}
+	if a.opts.Audience != "" && !hasAudience(claims, a.opts.Audience) {
+		return errors.New("unexpected audience")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func timeClaim(claims map[string]interface{}, name string) (time.Time, bool) {
+	number, ok := claims[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+// rolesClaim returns known roles of the claim, which is an array or a space-separated string
+func rolesClaim(claims map[string]interface{}, name string) []string {
+	var values []string
+	switch value := claims[name].(type) {
+	case string:
+		values = strings.Fields(value)
+	case []interface{}:
+		for _, item := range value {
+			if role, ok := item.(string); ok {
+				values = append(values, role)
+			}
+		}
+	}
+	roles := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := knownRoles[value]; ok {
+			roles = append(roles, value)
+		}
+	}
+	if len(roles) == 0 {
+		return defaultRoles
+	}
+	return roles
+}
+
+// hasAudience checks aud claim, which is a string or an array of strings
+func hasAudience(claims map[string]interface{}, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, item := range aud {
+			if item == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
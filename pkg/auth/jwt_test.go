@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+var now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func encodeSegment(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signRS256(key *rsa.PrivateKey, header, claims map[string]interface{}) string {
+	input := encodeSegment(header) + "." + encodeSegment(claims)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(key *ecdsa.PrivateKey, header, claims map[string]interface{}) string {
+	input := encodeSegment(header) + "." + encodeSegment(claims)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestJWTAuthenticator(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) *jwtAuthenticator {
+	g := gomega.NewWithT(t)
+	jwks := map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-01",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC",
+				"kid": "ec-01",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()),
+				"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes()),
+			},
+			{
+				"kty": "oct",
+				"kid": "hmac-01",
+			},
+		},
+	}
+	data, err := json.Marshal(jwks)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	g.Expect(os.WriteFile(path, data, 0600)).To(gomega.Succeed())
+
+	opts := NewOptions().JWT
+	opts.JWKSFile = path
+	opts.Issuer = "issuer-01"
+	opts.Audience = "vetes"
+	a, err := newJWTAuthenticator(opts)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(a.keys).To(gomega.HaveLen(2))
+	a.now = func() time.Time { return now }
+	return a
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newTestJWTAuthenticator(t, rsaKey, ecKey)
+
+	claims := func(modify func(claims map[string]interface{})) map[string]interface{} {
+		res := map[string]interface{}{
+			"sub":        "user-01",
+			"iss":        "issuer-01",
+			"aud":        []string{"other", "vetes"},
+			"exp":        now.Add(time.Hour).Unix(),
+			"account_id": "account-01",
+			"user_id":    "user-01",
+		}
+		if modify != nil {
+			modify(res)
+		}
+		return res
+	}
+	rsaHeader := map[string]interface{}{"alg": "RS256", "kid": "rsa-01"}
+
+	tests := []struct {
+		name        string
+		token       string
+		expIdentity *Identity
+	}{
+		{
+			name:        "RS256",
+			token:       signRS256(rsaKey, rsaHeader, claims(nil)),
+			expIdentity: &Identity{Subject: "user-01", AccountID: "account-01", UserID: "user-01", Roles: []string{RoleUser}},
+		},
+		{
+			name:        "ES256 without kid",
+			token:       signES256(ecKey, map[string]interface{}{"alg": "ES256"}, claims(func(c map[string]interface{}) { delete(c, "user_id") })),
+			expIdentity: &Identity{Subject: "user-01", AccountID: "account-01", Roles: []string{RoleUser}},
+		},
+		{
+			name: "roles array",
+			token: signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) {
+				c["roles"] = []string{"cluster-agent", "other"}
+				c["cluster_id"] = "cluster-01"
+			})),
+			expIdentity: &Identity{Subject: "user-01", AccountID: "account-01", UserID: "user-01", Roles: []string{RoleClusterAgent}, ClusterID: "cluster-01"},
+		},
+		{
+			name:        "roles string",
+			token:       signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) { c["roles"] = "other operator" })),
+			expIdentity: &Identity{Subject: "user-01", AccountID: "account-01", UserID: "user-01", Roles: []string{RoleOperator}},
+		},
+		{
+			name:  "unknown key",
+			token: signRS256(otherKey, rsaHeader, claims(nil)),
+		},
+		{
+			name:  "kid of another key",
+			token: signRS256(rsaKey, map[string]interface{}{"alg": "RS256", "kid": "ec-01"}, claims(nil)),
+		},
+		{
+			name:  "alg none",
+			token: encodeSegment(map[string]interface{}{"alg": "none"}) + "." + encodeSegment(claims(nil)) + ".",
+		},
+		{
+			name:  "expired",
+			token: signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) { c["exp"] = now.Add(-time.Hour).Unix() })),
+		},
+		{
+			name:  "without exp",
+			token: signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) { delete(c, "exp") })),
+		},
+		{
+			name:  "not valid yet",
+			token: signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) { c["nbf"] = now.Add(time.Hour).Unix() })),
+		},
+		{
+			name:  "unexpected issuer",
+			token: signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) { c["iss"] = "other" })),
+		},
+		{
+			name:  "unexpected audience",
+			token: signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) { c["aud"] = "other" })),
+		},
+		{
+			name:  "without sub",
+			token: signRS256(rsaKey, rsaHeader, claims(func(c map[string]interface{}) { delete(c, "sub") })),
+		},
+		{
+			name:  "not jwt",
+			token: "static-token",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			identity, err := a.Authenticate(context.TODO(), test.token)
+			if test.expIdentity == nil {
+				g.Expect(err).To(gomega.MatchError(ErrInvalidToken))
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(identity).To(gomega.Equal(test.expIdentity))
+		})
+	}
+}
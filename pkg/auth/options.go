@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Options ...
+type Options struct {
+	Enable bool `mapstructure:"enable"`
+	// TokensFile is a JSON file of static bearer tokens
+	TokensFile string      `mapstructure:"tokensFile"`
+	JWT        *JWTOptions `mapstructure:"jwt"`
+}
+
+// JWTOptions ...
+type JWTOptions struct {
+	// JWKSFile is a JSON Web Key Set file of keys to verify JWTs, JWTs are not accepted if it is empty
+	JWKSFile string `mapstructure:"jwksFile"`
+	// Issuer and Audience are checked if they are not empty
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	// AccountIDClaim and UserIDClaim are claims of the account and user the caller is bound to
+	AccountIDClaim string `mapstructure:"accountIDClaim"`
+	UserIDClaim    string `mapstructure:"userIDClaim"`
+	// RolesClaim is the claim of roles of the caller, an array or a space-separated string,
+	// unknown roles are ignored and the caller is a user if it has no known role
+	RolesClaim string `mapstructure:"rolesClaim"`
+	// ClusterIDClaim is the claim of the cluster a cluster agent is bound to
+	ClusterIDClaim string `mapstructure:"clusterIDClaim"`
+	// Leeway tolerates clock skew when checking exp and nbf
+	Leeway time.Duration `mapstructure:"leeway"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Enable: false,
+		JWT: &JWTOptions{
+			AccountIDClaim: "account_id",
+			UserIDClaim:    "user_id",
+			RolesClaim:     "roles",
+			ClusterIDClaim: "cluster_id",
+			Leeway:         time.Minute,
+		},
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if !o.Enable {
+		return nil
+	}
+	if o.TokensFile == "" && o.JWT.JWKSFile == "" {
+		return errors.New("auth tokensFile or jwt jwksFile is required")
+	}
+	if o.JWT.Leeway < 0 {
+		return errors.New("auth jwt leeway cannot be negative")
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enable, "auth-enable", o.Enable, "enable authentication of api requests by bearer tokens")
+	fs.StringVar(&o.TokensFile, "auth-tokens-file", o.TokensFile, "JSON file of static bearer tokens")
+	fs.StringVar(&o.JWT.JWKSFile, "auth-jwt-jwks-file", o.JWT.JWKSFile, "JWKS file of keys to verify JWTs")
+	fs.StringVar(&o.JWT.Issuer, "auth-jwt-issuer", o.JWT.Issuer, "expected issuer of JWTs")
+	fs.StringVar(&o.JWT.Audience, "auth-jwt-audience", o.JWT.Audience, "expected audience of JWTs")
+	fs.StringVar(&o.JWT.AccountIDClaim, "auth-jwt-account-id-claim", o.JWT.AccountIDClaim, "JWT claim of the account id of the caller")
+	fs.StringVar(&o.JWT.UserIDClaim, "auth-jwt-user-id-claim", o.JWT.UserIDClaim, "JWT claim of the user id of the caller")
+	fs.StringVar(&o.JWT.RolesClaim, "auth-jwt-roles-claim", o.JWT.RolesClaim, "JWT claim of roles of the caller")
+	fs.StringVar(&o.JWT.ClusterIDClaim, "auth-jwt-cluster-id-claim", o.JWT.ClusterIDClaim, "JWT claim of the cluster id of a cluster agent")
+	fs.DurationVar(&o.JWT.Leeway, "auth-jwt-leeway", o.JWT.Leeway, "clock skew tolerated when checking exp and nbf of JWTs")
+}
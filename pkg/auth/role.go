@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+	"github.com/GBA-BI/tes-api/pkg/utils"
+)
+
+// roles of callers
+const (
+	// RoleUser manages tasks of its own account
+	RoleUser = "user"
+	// RoleClusterAgent claims and updates tasks of its own cluster
+	RoleClusterAgent = "cluster-agent"
+	// RoleOperator manages everything
+	RoleOperator = "operator"
+)
+
+var knownRoles = map[string]struct{}{
+	RoleUser:         {},
+	RoleClusterAgent: {},
+	RoleOperator:     {},
+}
+
+// defaultRoles are roles of callers without any role
+var defaultRoles = []string{RoleUser}
+
+func validateRoles(roles []string) error {
+	for _, role := range roles {
+		if _, ok := knownRoles[role]; !ok {
+			return fmt.Errorf("unknown role %s", role)
+		}
+	}
+	return nil
+}
+
+// HasRole checks whether the caller has any of the roles, everything is allowed if the caller is not authenticated
+func (i *Identity) HasRole(roles ...string) bool {
+	if i == nil {
+		return true
+	}
+	for _, role := range roles {
+		for _, own := range i.Roles {
+			if own == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AccountScope returns the account that tasks visible to the caller are restricted to, empty means no restriction.
+// Only users are restricted, and users not bound to any account are denied.
+func (i *Identity) AccountScope() (string, error) {
+	if i.HasRole(RoleOperator, RoleClusterAgent) {
+		return "", nil
+	}
+	if i.AccountID == "" {
+		return "", apperrors.NewPermissionDeniedError("caller is not bound to any account")
+	}
+	return i.AccountID, nil
+}
+
+// ClusterScope returns the cluster that tasks updated by the caller are restricted to, empty means no restriction.
+// Only cluster agents are restricted, and cluster agents not bound to any cluster are denied.
+func (i *Identity) ClusterScope() (string, error) {
+	if i.HasRole(RoleOperator) {
+		return "", nil
+	}
+	if i.ClusterID == "" {
+		return "", apperrors.NewPermissionDeniedError("caller is not bound to any cluster")
+	}
+	return i.ClusterID, nil
+}
+
+// RequireRoles is a hertz route handler which denies callers without any of the roles
+func RequireRoles(roles ...string) app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		if !GetIdentity(c).HasRole(roles...) {
+			utils.WriteHertzErrorResponse(ctx, apperrors.NewPermissionDeniedError("caller has no role allowed by the route"))
+			ctx.Abort()
+		}
+	}
+}
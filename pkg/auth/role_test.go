@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	apperrors "github.com/GBA-BI/tes-api/pkg/errors"
+)
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		identity   *Identity
+		expAccount string
+		expCluster string
+		expAccErr  bool
+		expCluErr  bool
+	}{
+		{
+			name: "not authenticated",
+		},
+		{
+			name:     "operator",
+			identity: &Identity{Subject: "operator", AccountID: "account-01", Roles: []string{RoleOperator}},
+		},
+		{
+			name:       "cluster agent",
+			identity:   &Identity{Subject: "agent-01", Roles: []string{RoleClusterAgent}, ClusterID: "cluster-01"},
+			expCluster: "cluster-01",
+		},
+		{
+			name:      "cluster agent not bound",
+			identity:  &Identity{Subject: "agent-01", Roles: []string{RoleClusterAgent}},
+			expCluErr: true,
+		},
+		{
+			name:       "user",
+			identity:   &Identity{Subject: "user-01", AccountID: "account-01", Roles: []string{RoleUser}},
+			expAccount: "account-01",
+			expCluErr:  true,
+		},
+		{
+			name:      "user not bound",
+			identity:  &Identity{Subject: "user-01", Roles: []string{RoleUser}},
+			expAccErr: true,
+			expCluErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			accountID, err := test.identity.AccountScope()
+			if test.expAccErr {
+				g.Expect(apperrors.IsCode(err, apperrors.PermissionDeniedCode)).To(gomega.BeTrue())
+			} else {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(accountID).To(gomega.Equal(test.expAccount))
+			}
+			clusterID, err := test.identity.ClusterScope()
+			if test.expCluErr {
+				g.Expect(apperrors.IsCode(err, apperrors.PermissionDeniedCode)).To(gomega.BeTrue())
+			} else {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(clusterID).To(gomega.Equal(test.expCluster))
+			}
+		})
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	g := gomega.NewWithT(t)
+	var identity *Identity
+	g.Expect(identity.HasRole(RoleOperator)).To(gomega.BeTrue())
+	identity = &Identity{Subject: "agent-01", Roles: []string{RoleClusterAgent}}
+	g.Expect(identity.HasRole(RoleClusterAgent, RoleOperator)).To(gomega.BeTrue())
+	g.Expect(identity.HasRole(RoleUser, RoleOperator)).To(gomega.BeFalse())
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// StaticToken is an item of the tokens file
+type StaticToken struct {
+	Token     string `json:"token"`
+	Subject   string `json:"subject"`
+	AccountID string `json:"accountID,omitempty"`
+	UserID    string `json:"userID,omitempty"`
+	// Roles of the token, the token is a user if it is empty
+	Roles     []string `json:"roles,omitempty"`
+	ClusterID string   `json:"clusterID,omitempty"`
+}
+
+type tokenAuthenticator struct {
+	tokens []*StaticToken
+}
+
+func newTokenAuthenticator(path string) (*tokenAuthenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]*StaticToken, 0)
+	if err = json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid tokens file %s: %w", path, err)
+	}
+	for index, token := range tokens {
+		if token.Token == "" || token.Subject == "" {
+			return nil, fmt.Errorf("token and subject of static token %d are required", index)
+		}
+		if err = validateRoles(token.Roles); err != nil {
+			return nil, fmt.Errorf("invalid static token %d: %w", index, err)
+		}
+		if len(token.Roles) == 0 {
+			token.Roles = defaultRoles
+		}
+	}
+	return &tokenAuthenticator{tokens: tokens}, nil
+}
+
+// Authenticate compares the token with all static tokens in constant time
+func (a *tokenAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	var matched *StaticToken
+	for _, staticToken := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(staticToken.Token), []byte(token)) == 1 {
+			matched = staticToken
+		}
+	}
+	if matched == nil {
+		return nil, ErrInvalidToken
+	}
+	return &Identity{
+		Subject:   matched.Subject,
+		AccountID: matched.AccountID,
+		UserID:    matched.UserID,
+		Roles:     matched.Roles,
+		ClusterID: matched.ClusterID,
+	}, nil
+}
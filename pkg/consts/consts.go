@@ -3,6 +3,8 @@ package consts
 const (
 	// XRequestIDKey is request id key in log
 	XRequestIDKey = "X-Request-ID"
+	// AuthorizationKey is the header of bearer tokens
+	AuthorizationKey = "Authorization"
 )
 
 // api prefix
@@ -14,6 +16,12 @@ const (
 // MySQLType is mysql db type
 const MySQLType = "mysql"
 
+// PostgresType is postgres db type
+const PostgresType = "postgres"
+
+// SQLiteType is sqlite db type
+const SQLiteType = "sqlite"
+
 // task view types
 const (
 	MinimalView = "MINIMAL"
@@ -21,6 +29,12 @@ const (
 	FullView    = "FULL"
 )
 
+// batch create modes
+const (
+	AllOrNothingMode = "ALL_OR_NOTHING"
+	BestEffortMode   = "BEST_EFFORT"
+)
+
 // task state
 const (
 	TaskQueued        = "QUEUED"
@@ -31,6 +45,14 @@ const (
 	TaskExecutorError = "EXECUTOR_ERROR"
 	TaskCanceling     = "CANCELING"
 	TaskCanceled      = "CANCELED"
+	TaskPreempted     = "PREEMPTED"
+)
+
+// webhook delivery states
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
 )
 
 // GlobalQuotaID is id of global quota
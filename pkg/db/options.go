@@ -4,21 +4,26 @@ import (
 	"errors"
 
 	"github.com/spf13/pflag"
+	"gorm.io/gorm"
 
 	"github.com/GBA-BI/tes-api/pkg/consts"
 )
 
 // Options ...
 type Options struct {
-	Type  string        `mapstrucure:"type"`
-	MySQL *MySQLOptions `mapstructure:"mysql"`
+	Type     string           `mapstrucure:"type"`
+	MySQL    *MySQLOptions    `mapstructure:"mysql"`
+	Postgres *PostgresOptions `mapstructure:"postgres"`
+	SQLite   *SQLiteOptions   `mapstructure:"sqlite"`
 }
 
 // NewOptions ...
 func NewOptions() *Options {
 	return &Options{
-		Type:  consts.MySQLType,
-		MySQL: NewMySQLOptions(),
+		Type:     consts.MySQLType,
+		MySQL:    NewMySQLOptions(),
+		Postgres: NewPostgresOptions(),
+		SQLite:   NewSQLiteOptions(),
 	}
 }
 
@@ -29,6 +34,14 @@ func (o *Options) Validate() error {
 		if err := o.MySQL.Validate(); err != nil {
 			return err
 		}
+	case consts.PostgresType:
+		if err := o.Postgres.Validate(); err != nil {
+			return err
+		}
+	case consts.SQLiteType:
+		if err := o.SQLite.Validate(); err != nil {
+			return err
+		}
 	default:
 		return errors.New("invalid db type")
 	}
@@ -38,6 +51,22 @@ func (o *Options) Validate() error {
 
 // AddFlags ...
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
-	fs.StringVar(&o.Type, "db-type", o.Type, "db type")
+	fs.StringVar(&o.Type, "db-type", o.Type, "db type, mysql, postgres or sqlite")
 	o.MySQL.AddFlags(fs)
+	o.Postgres.AddFlags(fs)
+	o.SQLite.AddFlags(fs)
+}
+
+// GetGORMInstance returns the shared gorm instance of the db type
+func (o *Options) GetGORMInstance() (*gorm.DB, error) {
+	switch o.Type {
+	case consts.MySQLType:
+		return o.MySQL.GetGORMInstance()
+	case consts.PostgresType:
+		return o.Postgres.GetGORMInstance()
+	case consts.SQLiteType:
+		return o.SQLite.GetGORMInstance()
+	default:
+		return nil, errors.New("invalid db type")
+	}
 }
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// PostgresOptions ...
+type PostgresOptions struct {
+	Username        string        `mapstructure:"username"`
+	Password        string        `mapstructure:"password"`
+	Host            string        `mapstructure:"host"`
+	Port            uint16        `mapstructure:"port"`
+	Database        string        `mapstructure:"database"`
+	SSLMode         string        `mapstructure:"sslMode"`
+	MaxIdleConns    int           `mapstructure:"maxIdleConns"`
+	MaxOpenConns    int           `mapstructure:"maxOpenConns"`
+	CreateBatchSize int           `mapstructure:"createBatchSize"`
+	ConnMaxLifetime time.Duration `mapstructure:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"connMaxIdleTime"`
+}
+
+// NewPostgresOptions ...
+func NewPostgresOptions() *PostgresOptions {
+	return &PostgresOptions{
+		Port:            5432,
+		Database:        "vetes",
+		SSLMode:         "disable",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		CreateBatchSize: 1000,
+		ConnMaxLifetime: time.Hour,
+		ConnMaxIdleTime: 30 * time.Second,
+	}
+}
+
+// Validate ...
+func (o *PostgresOptions) Validate() error {
+	switch o.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		return nil
+	default:
+		return fmt.Errorf("invalid postgres sslMode %s", o.SSLMode)
+	}
+}
+
+// AddFlags ...
+func (o *PostgresOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Username, "postgres-username", o.Username, "postgres db username")
+	fs.StringVar(&o.Password, "postgres-password", o.Password, "postgres db password")
+	fs.StringVar(&o.Host, "postgres-host", "", "postgres db host")
+	fs.Uint16Var(&o.Port, "postgres-port", o.Port, "postgres db port")
+	fs.StringVar(&o.Database, "postgres-database", o.Database, "postgres database name")
+	fs.StringVar(&o.SSLMode, "postgres-ssl-mode", o.SSLMode, "postgres ssl mode")
+	fs.IntVar(&o.MaxIdleConns, "postgres-max-idle-conns", o.MaxIdleConns, "postgres max idle conns")
+	fs.IntVar(&o.MaxOpenConns, "postgres-max-open-conns", o.MaxOpenConns, "postgres max open conns")
+	fs.IntVar(&o.CreateBatchSize, "postgres-create-batch-size", o.CreateBatchSize, "postgres create batch size")
+	fs.DurationVar(&o.ConnMaxIdleTime, "postgres-conn-max-idle-time", o.ConnMaxIdleTime, "postgres conn max idle time")
+	fs.DurationVar(&o.ConnMaxLifetime, "postgres-conn-max-life-time", o.ConnMaxLifetime, "postgres conn max life time")
+}
+
+// GetGORMInstance ...
+func (o *PostgresOptions) GetGORMInstance() (*gorm.DB, error) {
+	var err error
+	once.Do(func() {
+		db, err = o.newGormInstance()
+	})
+	return db, err
+}
+
+func (o *PostgresOptions) newGormInstance() (*gorm.DB, error) {
+	dsn := (&url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(o.Username, o.Password),
+		Host:     fmt.Sprintf("%s:%d", o.Host, o.Port),
+		Path:     o.Database,
+		RawQuery: url.Values{"sslmode": []string{o.SSLMode}, "TimeZone": []string{"UTC"}}.Encode(),
+	}).String()
+
+	gormDB, err := gorm.Open(NewPostgresDialector(postgres.Config{DSN: dsn}), &gorm.Config{
+		CreateBatchSize: o.CreateBatchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return nil, err
+	}
+	// set connection pool
+	sqlDB.SetMaxIdleConns(o.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(o.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(o.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(o.ConnMaxIdleTime)
+
+	if err = sqlDB.Ping(); err != nil {
+		return nil, err
+	}
+	return gormDB, nil
+}
+
+// postgresDialector translates MySQL column types in gorm tags of persistent objects into PostgreSQL ones,
+// so that the same models are migrated by both dialects
+type postgresDialector struct {
+	*postgres.Dialector
+}
+
+// NewPostgresDialector ...
+func NewPostgresDialector(config postgres.Config) gorm.Dialector {
+	return &postgresDialector{Dialector: postgres.New(config).(*postgres.Dialector)}
+}
+
+// Migrator uses the dialector itself to get data types of fields
+func (d *postgresDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	m := d.Dialector.Migrator(db).(postgres.Migrator)
+	m.Dialector = d
+	return m
+}
+
+// DataTypeOf ...
+func (d *postgresDialector) DataTypeOf(field *schema.Field) string {
+	switch strings.ToUpper(string(field.DataType)) {
+	case "LONGTEXT":
+		return "text"
+	case "DATETIME":
+		return "timestamp"
+	case "DOUBLE":
+		return "float8"
+	case "BIGINT":
+		if field.AutoIncrement {
+			return "bigserial"
+		}
+	case "INT":
+		if field.AutoIncrement {
+			return "serial"
+		}
+	}
+	return d.Dialector.DataTypeOf(field)
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SQLiteMemoryPath is the path of an in-memory sqlite db
+const SQLiteMemoryPath = ":memory:"
+
+// SQLiteOptions ...
+type SQLiteOptions struct {
+	// Path is the db file path, or ":memory:" for an in-memory db
+	Path            string `mapstructure:"path"`
+	CreateBatchSize int    `mapstructure:"createBatchSize"`
+}
+
+// NewSQLiteOptions ...
+func NewSQLiteOptions() *SQLiteOptions {
+	return &SQLiteOptions{
+		Path:            "vetes.db",
+		CreateBatchSize: 100,
+	}
+}
+
+// Validate ...
+func (o *SQLiteOptions) Validate() error {
+	if o.Path == "" {
+		return errors.New("sqlite path cannot be empty")
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *SQLiteOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Path, "sqlite-path", o.Path, "sqlite db file path, or :memory: for an in-memory db")
+	fs.IntVar(&o.CreateBatchSize, "sqlite-create-batch-size", o.CreateBatchSize, "sqlite create batch size")
+}
+
+// GetGORMInstance ...
+func (o *SQLiteOptions) GetGORMInstance() (*gorm.DB, error) {
+	var err error
+	once.Do(func() {
+		db, err = o.NewGORMInstance()
+	})
+	return db, err
+}
+
+// NewGORMInstance opens a new sqlite db, unlike GetGORMInstance it is not shared,
+// so that every in-memory db is isolated.
+func (o *SQLiteOptions) NewGORMInstance() (*gorm.DB, error) {
+	dsn := o.Path
+	if dsn != SQLiteMemoryPath {
+		dsn = "file:" + dsn + "?_busy_timeout=5000&_journal_mode=WAL"
+	}
+
+	gormDB, err := gorm.Open(NewSQLiteDialector(dsn), &gorm.Config{
+		CreateBatchSize: o.CreateBatchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return nil, err
+	}
+	// sqlite allows a single writer, and every connection to ":memory:" opens a new db,
+	// so all queries share one connection which is never closed
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	if err = sqlDB.Ping(); err != nil {
+		return nil, err
+	}
+	return gormDB, nil
+}
+
+// sqliteDialector makes auto increment columns with MySQL types in gorm tags of persistent objects
+// alias of rowid, and tolerates index names shared by tables, which are global in sqlite
+type sqliteDialector struct {
+	*sqlite.Dialector
+}
+
+// NewSQLiteDialector ...
+func NewSQLiteDialector(dsn string) gorm.Dialector {
+	return &sqliteDialector{Dialector: sqlite.Open(dsn).(*sqlite.Dialector)}
+}
+
+// Migrator uses the dialector itself to get data types of fields
+func (d *sqliteDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	m := d.Dialector.Migrator(db).(sqlite.Migrator)
+	m.Dialector = d
+	return sqliteMigrator{Migrator: m}
+}
+
+// DataTypeOf ...
+func (d *sqliteDialector) DataTypeOf(field *schema.Field) string {
+	if field.AutoIncrement {
+		return "integer"
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+type sqliteMigrator struct {
+	sqlite.Migrator
+}
+
+// CreateIndex skips indexes whose name is taken by another table, index names are global
+// in sqlite but per table in mysql
+func (m sqliteMigrator) CreateIndex(value interface{}, name string) error {
+	var count int
+	if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+		return m.DB.Raw("SELECT count(*) FROM sqlite_master WHERE type = ? AND name = ?", "index", name).
+			Row().Scan(&count)
+	}); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return m.Migrator.CreateIndex(value, name)
+}
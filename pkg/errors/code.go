@@ -6,6 +6,9 @@ const (
 	NotFoundCode
 	CannotExecCode
 	InternalCode
+	QuotaExceededCode
+	UnauthenticatedCode
+	PermissionDeniedCode
 )
 
 // hertz code.
@@ -67,6 +67,30 @@ func NewInternalError(err error) *AppError {
 	}
 }
 
+// NewQuotaExceededError ...
+func NewQuotaExceededError(resources ...string) *AppError {
+	return &AppError{
+		Code:    QuotaExceededCode,
+		Message: fmt.Sprintf("quota exceeded: %v", resources),
+	}
+}
+
+// NewUnauthenticatedError ...
+func NewUnauthenticatedError(msg string) *AppError {
+	return &AppError{
+		Code:    UnauthenticatedCode,
+		Message: msg,
+	}
+}
+
+// NewPermissionDeniedError ...
+func NewPermissionDeniedError(msg string) *AppError {
+	return &AppError{
+		Code:    PermissionDeniedCode,
+		Message: msg,
+	}
+}
+
 // NewHertzRouteNotFoundError ...
 func NewHertzRouteNotFoundError(ctx *app.RequestContext) *AppError {
 	return &AppError{
@@ -0,0 +1,296 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	applog "github.com/GBA-BI/tes-api/pkg/log"
+)
+
+// Migration is a numbered schema change, migrations are applied in order of versions
+type Migration struct {
+	Version int64
+	Name    string
+	// Up applies the change, it runs in a transaction with the record of the migration,
+	// but DDL is committed implicitly by mysql, so it should be safe to run again
+	Up func(tx *gorm.DB) error
+	// Down reverts the change, nil if the migration is irreversible
+	Down func(tx *gorm.DB) error
+}
+
+// Status is a known migration and its record, Record is nil if the migration is pending
+type Status struct {
+	*Migration
+	Record *Record
+}
+
+// ErrSchemaBehind is returned by Check if any migration is pending
+var ErrSchemaBehind = errors.New("db schema is behind")
+
+// ErrLockTimeout is returned if the migration lock is held by another migrator until timeout
+var ErrLockTimeout = errors.New("timeout to acquire migration lock")
+
+// Record is an applied migration
+type Record struct {
+	Version     int64     `gorm:"column:version;type:BIGINT;not null;primaryKey;autoIncrement:false"`
+	Name        string    `gorm:"column:name;type:VARCHAR(128);not null;default:''"`
+	AppliedTime time.Time `gorm:"column:applied_time;type:DATETIME;not null"`
+}
+
+// TableName ...
+func (r *Record) TableName() string {
+	return "schema_migration"
+}
+
+// lock is the only row of the lock table, migrators take it by setting the owner
+type lock struct {
+	ID       int64      `gorm:"column:id;type:BIGINT;not null;primaryKey;autoIncrement:false"`
+	Owner    string     `gorm:"column:owner;type:VARCHAR(128);not null;default:''"`
+	LockTime *time.Time `gorm:"column:lock_time;type:DATETIME"`
+}
+
+// TableName ...
+func (l *lock) TableName() string {
+	return "schema_migration_lock"
+}
+
+const lockID = 1
+
+// lockPollInterval is replaceable in tests
+var lockPollInterval = time.Second
+
+// Migrator applies and reverts migrations, only one migrator changes the schema at a time
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+	opts       *Options
+	owner      string
+}
+
+// NewMigrator ...
+func NewMigrator(db *gorm.DB, migrations []*Migration, opts *Options) (*Migrator, error) {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i, migration := range sorted {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("invalid version %d of migration %s", migration.Version, migration.Name)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("duplicated migration version %d", migration.Version)
+		}
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %d has no up", migration.Version)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		opts:       opts,
+		owner:      fmt.Sprintf("%s/%s", hostname, uuid.New().String()),
+	}, nil
+}
+
+// Status lists known migrations in order
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		res = append(res, &Status{Migration: migration, Record: records[migration.Version]})
+	}
+	return res, nil
+}
+
+// Check returns ErrSchemaBehind if any migration is pending,
+// migrations applied by a newer version are ignored
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending := make([]int64, 0)
+	for _, status := range statuses {
+		if status.Record == nil {
+			pending = append(pending, status.Version)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w, pending migrations: %v", ErrSchemaBehind, pending)
+	}
+	return nil
+}
+
+// Up applies pending migrations up to the version, 0 means the latest
+func (m *Migrator) Up(ctx context.Context, to int64) error {
+	return m.withLock(ctx, func(db *gorm.DB) error {
+		records, err := m.records(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if to > 0 && migration.Version > to {
+				break
+			}
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+			applog.Infow("apply migration", "version", migration.Version, "name", migration.Name)
+			if err = db.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&Record{Version: migration.Version, Name: migration.Name, AppliedTime: time.Now()}).Error
+			}); err != nil {
+				return fmt.Errorf("failed to apply migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down reverts applied migrations after the version in reverse order, 0 reverts all
+func (m *Migrator) Down(ctx context.Context, to int64) error {
+	return m.withLock(ctx, func(db *gorm.DB) error {
+		records, err := m.records(ctx)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= to {
+				break
+			}
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("migration %d %s is irreversible", migration.Version, migration.Name)
+			}
+			applog.Infow("revert migration", "version", migration.Version, "name", migration.Name)
+			if err = db.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&Record{}, migration.Version).Error
+			}); err != nil {
+				return fmt.Errorf("failed to revert migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// records returns applied migrations by version, the schema is untouched if the record table does not exist
+func (m *Migrator) records(ctx context.Context) (map[int64]*Record, error) {
+	db := m.db.WithContext(ctx)
+	res := make(map[int64]*Record)
+	if !db.Migrator().HasTable(&Record{}) {
+		return res, nil
+	}
+	records := make([]*Record, 0)
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		res[record.Version] = record
+	}
+	return res, nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&Record{}, &lock{}); err != nil {
+		// another migrator may create the tables at the same time
+		if err = db.AutoMigrate(&Record{}, &lock{}); err != nil {
+			return err
+		}
+	}
+	if err := m.acquireLock(ctx, db); err != nil {
+		return err
+	}
+	defer func() {
+		// release the lock even if ctx is canceled
+		if err := m.releaseLock(m.db.WithContext(context.Background())); err != nil {
+			applog.Errorw("failed to release migration lock", "err", err)
+		}
+	}()
+	defer m.keepLock(m.db.WithContext(context.Background()))()
+	return fn(db)
+}
+
+func (m *Migrator) acquireLock(ctx context.Context, db *gorm.DB) error {
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock{ID: lockID}).Error; err != nil {
+		return err
+	}
+	deadline := time.Now().Add(m.opts.LockTimeout)
+	for {
+		now := time.Now()
+		res := db.Model(&lock{}).Where("id = ? AND (owner = '' OR lock_time < ?)", lockID, now.Add(-m.opts.LockTTL)).
+			Updates(map[string]interface{}{"owner": m.owner, "lock_time": now})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+		if now.After(deadline) {
+			return ErrLockTimeout
+		}
+		applog.Infow("wait for migration lock")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// keepLock renews the lock in background every third of its TTL until the returned function is called,
+// so that a long migration does not make the lock stale
+func (m *Migrator) keepLock(db *gorm.DB) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(m.opts.LockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := m.renewLock(db); err != nil {
+					applog.Errorw("failed to renew migration lock", "err", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+func (m *Migrator) renewLock(db *gorm.DB) error {
+	return db.Model(&lock{}).Where("id = ? AND owner = ?", lockID, m.owner).Update("lock_time", time.Now()).Error
+}
+
+func (m *Migrator) releaseLock(db *gorm.DB) error {
+	return db.Model(&lock{}).Where("id = ? AND owner = ?", lockID, m.owner).
+		Updates(map[string]interface{}{"owner": "", "lock_time": nil}).Error
+}
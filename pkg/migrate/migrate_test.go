@@ -0,0 +1,215 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/pkg/testutil"
+)
+
+type table1 struct {
+	ID string `gorm:"column:id;type:VARCHAR(16);not null;primaryKey"`
+}
+
+func (t *table1) TableName() string {
+	return "table1"
+}
+
+type table2 struct {
+	ID string `gorm:"column:id;type:VARCHAR(16);not null;primaryKey"`
+}
+
+func (t *table2) TableName() string {
+	return "table2"
+}
+
+func createTableMigration(version int64, table interface{}) *Migration {
+	return &Migration{
+		Version: version,
+		Name:    "create table",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(table)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(table)
+		},
+	}
+}
+
+func TestNewMigrator(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []*Migration
+		expErr     bool
+	}{
+		{
+			name:       "normal",
+			migrations: []*Migration{createTableMigration(2, &table2{}), createTableMigration(1, &table1{})},
+		},
+		{
+			name:       "invalid version",
+			migrations: []*Migration{createTableMigration(0, &table1{})},
+			expErr:     true,
+		},
+		{
+			name:       "duplicated version",
+			migrations: []*Migration{createTableMigration(1, &table1{}), createTableMigration(1, &table2{})},
+			expErr:     true,
+		},
+		{
+			name:       "no up",
+			migrations: []*Migration{{Version: 1, Name: "no up"}},
+			expErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			m, err := NewMigrator(testutil.NewSQLiteDB(), test.migrations, NewOptions())
+			if test.expErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.migrations[0].Version).To(gomega.Equal(int64(1)))
+			g.Expect(m.migrations[1].Version).To(gomega.Equal(int64(2)))
+		})
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	m, err := NewMigrator(db, []*Migration{createTableMigration(1, &table1{}), createTableMigration(2, &table2{})}, NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// check does not create the tables of migrator
+	g.Expect(errors.Is(m.Check(context.TODO()), ErrSchemaBehind)).To(gomega.BeTrue())
+	g.Expect(db.Migrator().HasTable(&Record{})).To(gomega.BeFalse())
+
+	g.Expect(m.Up(context.TODO(), 1)).To(gomega.Succeed())
+	g.Expect(db.Migrator().HasTable(&table1{})).To(gomega.BeTrue())
+	g.Expect(db.Migrator().HasTable(&table2{})).To(gomega.BeFalse())
+	g.Expect(errors.Is(m.Check(context.TODO()), ErrSchemaBehind)).To(gomega.BeTrue())
+
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	g.Expect(db.Migrator().HasTable(&table2{})).To(gomega.BeTrue())
+	g.Expect(m.Check(context.TODO())).To(gomega.Succeed())
+	// applied migrations are skipped
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+
+	statuses, err := m.Status(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(statuses).To(gomega.HaveLen(2))
+	g.Expect(statuses[0].Record).NotTo(gomega.BeNil())
+	g.Expect(statuses[1].Record).NotTo(gomega.BeNil())
+
+	g.Expect(m.Down(context.TODO(), 1)).To(gomega.Succeed())
+	g.Expect(db.Migrator().HasTable(&table1{})).To(gomega.BeTrue())
+	g.Expect(db.Migrator().HasTable(&table2{})).To(gomega.BeFalse())
+	statuses, err = m.Status(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(statuses[1].Record).To(gomega.BeNil())
+
+	g.Expect(m.Down(context.TODO(), 0)).To(gomega.Succeed())
+	g.Expect(db.Migrator().HasTable(&table1{})).To(gomega.BeFalse())
+}
+
+func TestUpFailed(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	failed := &Migration{
+		Version: 2,
+		Name:    "failed",
+		Up: func(tx *gorm.DB) error {
+			return errors.New("failed")
+		},
+	}
+	m, err := NewMigrator(db, []*Migration{createTableMigration(1, &table1{}), failed}, NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(m.Up(context.TODO(), 0)).NotTo(gomega.Succeed())
+	statuses, err := m.Status(context.TODO())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(statuses[0].Record).NotTo(gomega.BeNil())
+	g.Expect(statuses[1].Record).To(gomega.BeNil())
+
+	// the lock is released, and the irreversible migration is not applied
+	g.Expect(m.Down(context.TODO(), 0)).To(gomega.Succeed())
+	g.Expect(db.Migrator().HasTable(&table1{})).To(gomega.BeFalse())
+}
+
+func TestDownIrreversible(t *testing.T) {
+	g := gomega.NewWithT(t)
+	irreversible := createTableMigration(1, &table1{})
+	irreversible.Down = nil
+	m, err := NewMigrator(testutil.NewSQLiteDB(), []*Migration{irreversible}, NewOptions())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	g.Expect(m.Down(context.TODO(), 0)).NotTo(gomega.Succeed())
+	g.Expect(m.Check(context.TODO())).To(gomega.Succeed())
+}
+
+func TestLock(t *testing.T) {
+	lockPollInterval = time.Millisecond
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	opts := NewOptions()
+	opts.LockTimeout = 10 * time.Millisecond
+	opts.LockTTL = time.Minute
+	m, err := NewMigrator(db, []*Migration{createTableMigration(1, &table1{})}, opts)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(db.AutoMigrate(&Record{}, &lock{})).To(gomega.Succeed())
+	lockTime := time.Now()
+	g.Expect(db.Create(&lock{ID: lockID, Owner: "other", LockTime: &lockTime}).Error).To(gomega.Succeed())
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.MatchError(ErrLockTimeout))
+	g.Expect(db.Migrator().HasTable(&table1{})).To(gomega.BeFalse())
+
+	// the lock held by the other migrator is stale
+	g.Expect(db.Model(&lock{}).Where("id = ?", lockID).Update("lock_time", lockTime.Add(-2*time.Minute)).Error).To(gomega.Succeed())
+	g.Expect(m.Up(context.TODO(), 0)).To(gomega.Succeed())
+	g.Expect(db.Migrator().HasTable(&table1{})).To(gomega.BeTrue())
+
+	l := &lock{}
+	g.Expect(db.First(l, lockID).Error).To(gomega.Succeed())
+	g.Expect(l.Owner).To(gomega.BeEmpty())
+	g.Expect(l.LockTime).To(gomega.BeNil())
+}
+
+func TestKeepLock(t *testing.T) {
+	g := gomega.NewWithT(t)
+	db := testutil.NewSQLiteDB()
+	opts := NewOptions()
+	opts.LockTTL = 30 * time.Millisecond
+	m, err := NewMigrator(db, nil, opts)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(db.AutoMigrate(&Record{}, &lock{})).To(gomega.Succeed())
+	g.Expect(m.acquireLock(context.TODO(), db)).To(gomega.Succeed())
+	l := &lock{}
+	g.Expect(db.First(l, lockID).Error).To(gomega.Succeed())
+	lockTime := *l.LockTime
+
+	stop := m.keepLock(db)
+	// the lock is renewed while a migration takes longer than the TTL
+	g.Eventually(func() time.Time {
+		l := &lock{}
+		g.Expect(db.First(l, lockID).Error).To(gomega.Succeed())
+		return *l.LockTime
+	}, time.Second, 5*time.Millisecond).Should(gomega.BeTemporally(">", lockTime))
+	stop()
+
+	g.Expect(db.First(l, lockID).Error).To(gomega.Succeed())
+	lockTime = *l.LockTime
+	time.Sleep(3 * opts.LockTTL)
+	g.Expect(db.First(l, lockID).Error).To(gomega.Succeed())
+	g.Expect(*l.LockTime).To(gomega.BeTemporally("==", lockTime))
+}
@@ -0,0 +1,47 @@
+package migrate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Options ...
+type Options struct {
+	// Auto migrates the schema up at startup instead of refusing to serve if it is behind,
+	// it is meant for single-node and local development, e.g. an in-memory sqlite db
+	Auto bool `mapstructure:"auto"`
+	// LockTimeout is how long to wait for the migration lock held by another migrator
+	LockTimeout time.Duration `mapstructure:"lockTimeout"`
+	// LockTTL is how long the lock is held without renewal before it is considered stale,
+	// the lock is renewed every third of it while migrating
+	LockTTL time.Duration `mapstructure:"lockTTL"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Auto:        false,
+		LockTimeout: 5 * time.Minute,
+		LockTTL:     10 * time.Minute,
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	if o.LockTimeout <= 0 {
+		return errors.New("migrate lockTimeout must be positive")
+	}
+	if o.LockTTL <= 0 {
+		return errors.New("migrate lockTTL must be positive")
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Auto, "migrate-auto", o.Auto, "migrate the db schema up at startup instead of refusing to serve if it is behind")
+	fs.DurationVar(&o.LockTimeout, "migrate-lock-timeout", o.LockTimeout, "how long to wait for the migration lock")
+	fs.DurationVar(&o.LockTTL, "migrate-lock-ttl", o.LockTTL, "how long the migration lock is held without renewal before it is stale")
+}
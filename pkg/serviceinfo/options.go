@@ -0,0 +1,35 @@
+package serviceinfo
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// Options ...
+type Options struct {
+	// Storage is the supported storage url schemes, such as s3://
+	Storage []string `mapstructure:"storage"`
+}
+
+// NewOptions ...
+func NewOptions() *Options {
+	return &Options{
+		Storage: []string{"s3://"},
+	}
+}
+
+// Validate ...
+func (o *Options) Validate() error {
+	for _, storage := range o.Storage {
+		if storage == "" {
+			return fmt.Errorf("serviceInfo storage should not be empty")
+		}
+	}
+	return nil
+}
+
+// AddFlags ...
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.Storage, "service-info-storage", o.Storage, "supported storage url schemes in service-info")
+}
@@ -2,14 +2,17 @@ package serviceinfo
 
 import "github.com/GBA-BI/tes-api/pkg/version"
 
+// TESVersion is the version of TES API implemented
+const TESVersion = "1.1.0"
+
 // DefaultServiceInfo ...
 var DefaultServiceInfo = ServiceInfo{
 	ID:   "com.volcengine.bioos.veTES",
 	Name: "veTES-api",
 	Type: TypeInfo{
-		Group:    "com.volcengine.bioos",
-		Artifact: "TES",
-		Version:  version.Get().Version,
+		Group:    "org.ga4gh",
+		Artifact: "tes",
+		Version:  TESVersion,
 	},
 	Organization: OrganizationInfo{
 		Name: "volcengine",
@@ -32,6 +35,29 @@ type ServiceInfo struct {
 	Environment      string           `json:"environment,omitempty"`
 	Version          string           `json:"version"`
 	Storage          []string         `json:"storage,omitempty"`
+
+	TESResourcesBackendParameters []string       `json:"tesResources_backend_parameters,omitempty"`
+	Resources                     *ResourcesInfo `json:"resources,omitempty"`
+}
+
+// ResourcesInfo shows how resources of tasks are normalized
+type ResourcesInfo struct {
+	DiskGB     *RangeInfo `json:"diskGB,omitempty"`
+	BootDiskGB *RangeInfo `json:"bootDiskGB,omitempty"`
+	GPU        *GPUInfo   `json:"gpu,omitempty"`
+}
+
+// RangeInfo ...
+type RangeInfo struct {
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	IsInteger bool    `json:"isInteger"`
+}
+
+// GPUInfo ...
+type GPUInfo struct {
+	IsInteger bool     `json:"isInteger"`
+	Types     []string `json:"types,omitempty"`
 }
 
 // TypeInfo ...
@@ -1,28 +1,87 @@
 package testutil
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
+	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+
+	"github.com/GBA-BI/tes-api/pkg/consts"
+	"github.com/GBA-BI/tes-api/pkg/db"
 )
 
+// Dialect is the db type sql mocks are created with. Expected sql is always
+// written in MySQL style, postgres sql is translated to it before matching.
+var Dialect = consts.MySQLType
+
+// RunDialects runs the tests of a package once for every supported db type,
+// it is meant to be called in TestMain.
+func RunDialects(m *testing.M) {
+	for _, dialect := range []string{consts.MySQLType, consts.PostgresType} {
+		Dialect = dialect
+		if code := m.Run(); code != 0 {
+			os.Exit(code)
+		}
+	}
+	os.Exit(0)
+}
+
 // NewSqlMock ...
 func NewSqlMock() (sqlmock.Sqlmock, *gorm.DB) {
-	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	sqlDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(matchQuery)))
 	if err != nil {
 		panic(err)
 	}
 
-	gormDB, err := gorm.Open(mysql.New(mysql.Config{Conn: db, SkipInitializeWithVersion: true}))
+	var dialector gorm.Dialector
+	switch Dialect {
+	case consts.PostgresType:
+		dialector = db.NewPostgresDialector(postgres.Config{Conn: sqlDB})
+	default:
+		dialector = mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true})
+	}
+	gormDB, err := gorm.Open(dialector)
 	if err != nil {
 		panic(err)
 	}
 	return mock, gormDB
 }
 
+// NewSQLiteDB opens an isolated in-memory sqlite db, it is used by integration tests
+// which run queries against a real db instead of sqlmock expectations.
+func NewSQLiteDB() *gorm.DB {
+	gormDB, err := (&db.SQLiteOptions{Path: db.SQLiteMemoryPath}).NewGORMInstance()
+	if err != nil {
+		panic(err)
+	}
+	return gormDB
+}
+
+var (
+	spaceRegexp       = regexp.MustCompile(`\s+`)
+	placeholderRegexp = regexp.MustCompile(`\$\d+`)
+)
+
+func matchQuery(expectedSQL, actualSQL string) error {
+	actual := actualSQL
+	if Dialect == consts.PostgresType {
+		actual = placeholderRegexp.ReplaceAllString(strings.ReplaceAll(actual, `"`, "`"), "?")
+	}
+	expect := strings.TrimSpace(spaceRegexp.ReplaceAllString(expectedSQL, " "))
+	actual = strings.TrimSpace(spaceRegexp.ReplaceAllString(actual, " "))
+	if actual != expect {
+		return fmt.Errorf(`actual sql: "%s" does not equal to expected "%s"`, actual, expect)
+	}
+	return nil
+}
+
 // GenInsertSql ...
 func GenInsertSql(rows []string) string {
 	names := make([]string, 0, len(rows))
@@ -53,14 +112,44 @@ func GenUpdateSql(rows []string) string {
 }
 
 // GenDuplicateKeySql ...
-func GenDuplicateKeySql(rows []string) string {
+func GenDuplicateKeySql(keys []string, rows []string) string {
 	items := make([]string, 0, len(rows))
 	for _, row := range rows {
-		items = append(items, fmt.Sprintf("`%s`=VALUES(`%s`)", row, row))
+		if Dialect == consts.PostgresType {
+			items = append(items, fmt.Sprintf("`%s`=`excluded`.`%s`", row, row))
+		} else {
+			items = append(items, fmt.Sprintf("`%s`=VALUES(`%s`)", row, row))
+		}
+	}
+	if Dialect == consts.PostgresType {
+		names := make([]string, 0, len(keys))
+		for _, key := range keys {
+			names = append(names, fmt.Sprintf("`%s`", key))
+		}
+		return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(names, ","), strings.Join(items, ","))
 	}
 	return fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", strings.Join(items, ","))
 }
 
+// GenDoNothingSql ...
+func GenDoNothingSql(row string) string {
+	if Dialect == consts.PostgresType {
+		return "ON CONFLICT DO NOTHING"
+	}
+	return fmt.Sprintf("ON DUPLICATE KEY UPDATE `%s`=`%s`", row, row)
+}
+
+// ExpectInsertWithID expects an insert into a table with auto increment id,
+// which postgres returns by RETURNING instead of the last insert id.
+func ExpectInsertWithID(mock sqlmock.Sqlmock, sql string, id int64, args ...driver.Value) {
+	if Dialect == consts.PostgresType {
+		mock.ExpectQuery(sql + " RETURNING `id`").WithArgs(args...).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+		return
+	}
+	mock.ExpectExec(sql).WithArgs(args...).WillReturnResult(sqlmock.NewResult(id, 1))
+}
+
 // NewCountRows ...
 func NewCountRows(count int) *sqlmock.Rows {
 	return sqlmock.NewRows([]string{"count"}).AddRow(count)
@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/base64"
 	"encoding/json"
+	"time"
 
 	applog "github.com/GBA-BI/tes-api/pkg/log"
 
@@ -12,6 +13,10 @@ import (
 // PageToken ...
 type PageToken struct {
 	LastID string `json:"last_id"`
+	// LastEffectivePriority and LastCreationTime locate the last task
+	// when tasks are sorted by priority
+	LastEffectivePriority *int       `json:"last_effective_priority,omitempty"`
+	LastCreationTime      *time.Time `json:"last_creation_time,omitempty"`
 }
 
 // GenPageToken ...
@@ -23,6 +23,12 @@ func WriteHertzErrorResponse(c *app.RequestContext, err error) {
 		c.JSON(http.StatusBadRequest, appError.Message)
 	case apperrors.NotFoundCode, apperrors.RouteNotFoundCode:
 		c.JSON(http.StatusNotFound, appError.Message)
+	case apperrors.QuotaExceededCode:
+		c.JSON(http.StatusTooManyRequests, appError.Message)
+	case apperrors.UnauthenticatedCode:
+		c.JSON(http.StatusUnauthorized, appError.Message)
+	case apperrors.PermissionDeniedCode:
+		c.JSON(http.StatusForbidden, appError.Message)
 	case apperrors.InternalCode:
 		c.JSON(http.StatusInternalServerError, appError.Message)
 	default:
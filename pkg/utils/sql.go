@@ -2,9 +2,14 @@ package utils
 
 import "strings"
 
+// LikeEscapeChar is the escape character of LIKE patterns, it is declared by ESCAPE
+// because sqlite has no default escape character and backslash is quoted differently by dialects
+const LikeEscapeChar = "!"
+
 // EscapeLikeSpecialChars ...
 func EscapeLikeSpecialChars(s string) string {
-	s = strings.ReplaceAll(s, "%", "\\%")
-	s = strings.ReplaceAll(s, "_", "\\_")
+	s = strings.ReplaceAll(s, LikeEscapeChar, LikeEscapeChar+LikeEscapeChar)
+	s = strings.ReplaceAll(s, "%", LikeEscapeChar+"%")
+	s = strings.ReplaceAll(s, "_", LikeEscapeChar+"_")
 	return s
 }
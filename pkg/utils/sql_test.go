@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestEscapeLikeSpecialChars(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	tests := []struct {
+		name string
+		s    string
+		exp  string
+	}{
+		{
+			name: "plain",
+			s:    "task-abcd",
+			exp:  "task-abcd",
+		},
+		{
+			name: "special",
+			s:    "task%1_1!",
+			exp:  "task!%1!_1!!",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g.Expect(EscapeLikeSpecialChars(test.s)).To(gomega.Equal(test.exp))
+		})
+	}
+}